@@ -0,0 +1,16 @@
+package app
+
+// infoActivityLimit caps the history lines shown in the task info view.
+const infoActivityLimit = 8
+
+// openInfo shows the info view, loading the selected task's history from
+// the activity log once rather than on every render.
+func (m *model) openInfo() {
+	m.ui.InfoActivity = nil
+	if task, _, ok := m.selectedTask(); ok && m.deps.Activity != nil {
+		if events, err := m.deps.Activity.Task(m.project.ID, task.ID); err == nil {
+			m.ui.InfoActivity = events
+		}
+	}
+	m.ui.Modes.ToInfo()
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
@@ -33,6 +34,7 @@ type focusPosition struct {
 	Kind          focusKind
 	CategoryIndex int
 	TaskIndex     int
+	SubtaskPath   []int // subtask indexes below Tasks[TaskIndex]; nil for top-level tasks
 }
 
 type model struct {
@@ -42,7 +44,12 @@ type model struct {
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	var cmds []tea.Cmd
+	if m.timerRunning() {
+		cmds = append(cmds, timerTick())
+	}
+	cmds = append(cmds, m.deps.Watcher.Wait())
+	return tea.Batch(cmds...)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -59,7 +66,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case editorFinishedMsg:
 		m.handleEditorFinished(msg)
+		if m.ui.ReloadPending {
+			m.reloadFromDisk()
+		}
 		return m, nil
+	case projectFileChangedMsg:
+		if msg.changed(m.project.ID) {
+			m.reloadFromDisk()
+		}
+		// A timer may have been started or stopped in another project.
+		hadTimer := m.timerRunning()
+		if msg.changedOtherThan(m.project.ID) {
+			m.refreshOtherTimer()
+		}
+		if !hadTimer && m.timerRunning() {
+			return m, tea.Batch(m.deps.Watcher.Wait(), timerTick())
+		}
+		return m, m.deps.Watcher.Wait()
+	case timerTickMsg:
+		if m.timerRunning() {
+			return m, timerTick()
+		}
 	case tea.FocusMsg:
 		m.ui.WindowFocused = true
 	case tea.BlurMsg:
@@ -81,7 +108,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case tea.KeyMsg:
 		m.ui.StatusMsg = ""
-		return m.handleKeyMsg(msg)
+		next, cmd := m.handleKeyMsg(msg)
+		if updated, ok := next.(model); ok && m.ui.ReloadPending {
+			updated.reloadFromDisk()
+			next = updated
+		}
+		return next, cmd
 	default:
 		return m.forwardToInput(msg)
 	}
@@ -104,6 +136,12 @@ func (m model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleInfoKey(msg), nil
 	case modes.ModeEstimatePicker:
 		return m.handleEstimatePickerKey(msg), nil
+	case modes.ModeDeadlinePicker:
+		return m.handleDeadlinePickerKey(msg), nil
+	case modes.ModeTrash:
+		return m.handleTrashKey(msg), nil
+	case modes.ModeArchive:
+		return m.handleArchiveKey(msg), nil
 	case modes.ModeEdit:
 		cmd := m.handleEditKey(msg)
 		return m, cmd
@@ -197,6 +235,21 @@ func (m model) handleEstimatePickerKey(msg tea.KeyMsg) model {
 	return m
 }
 
+func (m model) handleDeadlinePickerKey(msg tea.KeyMsg) model {
+	switch msg.String() {
+	case "q", "esc":
+		m.ui.Modes.ToNormal()
+	case "j", "down":
+		m.ui.DeadlinePicker.MoveDown()
+	case "k", "up":
+		m.ui.DeadlinePicker.MoveUp()
+	case "enter":
+		m.selectDeadline(m.ui.DeadlinePicker.SelectedValue())
+		m.ui.Modes.ToNormal()
+	}
+	return m
+}
+
 func (m *model) toggleSelectedOption() {
 	switch m.ui.Options.selectedOption {
 	case 0: // StatusDisplay
@@ -330,17 +383,51 @@ func (m model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.sortTasksByStatusReverse()
 		m.ui.PendingKey = 0
 	case "f":
+		m.ui.Filter.SetAvailableTags(m.project.Tags())
 		m.ui.Modes.ToFilter()
 		m.ui.PendingKey = 0
 	case "e":
 		m.ui.PendingKey = 0
 		return m, m.startExternalEdit()
 	case "i":
-		m.ui.Modes.ToInfo()
+		m.openInfo()
 		m.ui.PendingKey = 0
 	case "t":
 		m.openEstimatePicker()
 		m.ui.PendingKey = 0
+	case "D":
+		m.openDeadlinePicker()
+		m.ui.PendingKey = 0
+	case "v":
+		m.cycleSectionView()
+		m.ui.PendingKey = 0
+	case "+":
+		m.startAddingSubtask()
+		m.ui.PendingKey = 0
+	case ">":
+		m.indentTask()
+		m.ui.PendingKey = 0
+	case "<":
+		m.outdentTask()
+		m.ui.PendingKey = 0
+	case "m":
+		m.moveTaskSection()
+		m.ui.PendingKey = 0
+	case "T":
+		m.ui.PendingKey = 0
+		return m, m.toggleTimer()
+	case "u":
+		m.undo()
+		m.ui.PendingKey = 0
+	case "ctrl+r":
+		m.redo()
+		m.ui.PendingKey = 0
+	case "X":
+		m.openTrash()
+		m.ui.PendingKey = 0
+	case "Z":
+		m.openArchive()
+		m.ui.PendingKey = 0
 	case "}":
 		m.jumpToNextCategory()
 		m.ui.PendingKey = 0
@@ -365,9 +452,12 @@ func (m *model) copySelected() tea.Cmd {
 	case focusCategory:
 		text = m.project.Categories[pos.CategoryIndex].Name
 	case focusTask:
-		task := m.project.Categories[pos.CategoryIndex].Tasks[pos.TaskIndex]
+		task := m.taskAt(pos)
+		if task == nil {
+			return nil
+		}
 		text = task.Title
-		taskCopy := task
+		taskCopy := task.Clone()
 		m.ui.Clipboard = ClipboardState{
 			Task:     &taskCopy,
 			IsCut:    false,
@@ -433,6 +523,12 @@ func (m model) View() string {
 		return modal.Render(content, m.infoView())
 	case modes.ModeEstimatePicker:
 		return modal.Render(content, m.estimatePickerView())
+	case modes.ModeDeadlinePicker:
+		return modal.Render(content, m.deadlinePickerView())
+	case modes.ModeTrash:
+		return modal.Render(content, m.trashView())
+	case modes.ModeArchive:
+		return modal.Render(content, m.archiveView())
 	}
 	return content
 }
@@ -446,7 +542,7 @@ func (m model) renderLayoutItem(item LayoutItem) string {
 		if m.ui.Modes.IsEdit() && isSelected {
 			return m.renderEditProjectLine()
 		}
-		return renderProjectLine(m.project.Name, isSelected, focused)
+		return renderProjectLine(m.project.Name, m.ui.Filter.SectionLabel(), isSelected, focused)
 
 	case LayoutCategory:
 		category := m.project.Categories[item.CategoryIndex]
@@ -454,14 +550,22 @@ func (m model) renderLayoutItem(item LayoutItem) string {
 			return m.renderEditCategoryLine()
 		}
 		folded := m.ui.Fold.IsFolded(category.ID)
-		return renderCategoryLine(category.Name, category.EstimateMinutes, category.AggregateStatus(), isSelected, folded, m.ui.Width, focused)
+		return renderCategoryLine(category.Name, category.EstimateMinutes, category.TrackedDuration(time.Now()), category.AggregateStatus(), isSelected, folded, m.ui.Width, focused)
 
 	case LayoutTask:
-		task := m.project.Categories[item.CategoryIndex].Tasks[item.TaskIndex]
+		task := m.project.Categories[item.CategoryIndex].TaskAt(item.TaskIndex, item.SubtaskPath)
+		if task == nil {
+			return ""
+		}
+		opts := components.TaskLineOptions{
+			Depth:   len(item.SubtaskPath),
+			Folded:  m.ui.Fold.IsFolded(task.ID),
+			Blocked: m.project.IsBlocked(task),
+		}
 		if m.ui.Modes.IsEdit() && isSelected {
-			return m.renderEditTaskLine(task)
+			return m.renderEditTaskLine(*task, opts.Depth)
 		}
-		return m.renderTaskLine(task, isSelected, m.ui.Width, focused)
+		return m.renderTaskLine(*task, isSelected, m.ui.Width, focused, opts)
 
 	case LayoutEmptyCategory:
 		return ui.MutedStyle.Render("    (no tasks)")
@@ -476,21 +580,80 @@ func (m model) renderLayoutItem(item LayoutItem) string {
 	return ""
 }
 
+// historyBatchDelay groups saves made in quick succession, such as a burst of
+// status changes, into one git history commit.
+const historyBatchDelay = 10 * time.Second
+
+// promptForLocked asks for the passphrase on the terminal, before the TUI
+// takes it over, when some projects are encrypted and no key was set.
+func promptForLocked(projects []data.ProjectSummary) ([]byte, error) {
+	if !config.CanPrompt() {
+		return nil, nil
+	}
+	for _, project := range projects {
+		if project.Encrypted && project.Name == data.LockedName {
+			return config.PromptPassphrase("Passphrase for encrypted projects: ")
+		}
+	}
+	return nil, nil
+}
+
 func Run(dataDir string, projectSelector string, cfgManager *config.Manager, workingDir string) error {
 	store := data.NewStore(dataDir)
+	if cfgManager.Get().Storage == config.StorageSQLite {
+		var err error
+		if store, err = data.OpenSQLiteStore(dataDir); err != nil {
+			return err
+		}
+	}
+	defer store.Close()
+	passphrase, err := config.Passphrase(cfgManager.Get())
+	if err != nil {
+		return err
+	}
+	if passphrase != nil {
+		store.Keys.SetPassphrase(passphrase)
+	}
+	store.Backups.EverySaves = cfgManager.Get().BackupEverySaves
+	store.Backups.Retention = cfgManager.Get().BackupRetention()
+	store.Via = "tui"
 	if err := store.Ensure(); err != nil {
 		return err
 	}
+	if cfgManager.Get().GitHistory && cfgManager.Get().Storage != config.StorageSQLite && !config.IsLocalDataDir(dataDir) {
+		history := data.NewGitHistory(dataDir)
+		history.Delay = historyBatchDelay
+		if err := history.Init(); err == nil {
+			store.History = history
+			defer history.Flush()
+		}
+	}
+	var unreadable []data.UnreadableFile
+	store.OnUnreadable = func(f data.UnreadableFile) {
+		unreadable = append(unreadable, f)
+	}
+
+	trash := store.Trash()
+	_, _ = trash.PurgeExpired(cfgManager.Get().TrashRetention(), time.Now())
 
 	stateManager := data.NewStateManager(dataDir, workingDir)
 	if err := stateManager.Load(); err != nil {
 		return err
 	}
 
-	projects, err := store.ListProjects()
+	projects, err := store.ListSummaries()
 	if err != nil {
 		return err
 	}
+	if passphrase, err := promptForLocked(projects); err != nil {
+		return err
+	} else if passphrase != nil {
+		store.Keys.SetPassphrase(passphrase)
+		unreadable = nil
+		if projects, err = store.ListSummaries(); err != nil {
+			return err
+		}
+	}
 
 	var project domain.Project
 	startMode := modes.ModeNormal
@@ -536,7 +699,25 @@ func Run(dataDir string, projectSelector string, cfgManager *config.Manager, wor
 		ui:      NewUIState(selMgr, modeMachine),
 		deps:    NewDependencies(store, cfgManager, stateManager),
 	}
+	m.deps.Trash = trash
+	m.deps.Activity = store.ActivityLog()
 	m.ui.Fold = foldState
+	m.ui.History.Track(project)
+	m.refreshOtherTimer()
+	if len(unreadable) > 0 {
+		m.ui.StatusMsg = unreadableStatus(unreadable)
+	}
+	// Live reload follows the JSON files; the sqlite storage has none.
+	if cfgManager.Get().Storage != config.StorageSQLite {
+		if watcher, err := NewProjectWatcher(store.Dir); err == nil {
+			defer watcher.Close()
+			m.deps.Watcher = watcher
+		}
+	}
+	ui := m.ui
+	store.OnUnreadable = func(f data.UnreadableFile) {
+		ui.StatusMsg = unreadableStatus([]data.UnreadableFile{f})
+	}
 
 	if startMode == modes.ModeProjectPicker {
 		m.ui.Picker = ProjectPickerState{
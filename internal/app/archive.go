@@ -0,0 +1,117 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"phasionary/internal/domain"
+	"phasionary/internal/ui"
+)
+
+func (m *model) openArchive() {
+	m.ui.ArchiveView = ArchiveViewState{}
+	m.loadArchiveView()
+	m.ui.Modes.ToArchive()
+}
+
+// loadArchiveView lists the project's archive, newest first, keeping the
+// selection in range.
+func (m *model) loadArchiveView() {
+	view := &m.ui.ArchiveView
+	view.items = make([]domain.ArchivedTask, 0, len(m.project.Archive))
+	for i := len(m.project.Archive) - 1; i >= 0; i-- {
+		view.items = append(view.items, m.project.Archive[i])
+	}
+	view.moveSelection(0)
+}
+
+func (m model) handleArchiveKey(msg tea.KeyMsg) model {
+	switch msg.String() {
+	case "j", "down":
+		m.ui.ArchiveView.moveSelection(1)
+	case "k", "up":
+		m.ui.ArchiveView.moveSelection(-1)
+	case "enter", "r":
+		m.restoreArchivedTask()
+	case "a":
+		m.archiveFinishedTasks()
+	case "q", "esc", "Z":
+		m.ui.ArchiveView = ArchiveViewState{}
+		m.ui.Modes.ToNormal()
+	}
+	return m
+}
+
+// archiveFinishedTasks moves every completed and cancelled task off the
+// board into the archive.
+func (m *model) archiveFinishedTasks() {
+	archived := m.project.ArchiveFinished(time.Time{})
+	if len(archived) == 0 {
+		m.ui.StatusMsg = "No finished tasks to archive"
+		return
+	}
+	m.rebuildPositions()
+	m.storeTaskUpdate()
+	m.loadArchiveView()
+	noun := "tasks"
+	if len(archived) == 1 {
+		noun = "task"
+	}
+	m.ui.StatusMsg = fmt.Sprintf("Archived %d finished %s", len(archived), noun)
+}
+
+func (m *model) restoreArchivedTask() {
+	entry, ok := m.ui.ArchiveView.selectedItem()
+	if !ok {
+		return
+	}
+	task, err := m.project.RestoreArchived(entry.Task.ID)
+	if err != nil {
+		m.ui.StatusMsg = fmt.Sprintf("Restore failed: %v", err)
+		return
+	}
+	m.rebuildPositions()
+	m.storeTaskUpdate()
+	m.loadArchiveView()
+	m.ui.StatusMsg = fmt.Sprintf("Restored %q", task.Title)
+}
+
+func (m model) archiveView() string {
+	view := m.ui.ArchiveView
+	lines := []string{ui.DialogTitleStyle.Render("Archive:"), ""}
+	if len(view.items) == 0 {
+		lines = append(lines, ui.MutedStyle.Render("  (empty)"))
+	}
+
+	visibleEnd := view.scrollOffset + pickerVisibleItems
+	if visibleEnd > len(view.items) {
+		visibleEnd = len(view.items)
+	}
+	if view.scrollOffset > 0 {
+		lines = append(lines, ui.DialogHintStyle.Render("  ↑ more above"))
+	}
+	for i := view.scrollOffset; i < visibleEnd; i++ {
+		item := view.items[i]
+		prefix := "  "
+		if i == view.selected {
+			prefix = "> "
+		}
+		line := fmt.Sprintf("%s%-11s %s", prefix, item.Task.Status, truncateText(item.Task.Title, 30))
+		detail := strings.TrimSpace(item.CategoryName + "  " + FormatRelativeTime(item.Task.FinishedAt()))
+		if i == view.selected {
+			line = ui.SelectedStyle.Render(line) + "  " + ui.DialogHintStyle.Render(detail)
+		} else {
+			line += "  " + ui.DialogHintStyle.Render(detail)
+		}
+		lines = append(lines, line)
+	}
+	if visibleEnd < len(view.items) {
+		lines = append(lines, ui.DialogHintStyle.Render("  ↓ more below"))
+	}
+
+	lines = append(lines, "", ui.DialogHintStyle.Render("j/k navigate | enter/r restore | a archive finished | esc close"))
+	return ui.HelpDialogStyle.Render(strings.Join(lines, "\n"))
+}
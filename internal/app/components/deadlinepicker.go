@@ -0,0 +1,66 @@
+package components
+
+import (
+	"time"
+
+	"phasionary/internal/domain"
+)
+
+type DeadlineOption struct {
+	Label string
+	Value string
+}
+
+type DeadlinePickerState struct {
+	Selected int
+	Options  []DeadlineOption
+}
+
+func DeadlinePresets(now time.Time) []DeadlineOption {
+	today := domain.StartOfDay(now)
+	return []DeadlineOption{
+		{Label: "None", Value: ""},
+		{Label: "Today", Value: today.Format(domain.DateLayout)},
+		{Label: "Tomorrow", Value: today.AddDate(0, 0, 1).Format(domain.DateLayout)},
+		{Label: "This Friday", Value: domain.NextWeekday(today, time.Friday).Format(domain.DateLayout)},
+		{Label: "Next Monday", Value: domain.NextWeekday(today.AddDate(0, 0, 1), time.Monday).Format(domain.DateLayout)},
+		{Label: "In 1 week", Value: today.AddDate(0, 0, 7).Format(domain.DateLayout)},
+		{Label: "In 2 weeks", Value: today.AddDate(0, 0, 14).Format(domain.DateLayout)},
+		{Label: "In 1 month", Value: today.AddDate(0, 1, 0).Format(domain.DateLayout)},
+	}
+}
+
+func NewDeadlinePickerState(currentDeadline string, now time.Time) DeadlinePickerState {
+	options := DeadlinePresets(now)
+	selected := -1
+	for i, option := range options {
+		if option.Value == currentDeadline {
+			selected = i
+			break
+		}
+	}
+	if selected < 0 {
+		options = append(options, DeadlineOption{Label: "Keep current", Value: currentDeadline})
+		selected = len(options) - 1
+	}
+	return DeadlinePickerState{Selected: selected, Options: options}
+}
+
+func (d *DeadlinePickerState) MoveUp() {
+	if d.Selected > 0 {
+		d.Selected--
+	}
+}
+
+func (d *DeadlinePickerState) MoveDown() {
+	if d.Selected < len(d.Options)-1 {
+		d.Selected++
+	}
+}
+
+func (d *DeadlinePickerState) SelectedValue() string {
+	if d.Selected >= 0 && d.Selected < len(d.Options) {
+		return d.Options[d.Selected].Value
+	}
+	return ""
+}
@@ -3,6 +3,7 @@ package components
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
@@ -11,10 +12,23 @@ import (
 	"phasionary/internal/ui"
 )
 
+// TaskLineOptions carries context about a task that is derived from the
+// rest of the project rather than the task itself.
+type TaskLineOptions struct {
+	Depth   int
+	Folded  bool
+	Blocked bool
+}
+
+func (o TaskLineOptions) indent() string {
+	return strings.Repeat("  ", o.Depth)
+}
+
 type TaskLineRenderer struct {
 	width         int
 	statusDisplay string
 	focused       bool
+	now           time.Time
 }
 
 func NewTaskLineRenderer(width int, statusDisplay string, focused bool) *TaskLineRenderer {
@@ -22,40 +36,62 @@ func NewTaskLineRenderer(width int, statusDisplay string, focused bool) *TaskLin
 		width:         width,
 		statusDisplay: statusDisplay,
 		focused:       focused,
+		now:           time.Now(),
+	}
+}
+
+// Height returns the number of screen rows the task occupies once wrapped.
+func (r *TaskLineRenderer) Height(task domain.Task, opts TaskLineOptions) int {
+	if r.width <= 0 {
+		return 1
 	}
+	iconText := ""
+	if icon := ui.PriorityIcon(task.Priority); icon != "" {
+		iconText = icon + " "
+	}
+	overhead := ansi.StringWidth("  " + opts.indent() + "[" + r.statusLabel(task.Status) + "] " + iconText)
+	suffixWidth := ansi.StringWidth(r.suffixText(task, opts))
+	available := safeWidth(r.width, overhead+suffixWidth)
+	wrapped := ansi.Wrap(task.Title, available, "")
+	return strings.Count(wrapped, "\n") + 1
 }
 
 func (r *TaskLineRenderer) Render(task domain.Task, selected bool) string {
+	return r.RenderWithOptions(task, selected, TaskLineOptions{})
+}
+
+func (r *TaskLineRenderer) RenderWithOptions(task domain.Task, selected bool, opts TaskLineOptions) string {
 	prefix := "  "
 	if selected {
 		prefix = "> "
 	}
+	prefix += opts.indent()
 	priorityIcon := ui.PriorityIcon(task.Priority)
 
 	if selected {
-		return r.renderSelected(task, prefix, priorityIcon)
+		return r.renderSelected(task, prefix, priorityIcon, opts)
 	}
-	return r.renderUnselected(task, prefix, priorityIcon)
+	return r.renderUnselected(task, prefix, priorityIcon, opts)
 }
 
-func (r *TaskLineRenderer) renderUnselected(task domain.Task, prefix, priorityIcon string) string {
+func (r *TaskLineRenderer) renderUnselected(task domain.Task, prefix, priorityIcon string, opts TaskLineOptions) string {
 	status := r.formatStatus(task.Status, false)
 	icon := ""
 	if priorityIcon != "" {
 		icon = ui.TaskTitleStyle(task.Priority, task.Status).Render(priorityIcon) + " "
 	}
-	estimate := r.formatEstimateBadge(task.EstimateMinutes, false)
+	suffix := r.formatSuffix(task, opts, false)
 	titleStyle := ui.TaskTitleStyle(task.Priority, task.Status)
 	prefixPart := fmt.Sprintf("%s[%s] %s", prefix, status, icon)
 
 	if r.width <= 0 {
-		return prefixPart + titleStyle.Render(task.Title) + estimate
+		return prefixPart + titleStyle.Render(task.Title) + suffix
 	}
 
-	return r.wrapTaskContentWithSuffix(task.Title, prefixPart, titleStyle, estimate, r.estimateBadgeText(task.EstimateMinutes))
+	return r.wrapTaskContentWithSuffix(task.Title, prefixPart, titleStyle, suffix, r.suffixText(task, opts))
 }
 
-func (r *TaskLineRenderer) renderSelected(task domain.Task, prefix, priorityIcon string) string {
+func (r *TaskLineRenderer) renderSelected(task domain.Task, prefix, priorityIcon string, opts TaskLineOptions) string {
 	statusText := r.statusLabel(task.Status)
 	priorityStyle := ui.GetSelectedPriorityStyle(task.Priority, r.focused)
 	statusStyle := ui.GetSelectedStatusStyle(task.Status, r.focused)
@@ -68,19 +104,19 @@ func (r *TaskLineRenderer) renderSelected(task domain.Task, prefix, priorityIcon
 		iconText = priorityIcon + " "
 	}
 
-	estimate := r.formatEstimateBadge(task.EstimateMinutes, true)
-	estimateText := r.estimateBadgeText(task.EstimateMinutes)
+	suffix := r.formatSuffix(task, opts, true)
+	suffixText := r.suffixText(task, opts)
 
 	prefixPart := selectedStyle.Render(prefix+"[") +
 		statusStyle.Render(statusText) +
 		selectedStyle.Render("] ") + icon
 
 	if r.width <= 0 {
-		return prefixPart + priorityStyle.Render(task.Title) + estimate
+		return prefixPart + priorityStyle.Render(task.Title) + suffix
 	}
 
 	overhead := ansi.StringWidth(prefix + "[" + statusText + "] " + iconText)
-	return r.wrapSelectedContentWithSuffix(task.Title, prefixPart, overhead, priorityStyle, estimate, estimateText)
+	return r.wrapSelectedContentWithSuffix(task.Title, prefixPart, overhead, priorityStyle, suffix, suffixText)
 }
 
 func (r *TaskLineRenderer) wrapTaskContentWithSuffix(title, prefixPart string, titleStyle lipgloss.Style, suffix, suffixText string) string {
@@ -169,6 +205,119 @@ func safeWidth(totalWidth, overhead int) int {
 	return available
 }
 
+func (r *TaskLineRenderer) formatSuffix(task domain.Task, opts TaskLineOptions, selected bool) string {
+	return r.formatTagsBadge(task, selected) +
+		r.formatBlockedBadge(opts, selected) +
+		r.formatProgressBadge(task, opts, selected) +
+		r.formatEstimateBadge(task.TotalEstimateMinutes(), selected) +
+		r.formatDeadlineBadge(task, selected)
+}
+
+func (r *TaskLineRenderer) suffixText(task domain.Task, opts TaskLineOptions) string {
+	return tagsBadgeText(task) +
+		blockedBadgeText(opts) +
+		progressBadgeText(task, opts) +
+		r.estimateBadgeText(task.TotalEstimateMinutes()) +
+		r.deadlineBadgeText(task)
+}
+
+func (r *TaskLineRenderer) formatTagsBadge(task domain.Task, selected bool) string {
+	text := tagsBadgeText(task)
+	if text == "" {
+		return ""
+	}
+	if selected {
+		return ui.GetSelectedStyle(r.focused).Foreground(ui.TagColor).Render(text)
+	}
+	return ui.TagStyle.Render(text)
+}
+
+func tagsBadgeText(task domain.Task) string {
+	return domain.FormatTitleWithTags("", task.Tags)
+}
+
+func (r *TaskLineRenderer) formatBlockedBadge(opts TaskLineOptions, selected bool) string {
+	text := blockedBadgeText(opts)
+	if text == "" {
+		return ""
+	}
+	if selected {
+		return ui.GetSelectedStyle(r.focused).Foreground(ui.BlockedColor).Render(text)
+	}
+	return ui.BlockedStyle.Render(text)
+}
+
+func blockedBadgeText(opts TaskLineOptions) string {
+	if !opts.Blocked {
+		return ""
+	}
+	return " ⊘ blocked"
+}
+
+func (r *TaskLineRenderer) formatProgressBadge(task domain.Task, opts TaskLineOptions, selected bool) string {
+	text := progressBadgeText(task, opts)
+	if text == "" {
+		return ""
+	}
+	if selected {
+		return ui.GetSelectedStyle(r.focused).Render(text)
+	}
+	return ui.MutedStyle.Render(text)
+}
+
+func progressBadgeText(task domain.Task, opts TaskLineOptions) string {
+	done, total := task.SubtaskProgress()
+	if total == 0 {
+		return ""
+	}
+	text := fmt.Sprintf(" %d/%d", done, total)
+	if opts.Folded {
+		text = " ▶" + text
+	}
+	return text
+}
+
+func (r *TaskLineRenderer) formatDeadlineBadge(task domain.Task, selected bool) string {
+	text := r.deadlineBadgeText(task)
+	if text == "" {
+		return ""
+	}
+	overdue := task.IsOverdue(r.now)
+	dueSoon := task.IsDueSoon(r.now)
+	if selected {
+		return ui.GetSelectedDeadlineStyle(overdue, dueSoon, r.focused).Render(text)
+	}
+	return ui.DeadlineStyle(overdue, dueSoon).Render(text)
+}
+
+func (r *TaskLineRenderer) deadlineBadgeText(task domain.Task) string {
+	days, ok := task.DaysUntilDeadline(r.now)
+	if !ok {
+		return ""
+	}
+	switch {
+	case days == 0:
+		return " due today"
+	case days == 1:
+		return " due tomorrow"
+	case days < 0 && !task.IsDone():
+		return " overdue " + formatDeadlineShort(task, r.now)
+	default:
+		return " due " + formatDeadlineShort(task, r.now)
+	}
+}
+
+func formatDeadlineShort(task domain.Task, now time.Time) string {
+	d, ok := task.DeadlineIn(now.Location())
+	if !ok {
+		return task.Deadline
+	}
+	if d.Year() == now.Year() {
+		return d.Format("Jan 2")
+	}
+	return d.Format("Jan 2 2006")
+}
+
 func (r *TaskLineRenderer) formatEstimateBadge(minutes int, selected bool) string {
 	text := r.estimateBadgeText(minutes)
 	if text == "" {
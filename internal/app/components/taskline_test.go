@@ -64,6 +64,23 @@ func TestTaskLineRenderer_Render(t *testing.T) {
 		assert.Contains(t, result, "[")
 		assert.Contains(t, result, "]")
 	})
+
+	t.Run("marks blocked tasks", func(t *testing.T) {
+		renderer := NewTaskLineRenderer(0, "text", true)
+		task := domain.Task{Title: "Deploy", Status: domain.StatusTodo}
+		assert.NotContains(t, renderer.Render(task, false), "blocked")
+
+		opts := TaskLineOptions{Blocked: true}
+		assert.Contains(t, renderer.RenderWithOptions(task, false, opts), "blocked")
+		assert.Contains(t, renderer.RenderWithOptions(task, true, opts), "blocked")
+	})
+
+	t.Run("shows tags after the title", func(t *testing.T) {
+		renderer := NewTaskLineRenderer(0, "text", true)
+		task := domain.Task{Title: "Deploy", Status: domain.StatusTodo, Tags: []string{"ops", "release-1.2"}}
+		assert.Contains(t, renderer.Render(task, false), "#ops #release-1.2")
+		assert.Contains(t, renderer.Render(task, true), "#ops #release-1.2")
+	})
 }
 
 func TestTaskLineRenderer_StatusLabel(t *testing.T) {
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"slices"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,9 +20,12 @@ func (m *model) startEditing() {
 		m.ui.Modes.ToEdit()
 		m.ui.Edit = newEditState(m.project.Name, false, "", focusProject)
 	case focusTask:
-		task := m.project.Categories[position.CategoryIndex].Tasks[position.TaskIndex]
+		task := m.taskAt(position)
+		if task == nil {
+			return
+		}
 		m.ui.Modes.ToEdit()
-		m.ui.Edit = newEditState(task.Title, false, "", focusTask)
+		m.ui.Edit = newEditState(domain.FormatTitleWithTags(task.Title, task.Tags), false, "", focusTask)
 	case focusCategory:
 		category := m.project.Categories[position.CategoryIndex]
 		m.ui.Modes.ToEdit()
@@ -38,10 +42,15 @@ func (m *model) startAddingTask() {
 	if catIndex < 0 || catIndex >= len(m.project.Categories) {
 		return
 	}
+	if m.ui.Filter.Section() == domain.SectionPast {
+		m.ui.StatusMsg = "Switch to another section to add tasks"
+		return
+	}
 	newTask, err := domain.NewTask("")
 	if err != nil {
 		return
 	}
+	newTask.Section = m.viewSection()
 
 	insertAtTop := m.ui.LastSortAscending == nil || *m.ui.LastSortAscending
 	var taskIndex int
@@ -136,23 +145,31 @@ func (m *model) finishEditing() {
 		m.project.UpdatedAt = domain.NowTimestamp()
 		m.storeTaskUpdate()
 	case focusTask:
-		task := &m.project.Categories[position.CategoryIndex].Tasks[position.TaskIndex]
-		if task.Title != trimmed || m.ui.Edit.isAdding {
-			task.Title = trimmed
+		task := m.taskAt(position)
+		if task == nil {
+			m.cancelEditing()
+			return
+		}
+		title, tags := domain.ParseTitleTags(trimmed)
+		oldTags := task.Tags
+		if err := task.SetTags(tags); err != nil {
+			m.ui.StatusMsg = err.Error()
+		}
+		changed := !slices.Equal(oldTags, task.Tags)
+		if task.Title != title || m.ui.Edit.isAdding {
+			task.Title = title
 			task.UpdatedAt = domain.NowTimestamp()
+			changed = true
 
-			if m.ui.Edit.isAdding {
+			if m.ui.Edit.isAdding && len(position.SubtaskPath) == 0 {
 				taskID := task.ID
 				ascending := m.ui.LastSortAscending == nil || *m.ui.LastSortAscending
 				sortCategoryTasks(m.project.Categories[position.CategoryIndex].Tasks, ascending)
 				m.rebuildPositions()
-				m.ui.Selection.SelectByPredicate(func(p selection.Position) bool {
-					return p.Kind == selection.FocusTask &&
-						p.CategoryIndex == position.CategoryIndex &&
-						m.project.Categories[p.CategoryIndex].Tasks[p.TaskIndex].ID == taskID
-				})
+				m.selectTaskByID(taskID)
 			}
-
+		}
+		if changed {
 			m.storeTaskUpdate()
 		}
 	case focusCategory:
@@ -212,14 +229,7 @@ func (m *model) removeNewTask() {
 	if m.ui.Edit.newItemID == "" {
 		return
 	}
-	for cIndex := range m.project.Categories {
-		for tIndex, task := range m.project.Categories[cIndex].Tasks {
-			if task.ID == m.ui.Edit.newItemID {
-				_ = m.project.Categories[cIndex].RemoveTask(tIndex)
-				break
-			}
-		}
-	}
+	m.project.RemoveTaskByID(m.ui.Edit.newItemID)
 	m.rebuildPositions()
 	m.ensureVisible()
 }
@@ -21,6 +21,7 @@ type ExternalEditState struct {
 	ItemType      focusKind
 	CategoryIndex int
 	TaskIndex     int
+	SubtaskPath   []int
 }
 
 func (e *ExternalEditState) reset() {
@@ -28,6 +29,7 @@ func (e *ExternalEditState) reset() {
 	e.ItemType = focusProject
 	e.CategoryIndex = -1
 	e.TaskIndex = -1
+	e.SubtaskPath = nil
 }
 
 func getEditorCmd() string {
@@ -40,8 +42,78 @@ func getEditorCmd() string {
 	return "vim"
 }
 
+// notesSeparator splits the description from the notes in the task edit
+// buffer. The first line of the buffer is always the title.
+const notesSeparator = "--- notes ---"
+
 func formatTaskForEdit(task domain.Task) string {
-	return task.Title
+	var sb strings.Builder
+	sb.WriteString(task.Title)
+	sb.WriteString("\n\n")
+	if task.Description != "" {
+		for _, line := range strings.Split(task.Description, "\n") {
+			sb.WriteString(escapeSeparator(line))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(notesSeparator)
+	sb.WriteString("\n")
+	if task.Notes != "" {
+		sb.WriteString(task.Notes)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+type taskEditContent struct {
+	title       string
+	description string
+	notes       string
+}
+
+func parseTaskEdit(content string) taskEditContent {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	var parsed taskEditContent
+	if len(lines) == 0 {
+		return parsed
+	}
+	parsed.title = strings.TrimSpace(lines[0])
+	var description, notes []string
+	inNotes := false
+	for _, line := range lines[1:] {
+		if !inNotes && strings.TrimSpace(line) == notesSeparator {
+			inNotes = true
+			continue
+		}
+		if inNotes {
+			notes = append(notes, line)
+		} else {
+			description = append(description, unescapeSeparator(line))
+		}
+	}
+	parsed.description = strings.TrimSpace(strings.Join(description, "\n"))
+	parsed.notes = strings.TrimSpace(strings.Join(notes, "\n"))
+	return parsed
+}
+
+// escapeSeparator puts a backslash in front of a description line that
+// reads as the notes separator, so it is not taken for one when the buffer
+// is parsed back. Lines that already have backslashes get one more.
+func escapeSeparator(line string) string {
+	text := strings.TrimLeft(line, " \t")
+	if strings.TrimRight(strings.TrimLeft(text, `\`), " \t") != notesSeparator {
+		return line
+	}
+	return line[:len(line)-len(text)] + `\` + text
+}
+
+func unescapeSeparator(line string) string {
+	text := strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(text, `\`) || escapeSeparator(text) == text {
+		return line
+	}
+	return line[:len(line)-len(text)] + text[1:]
 }
 
 func formatCategoryForEdit(category domain.Category) string {
@@ -65,7 +137,11 @@ func (m *model) startExternalEdit() tea.Cmd {
 	case focusCategory:
 		content = formatCategoryForEdit(m.project.Categories[pos.CategoryIndex])
 	case focusTask:
-		content = formatTaskForEdit(m.project.Categories[pos.CategoryIndex].Tasks[pos.TaskIndex])
+		task := m.taskAt(pos)
+		if task == nil {
+			return nil
+		}
+		content = formatTaskForEdit(*task)
 	}
 
 	tempFile, err := os.CreateTemp("", "phasionary-edit-*.txt")
@@ -87,6 +163,7 @@ func (m *model) startExternalEdit() tea.Cmd {
 		ItemType:      pos.Kind,
 		CategoryIndex: pos.CategoryIndex,
 		TaskIndex:     pos.TaskIndex,
+		SubtaskPath:   pos.SubtaskPath,
 	}
 
 	m.ui.Modes.ToExternalEdit()
@@ -140,10 +217,11 @@ func (m *model) applyProjectEdit(content string) {
 
 	m.project.Name = name
 	m.project.UpdatedAt = domain.NowTimestamp()
-	if err := m.deps.Store.SaveProject(m.project); err != nil {
+	if err := m.writeProject(); err != nil {
 		m.ui.StatusMsg = fmt.Sprintf("Failed to save: %v", err)
 		return
 	}
+	m.ui.History.Record(m.project)
 	m.ui.StatusMsg = "Project updated"
 }
 
@@ -174,10 +252,11 @@ func (m *model) applyCategoryEdit(content string) {
 
 	m.project.Categories[idx].Name = name
 	m.project.UpdatedAt = domain.NowTimestamp()
-	if err := m.deps.Store.SaveProject(m.project); err != nil {
+	if err := m.writeProject(); err != nil {
 		m.ui.StatusMsg = fmt.Sprintf("Failed to save: %v", err)
 		return
 	}
+	m.ui.History.Record(m.project)
 	m.ui.StatusMsg = "Category updated"
 }
 
@@ -189,26 +268,29 @@ func (m *model) applyTaskEdit(content string) {
 		m.ui.StatusMsg = "Category no longer exists"
 		return
 	}
-	if taskIdx < 0 || taskIdx >= len(m.project.Categories[catIdx].Tasks) {
+	task := m.project.Categories[catIdx].TaskAt(taskIdx, m.ui.ExternalEdit.SubtaskPath)
+	if task == nil {
 		m.ui.StatusMsg = "Task no longer exists"
 		return
 	}
 
-	title := strings.TrimSpace(content)
-	if title == "" {
+	parsed := parseTaskEdit(content)
+	if parsed.title == "" {
 		m.ui.StatusMsg = "Task title cannot be empty"
 		return
 	}
 
-	task := &m.project.Categories[catIdx].Tasks[taskIdx]
-	if title == task.Title {
+	if parsed.title == task.Title && parsed.description == task.Description && parsed.notes == task.Notes {
 		return
 	}
 
-	task.Title = title
-	if err := m.deps.Store.SaveProject(m.project); err != nil {
+	task.Title = parsed.title
+	task.SetDescription(parsed.description)
+	task.SetNotes(parsed.notes)
+	if err := m.writeProject(); err != nil {
 		m.ui.StatusMsg = fmt.Sprintf("Failed to save: %v", err)
 		return
 	}
+	m.ui.History.Record(m.project)
 	m.ui.StatusMsg = "Task updated"
 }
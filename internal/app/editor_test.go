@@ -0,0 +1,58 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"phasionary/internal/domain"
+)
+
+func TestParseTaskEdit(t *testing.T) {
+	t.Run("title only", func(t *testing.T) {
+		parsed := parseTaskEdit("Fix login\n")
+		assert.Equal(t, "Fix login", parsed.title)
+		assert.Empty(t, parsed.description)
+		assert.Empty(t, parsed.notes)
+	})
+
+	t.Run("title, description and notes", func(t *testing.T) {
+		content := "Fix login\n\nSteps:\n1. open app\n\n2. log in\n\n--- notes ---\nSee ticket 42\n"
+		parsed := parseTaskEdit(content)
+		assert.Equal(t, "Fix login", parsed.title)
+		assert.Equal(t, "Steps:\n1. open app\n\n2. log in", parsed.description)
+		assert.Equal(t, "See ticket 42", parsed.notes)
+	})
+
+	t.Run("handles CRLF line endings", func(t *testing.T) {
+		parsed := parseTaskEdit("Title\r\n\r\nBody\r\n--- notes ---\r\nNote\r\n")
+		assert.Equal(t, "Title", parsed.title)
+		assert.Equal(t, "Body", parsed.description)
+		assert.Equal(t, "Note", parsed.notes)
+	})
+}
+
+func TestFormatTaskForEdit_RoundTrip(t *testing.T) {
+	task := domain.Task{
+		Title:       "Write docs",
+		Description: "Cover install\n\nand usage",
+		Notes:       "Link: https://example.com",
+	}
+	parsed := parseTaskEdit(formatTaskForEdit(task))
+	assert.Equal(t, task.Title, parsed.title)
+	assert.Equal(t, task.Description, parsed.description)
+	assert.Equal(t, task.Notes, parsed.notes)
+}
+
+func TestFormatTaskForEdit_EscapesSeparator(t *testing.T) {
+	task := domain.Task{
+		Title:       "Write docs",
+		Description: "Before\n--- notes ---\n\\--- notes ---\nAfter",
+		Notes:       "Real notes",
+	}
+	content := formatTaskForEdit(task)
+	assert.Contains(t, content, "\\--- notes ---\n\\\\--- notes ---")
+	parsed := parseTaskEdit(content)
+	assert.Equal(t, task.Description, parsed.description)
+	assert.Equal(t, task.Notes, parsed.notes)
+}
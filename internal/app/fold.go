@@ -6,18 +6,34 @@ func (m *model) toggleFold() {
 		return
 	}
 
-	var categoryID string
+	var foldID string
 	switch pos.Kind {
 	case focusCategory:
-		categoryID = m.project.Categories[pos.CategoryIndex].ID
+		foldID = m.project.Categories[pos.CategoryIndex].ID
 	case focusTask:
-		categoryID = m.project.Categories[pos.CategoryIndex].ID
-		m.ui.Selection.SetSelected(m.findCategoryPositionIndex(pos.CategoryIndex))
+		category := &m.project.Categories[pos.CategoryIndex]
+		task := m.taskAt(pos)
+		switch {
+		case task != nil && task.HasSubtasks():
+			foldID = task.ID
+		case len(pos.SubtaskPath) > 0:
+			parent := category.TaskAt(pos.TaskIndex, pos.SubtaskPath[:len(pos.SubtaskPath)-1])
+			foldID = parent.ID
+			m.ui.Fold.Toggle(foldID)
+			m.saveFoldState()
+			m.rebuildPositions()
+			m.selectTaskByID(foldID)
+			m.ensureVisible()
+			return
+		default:
+			foldID = category.ID
+			m.ui.Selection.SetSelected(m.findCategoryPositionIndex(pos.CategoryIndex))
+		}
 	default:
 		return
 	}
 
-	m.ui.Fold.Toggle(categoryID)
+	m.ui.Fold.Toggle(foldID)
 	m.saveFoldState()
 	m.rebuildPositions()
 	m.ensureVisible()
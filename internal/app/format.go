@@ -3,6 +3,8 @@ package app
 import (
 	"fmt"
 	"time"
+
+	"phasionary/internal/domain"
 )
 
 func FormatDate(timestamp string) string {
@@ -16,6 +18,15 @@ func FormatDate(timestamp string) string {
 	return t.Local().Format("Jan 2, 2006 at 3:04 PM")
 }
 
+// formatActivityTime is the short timestamp used in the info view history.
+func formatActivityTime(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return t.Local().Format("Jan 2 15:04")
+}
+
 func FormatRelativeTime(timestamp string) string {
 	if timestamp == "" {
 		return ""
@@ -130,6 +141,26 @@ func FormatEstimate(minutes int) string {
 	return fmt.Sprintf("%dd", days)
 }
 
+func formatTrackedBadge(tracked time.Duration) string {
+	if tracked < time.Minute {
+		return ""
+	}
+	return " ⏱" + domain.FormatDuration(tracked)
+}
+
+// formatTrackedLabel shows the task's logged time, with the subtask total
+// and a running marker when they apply.
+func formatTrackedLabel(task *domain.Task, now time.Time) string {
+	label := domain.FormatDuration(task.TrackedDuration(now))
+	if total := task.TotalTrackedDuration(now); task.HasSubtasks() && total != task.TrackedDuration(now) {
+		label += fmt.Sprintf(" (%s with subtasks)", domain.FormatDuration(total))
+	}
+	if task.IsTimerRunning() {
+		label += " (running)"
+	}
+	return label
+}
+
 func FormatEstimateLabel(minutes int) string {
 	if minutes == 0 {
 		return "None"
@@ -153,3 +184,26 @@ func FormatEstimateLabel(minutes int) string {
 	}
 	return fmt.Sprintf("%d days", days)
 }
+
+func FormatDeadlineLabel(deadline string, now time.Time) string {
+	if deadline == "" {
+		return "None"
+	}
+	task := domain.Task{Deadline: deadline}
+	days, ok := task.DaysUntilDeadline(now)
+	if !ok {
+		return deadline
+	}
+	switch {
+	case days == 0:
+		return deadline + " (today)"
+	case days == 1:
+		return deadline + " (tomorrow)"
+	case days == -1:
+		return deadline + " (yesterday)"
+	case days < 0:
+		return fmt.Sprintf("%s (%d days ago)", deadline, -days)
+	default:
+		return fmt.Sprintf("%s (in %d days)", deadline, days)
+	}
+}
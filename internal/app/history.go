@@ -0,0 +1,41 @@
+package app
+
+import "phasionary/internal/domain"
+
+func (m *model) undo() {
+	ok, err := m.ui.History.Undo(m.project, m.restoreProject)
+	switch {
+	case !ok:
+		m.ui.StatusMsg = "Nothing to undo"
+	case err != nil:
+		m.ui.StatusMsg = "Save failed: " + err.Error()
+	default:
+		m.ui.StatusMsg = "Undone"
+	}
+}
+
+func (m *model) redo() {
+	ok, err := m.ui.History.Redo(m.project, m.restoreProject)
+	switch {
+	case !ok:
+		m.ui.StatusMsg = "Nothing to redo"
+	case err != nil:
+		m.ui.StatusMsg = "Save failed: " + err.Error()
+	default:
+		m.ui.StatusMsg = "Redone"
+	}
+}
+
+// restoreProject writes a snapshot from the history back as the current
+// state of the project, putting the current state back when that fails.
+func (m *model) restoreProject(project domain.Project) error {
+	project.Revision = m.project.Revision
+	current := m.project
+	m.replaceProject(project)
+	if err := m.writeProject(); err != nil {
+		m.replaceProject(current)
+		return err
+	}
+	m.ui.History.Track(m.project)
+	return nil
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"phasionary/internal/app/modes"
+	"phasionary/internal/app/selection"
+	"phasionary/internal/config"
+	"phasionary/internal/data"
+	"phasionary/internal/domain"
+)
+
+// failingRepository is a store whose saves fail until told otherwise.
+type failingRepository struct {
+	data.ProjectRepository
+	err error
+}
+
+func (r *failingRepository) SaveProject(project *domain.Project) error {
+	if r.err != nil {
+		return r.err
+	}
+	project.Revision++
+	return nil
+}
+
+func newHistoryModel(store data.ProjectRepository, project domain.Project) model {
+	positions := rebuildPositions(project.Categories, nil, nil)
+	selMgr := selection.NewManager(toSelectionPositions(positions), findFirstTaskIndex(positions))
+	m := model{
+		project: project,
+		ui:      NewUIState(selMgr, modes.NewMachine(modes.ModeNormal)),
+		deps:    NewDependencies(store, config.NewManager(""), nil),
+	}
+	m.ui.History.Track(project)
+	return m
+}
+
+func TestUndo_FailedSaveKeepsHistory(t *testing.T) {
+	store := &failingRepository{}
+	m := newHistoryModel(store, domain.Project{ID: "p1", Name: "v1"})
+	m.project.Name = "v2"
+	m.storeTaskUpdate()
+
+	store.err = errors.New("disk full")
+	m.undo()
+	assert.Equal(t, "Save failed: disk full", m.ui.StatusMsg)
+	assert.Equal(t, "v2", m.project.Name)
+	m.redo()
+	assert.Equal(t, "Nothing to redo", m.ui.StatusMsg)
+
+	store.err = nil
+	m.undo()
+	assert.Equal(t, "Undone", m.ui.StatusMsg)
+	assert.Equal(t, "v1", m.project.Name)
+
+	store.err = errors.New("disk full")
+	m.redo()
+	assert.Equal(t, "Save failed: disk full", m.ui.StatusMsg)
+	assert.Equal(t, "v1", m.project.Name)
+
+	store.err = nil
+	m.redo()
+	require.Equal(t, "Redone", m.ui.StatusMsg)
+	assert.Equal(t, "v2", m.project.Name)
+}
@@ -1,10 +1,12 @@
 package app
 
 import (
+	"time"
+
 	"github.com/charmbracelet/x/ansi"
 
+	"phasionary/internal/app/components"
 	"phasionary/internal/domain"
-	"phasionary/internal/ui"
 )
 
 type LayoutItemKind int
@@ -24,6 +26,7 @@ type LayoutItem struct {
 	PositionIndex int // Index into model.positions (-1 for non-selectable)
 	CategoryIndex int
 	TaskIndex     int
+	SubtaskPath   []int
 }
 
 type Layout struct {
@@ -94,7 +97,8 @@ func (b *LayoutBuilder) Build(project domain.Project, positions []focusPosition)
 		totalHeight += b.config.BlankAfterProject
 	}
 
-	for catIdx, category := range project.Categories {
+	for catIdx := range project.Categories {
+		category := &project.Categories[catIdx]
 		// Spacing between categories (not before first)
 		if catIdx > 0 && b.config.BlankBetweenCats > 0 {
 			items = append(items, LayoutItem{
@@ -112,6 +116,7 @@ func (b *LayoutBuilder) Build(project domain.Project, positions []focusPosition)
 		if category.AggregateStatus() != "" {
 			catSuffixWidth += 4 // " [x]"
 		}
+		catSuffixWidth += ansi.StringWidth(formatTrackedBadge(category.TrackedDuration(time.Now())))
 		catHeight := countWrappedLines(category.Name, b.width, prefixWidth+2+catSuffixWidth)
 		items = append(items, LayoutItem{
 			Kind:          LayoutCategory,
@@ -136,14 +141,8 @@ func (b *LayoutBuilder) Build(project domain.Project, positions []focusPosition)
 			continue
 		}
 
-		visibleTaskCount := 0
-		for _, task := range category.Tasks {
-			if b.filter == nil || b.filter.IsStatusVisible(task.Status) {
-				visibleTaskCount++
-			}
-		}
-
-		if visibleTaskCount == 0 {
+		visible := visibleTasks(category, b.filter, b.fold)
+		if len(visible) == 0 {
 			// "(no tasks)" placeholder - not selectable
 			items = append(items, LayoutItem{
 				Kind:          LayoutEmptyCategory,
@@ -169,17 +168,15 @@ func (b *LayoutBuilder) Build(project domain.Project, positions []focusPosition)
 		}
 
 		// Tasks (consecutive tasks have no blank lines between them)
-		for taskIdx, task := range category.Tasks {
-			if b.filter != nil && !b.filter.IsStatusVisible(task.Status) {
-				continue
-			}
-			taskHeight := b.countTaskLines(task)
+		for _, v := range visible {
+			taskHeight := b.countTaskLines(*v.task, b.taskLineOptions(&project, v))
 			items = append(items, LayoutItem{
 				Kind:          LayoutTask,
 				Height:        taskHeight,
 				PositionIndex: posIndex,
 				CategoryIndex: catIdx,
-				TaskIndex:     taskIdx,
+				TaskIndex:     v.taskIndex,
+				SubtaskPath:   v.path,
 			})
 			totalHeight += taskHeight
 			posIndex++
@@ -192,19 +189,16 @@ func (b *LayoutBuilder) Build(project domain.Project, positions []focusPosition)
 	}
 }
 
-func (b *LayoutBuilder) countTaskLines(task domain.Task) int {
-	if b.width <= 0 {
-		return 1
-	}
-	prefix := "  "
-	priorityIcon := ui.PriorityIcon(task.Priority)
-	statusText := statusLabel(task.Status, b.statusDisplay)
-	iconText := ""
-	if priorityIcon != "" {
-		iconText = priorityIcon + " "
+func (b *LayoutBuilder) countTaskLines(task domain.Task, opts components.TaskLineOptions) int {
+	return components.NewTaskLineRenderer(b.width, b.statusDisplay, true).Height(task, opts)
+}
+
+func (b *LayoutBuilder) taskLineOptions(project *domain.Project, v visibleTask) components.TaskLineOptions {
+	return components.TaskLineOptions{
+		Depth:   v.depth(),
+		Folded:  b.fold != nil && b.fold.IsFolded(v.task.ID),
+		Blocked: project.IsBlocked(v.task),
 	}
-	overhead := ansi.StringWidth(prefix + "[" + statusText + "] " + iconText)
-	return countWrappedLines(task.Title, b.width, overhead)
 }
 
 func (m *model) buildLayout() *Layout {
@@ -13,6 +13,11 @@ type ClipboardState struct {
 	Task     *domain.Task
 	IsCut    bool
 	SourceID string
+	// Category is set instead of Task when a category was cut.
+	Category *domain.Category
+	// ProjectID is the project a cut task comes from, which may no longer
+	// be the current one by the time it is pasted.
+	ProjectID string
 }
 
 type UIState struct {
@@ -25,7 +30,13 @@ type UIState struct {
 	Fold               FoldState
 	ExternalEdit       ExternalEditState
 	EstimatePicker     components.EstimatePickerState
+	DeadlinePicker     components.DeadlinePickerState
 	Clipboard          ClipboardState
+	History            HistoryState
+	TrashView          TrashViewState
+	ArchiveView        ArchiveViewState
+	InfoActivity       []data.ActivityEvent
+	OtherTimer         *data.RunningTimer
 	StatusMsg          string
 	ScrollOffset       int
 	PendingKey         rune
@@ -33,12 +44,16 @@ type UIState struct {
 	Height             int
 	LastSortAscending  *bool
 	WindowFocused      bool
+	ReloadPending      bool
 }
 
 type Dependencies struct {
 	Store        data.ProjectRepository
 	CfgManager   *config.Manager
 	StateManager *data.StateManager
+	Watcher      *ProjectWatcher
+	Trash        *data.Trash
+	Activity     *data.ActivityLog
 }
 
 func NewUIState(sel *selection.Manager, modeMachine *modes.Machine) *UIState {
@@ -47,6 +62,7 @@ func NewUIState(sel *selection.Manager, modeMachine *modes.Machine) *UIState {
 		Modes:         modeMachine,
 		Filter:        NewFilterState(),
 		Fold:          NewFoldState(),
+		History:       NewHistoryState(),
 		WindowFocused: true,
 	}
 }
@@ -13,6 +13,9 @@ const (
 	ModeExternalEdit
 	ModeInfo
 	ModeEstimatePicker
+	ModeDeadlinePicker
+	ModeTrash
+	ModeArchive
 )
 
 type Action int
@@ -26,6 +29,9 @@ const (
 	ActionAddCategory
 	ActionChangePriority
 	ActionChangeEstimate
+	ActionChangeDeadline
+	ActionChangeSection
+	ActionTrackTime
 	ActionMoveItem
 	ActionSort
 	ActionCopy
@@ -86,6 +92,18 @@ func (m *Machine) IsEstimatePicker() bool {
 	return m.current == ModeEstimatePicker
 }
 
+func (m *Machine) IsDeadlinePicker() bool {
+	return m.current == ModeDeadlinePicker
+}
+
+func (m *Machine) IsTrash() bool {
+	return m.current == ModeTrash
+}
+
+func (m *Machine) IsArchive() bool {
+	return m.current == ModeArchive
+}
+
 func (m *Machine) TransitionTo(mode Mode) bool {
 	if !m.canTransition(mode) {
 		return false
@@ -116,6 +134,12 @@ func (m *Machine) canTransition(target Mode) bool {
 		return target == ModeNormal
 	case ModeEstimatePicker:
 		return target == ModeNormal
+	case ModeDeadlinePicker:
+		return target == ModeNormal
+	case ModeTrash:
+		return target == ModeNormal
+	case ModeArchive:
+		return target == ModeNormal
 	}
 	return false
 }
@@ -138,6 +162,12 @@ func (m *Machine) CanPerformAction(action Action) bool {
 		return false
 	case ModeEstimatePicker:
 		return false
+	case ModeDeadlinePicker:
+		return false
+	case ModeTrash:
+		return false
+	case ModeArchive:
+		return false
 	}
 	return false
 }
@@ -197,3 +227,15 @@ func (m *Machine) ToggleInfo() {
 func (m *Machine) ToEstimatePicker() bool {
 	return m.TransitionTo(ModeEstimatePicker)
 }
+
+func (m *Machine) ToDeadlinePicker() bool {
+	return m.TransitionTo(ModeDeadlinePicker)
+}
+
+func (m *Machine) ToTrash() bool {
+	return m.TransitionTo(ModeTrash)
+}
+
+func (m *Machine) ToArchive() bool {
+	return m.TransitionTo(ModeArchive)
+}
@@ -152,6 +152,29 @@ func TestMachine_ConvenienceMethods(t *testing.T) {
 		assert.True(t, m.IsProjectPicker())
 	})
 
+	t.Run("ToDeadlinePicker", func(t *testing.T) {
+		m := NewMachine(ModeNormal)
+		assert.True(t, m.ToDeadlinePicker())
+		assert.True(t, m.IsDeadlinePicker())
+		assert.False(t, m.ToEdit())
+	})
+
+	t.Run("ToTrash", func(t *testing.T) {
+		m := NewMachine(ModeNormal)
+		assert.True(t, m.ToTrash())
+		assert.True(t, m.IsTrash())
+		assert.False(t, m.CanPerformAction(ActionDeleteItem))
+		assert.False(t, m.ToEdit())
+	})
+
+	t.Run("ToArchive", func(t *testing.T) {
+		m := NewMachine(ModeNormal)
+		assert.True(t, m.ToArchive())
+		assert.True(t, m.IsArchive())
+		assert.False(t, m.CanPerformAction(ActionToggleTask))
+		assert.False(t, m.ToTrash())
+	})
+
 	t.Run("ToNormal always works", func(t *testing.T) {
 		m := NewMachine(ModeEdit)
 		m.ToNormal()
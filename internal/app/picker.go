@@ -6,21 +6,18 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"phasionary/internal/domain"
+	"phasionary/internal/data"
 )
 
 const pickerVisibleItems = 10
 
 func (m *model) openProjectPicker() {
-	projects, err := m.deps.Store.ListProjects()
+	projects, err := m.pickerProjects(false)
 	if err != nil {
 		m.ui.StatusMsg = fmt.Sprintf("Error loading projects: %v", err)
 		return
 	}
 
-	order := m.deps.StateManager.GetProjectOrder()
-	projects = orderProjects(projects, order)
-
 	currentIdx := 0
 	for i, p := range projects {
 		if p.ID == m.project.ID {
@@ -38,17 +35,34 @@ func (m *model) openProjectPicker() {
 	m.ui.Modes.ToProjectPicker()
 }
 
-func orderProjects(projects []domain.Project, order []string) []domain.Project {
-	if len(order) == 0 {
-		return projects
+// pickerProjects lists the projects in the order kept in the state, leaving
+// out archived ones unless showArchived is set.
+func (m *model) pickerProjects(showArchived bool) ([]data.ProjectSummary, error) {
+	projects, err := m.deps.Store.ListSummaries()
+	if err != nil {
+		return nil, err
 	}
+	if !showArchived {
+		active := projects[:0]
+		for _, p := range projects {
+			if !p.Archived {
+				active = append(active, p)
+			}
+		}
+		projects = active
+	}
+	return orderProjects(projects, m.deps.StateManager.GetProjectOrder()), nil
+}
 
-	projectMap := make(map[string]domain.Project)
+// orderProjects sorts projects by the saved order, then by name for those
+// not in it. Archived projects come after the others.
+func orderProjects(projects []data.ProjectSummary, order []string) []data.ProjectSummary {
+	projectMap := make(map[string]data.ProjectSummary)
 	for _, p := range projects {
 		projectMap[p.ID] = p
 	}
 
-	var ordered []domain.Project
+	var ordered []data.ProjectSummary
 	seen := make(map[string]bool)
 	for _, id := range order {
 		if p, ok := projectMap[id]; ok {
@@ -57,7 +71,7 @@ func orderProjects(projects []domain.Project, order []string) []domain.Project {
 		}
 	}
 
-	var remaining []domain.Project
+	var remaining []data.ProjectSummary
 	for _, p := range projects {
 		if !seen[p.ID] {
 			remaining = append(remaining, p)
@@ -67,7 +81,11 @@ func orderProjects(projects []domain.Project, order []string) []domain.Project {
 		return strings.ToLower(remaining[i].Name) < strings.ToLower(remaining[j].Name)
 	})
 
-	return append(ordered, remaining...)
+	ordered = append(ordered, remaining...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return !ordered[i].Archived && ordered[j].Archived
+	})
+	return ordered
 }
 
 func (m model) handleProjectPickerKey(msg tea.KeyMsg) (model, tea.Cmd) {
@@ -91,6 +109,10 @@ func (m model) handleProjectPickerKey(msg tea.KeyMsg) (model, tea.Cmd) {
 		}
 	case "d":
 		m.initiateProjectDelete()
+	case "a":
+		m.toggleProjectArchived()
+	case "A":
+		m.toggleShowArchived()
 	case "esc", "q":
 		if m.project.ID == "" {
 			return m, tea.Quit
@@ -101,6 +123,85 @@ func (m model) handleProjectPickerKey(msg tea.KeyMsg) (model, tea.Cmd) {
 	return m, nil
 }
 
+// toggleShowArchived reveals or hides archived projects, keeping the
+// selection on the same project when it is still listed.
+func (m *model) toggleShowArchived() {
+	showArchived := !m.ui.Picker.showArchived
+	projects, err := m.pickerProjects(showArchived)
+	if err != nil {
+		m.ui.StatusMsg = fmt.Sprintf("Error loading projects: %v", err)
+		return
+	}
+	m.ui.Picker.showArchived = showArchived
+	m.setPickerProjects(projects)
+	if showArchived {
+		m.ui.StatusMsg = "Showing archived projects"
+	} else {
+		m.ui.StatusMsg = "Hiding archived projects"
+	}
+}
+
+// toggleProjectArchived archives the selected project, or brings it back
+// when it is archived.
+func (m *model) toggleProjectArchived() {
+	if m.ui.Picker.isOnAddButton() {
+		return
+	}
+	selected := m.ui.Picker.projects[m.ui.Picker.selected]
+	archive := !selected.Archived
+	if selected.ID == m.project.ID {
+		m.project.Archived = archive
+		m.storeTaskUpdate()
+	} else {
+		project, err := m.deps.Store.LoadProject(selected.ID)
+		if err != nil {
+			m.ui.StatusMsg = fmt.Sprintf("Error loading project: %v", err)
+			return
+		}
+		project.Archived = archive
+		if err := m.deps.Store.SaveProject(&project); err != nil {
+			m.ui.StatusMsg = fmt.Sprintf("Error saving project: %v", err)
+			return
+		}
+	}
+
+	projects, err := m.pickerProjects(m.ui.Picker.showArchived)
+	if err != nil {
+		m.ui.StatusMsg = fmt.Sprintf("Error loading projects: %v", err)
+		return
+	}
+	m.setPickerProjects(projects)
+	if archive {
+		m.ui.StatusMsg = fmt.Sprintf("Archived project: %s", selected.Name)
+	} else {
+		m.ui.StatusMsg = fmt.Sprintf("Unarchived project: %s", selected.Name)
+	}
+}
+
+// setPickerProjects replaces the picker's list, keeping the selection on
+// the same project, or at the same place when it is gone.
+func (m *model) setPickerProjects(projects []data.ProjectSummary) {
+	var selectedID string
+	if !m.ui.Picker.isOnAddButton() && m.ui.Picker.selected >= 0 {
+		selectedID = m.ui.Picker.projects[m.ui.Picker.selected].ID
+	}
+	m.ui.Picker.projects = projects
+	for i, p := range projects {
+		if p.ID == selectedID {
+			m.ui.Picker.selected = i
+			m.ui.Picker.ensureVisible()
+			return
+		}
+	}
+	if m.ui.Picker.selected >= len(projects) {
+		m.ui.Picker.selected = len(projects) - 1
+	}
+	if m.ui.Picker.selected < 0 {
+		m.ui.Picker.selected = 0
+	}
+	m.ui.Picker.ensureVisible()
+}
+
 func (m *model) initiateProjectDelete() {
 	if m.ui.Picker.isOnAddButton() {
 		return
@@ -136,10 +237,11 @@ func (m *model) confirmDeleteProject() {
 	}
 
 	m.removeProjectFromOrder(deleteID)
+	m.ui.History.Forget(deleteID)
 	_ = m.deps.StateManager.DeleteFoldedCategories(deleteID)
 
 	if m.project.ID == deleteID {
-		projects, err := m.deps.Store.ListProjects()
+		projects, err := m.pickerProjects(true)
 		if err != nil {
 			m.ui.StatusMsg = fmt.Sprintf("Error loading projects: %v", err)
 			m.ui.Picker.pendingDeleteID = ""
@@ -147,7 +249,11 @@ func (m *model) confirmDeleteProject() {
 			return
 		}
 		if len(projects) > 0 {
-			m.project = projects[0]
+			if project, err := m.deps.Store.LoadProject(projects[0].ID); err == nil {
+				m.project = project
+			}
+			m.ui.History.Track(m.project)
+			m.refreshOtherTimer()
 			_ = m.deps.StateManager.SetLastProjectID(m.project.ID)
 			m.ui.Filter = NewFilterState()
 			m.ui.Fold = NewFoldStateFrom(m.deps.StateManager.GetFoldedCategories(m.project.ID))
@@ -159,7 +265,7 @@ func (m *model) confirmDeleteProject() {
 		}
 	}
 
-	projects, err := m.deps.Store.ListProjects()
+	projects, err := m.pickerProjects(m.ui.Picker.showArchived)
 	if err != nil {
 		m.ui.StatusMsg = fmt.Sprintf("Error reloading projects: %v", err)
 	} else {
@@ -212,6 +318,8 @@ func (m *model) createProjectFromPicker() {
 	_ = m.deps.StateManager.SetProjectOrder(order)
 
 	m.project = project
+	m.ui.History.Track(project)
+	m.refreshOtherTimer()
 	m.ui.Filter = NewFilterState()
 	m.ui.Fold = NewFoldState()
 	positions := rebuildPositions(project.Categories, &m.ui.Filter, &m.ui.Fold)
@@ -251,6 +359,8 @@ func (m *model) selectProject() {
 	_ = m.deps.StateManager.SetLastProjectID(project.ID)
 
 	m.project = project
+	m.ui.History.Track(project)
+	m.refreshOtherTimer()
 	m.ui.Filter = NewFilterState()
 	m.ui.Fold = NewFoldStateFrom(m.deps.StateManager.GetFoldedCategories(project.ID))
 	positions := rebuildPositions(project.Categories, &m.ui.Filter, &m.ui.Fold)
@@ -308,10 +418,19 @@ func (m *model) moveProjectUp() {
 	m.saveProjectOrder()
 }
 
+// saveProjectOrder stores the picker's order. Projects the picker does not
+// show, such as hidden archived ones, keep their place after the others.
 func (m *model) saveProjectOrder() {
-	order := make([]string, len(m.ui.Picker.projects))
-	for i, p := range m.ui.Picker.projects {
-		order[i] = p.ID
+	order := make([]string, 0, len(m.ui.Picker.projects))
+	listed := make(map[string]bool, len(m.ui.Picker.projects))
+	for _, p := range m.ui.Picker.projects {
+		order = append(order, p.ID)
+		listed[p.ID] = true
+	}
+	for _, id := range m.deps.StateManager.GetProjectOrder() {
+		if !listed[id] {
+			order = append(order, id)
+		}
 	}
 	_ = m.deps.StateManager.SetProjectOrder(order)
 }
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"phasionary/internal/data"
+)
+
+func TestOrderProjects_ArchivedLast(t *testing.T) {
+	projects := []data.ProjectSummary{
+		{ID: "a", Name: "Alpha", Archived: true},
+		{ID: "b", Name: "Beta"},
+		{ID: "c", Name: "Gamma"},
+		{ID: "d", Name: "Delta", Archived: true},
+	}
+
+	ids := func(projects []data.ProjectSummary) []string {
+		var ids []string
+		for _, p := range projects {
+			ids = append(ids, p.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"b", "c", "a", "d"}, ids(orderProjects(projects, nil)))
+	assert.Equal(t, []string{"c", "b", "d", "a"}, ids(orderProjects(projects, []string{"d", "c", "a"})))
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/x/ansi"
 
@@ -12,16 +13,17 @@ import (
 	"phasionary/internal/ui"
 )
 
-func renderProjectLine(name string, selected bool, focused bool) string {
+func renderProjectLine(name, section string, selected bool, focused bool) string {
 	prefix := "  "
 	if selected {
 		prefix = "> "
 	}
 	line := fmt.Sprintf("%s■ %s", prefix, name)
+	sectionBadge := " [" + section + "]"
 	if selected {
-		return ui.GetSelectedStyle(focused).Render(line)
+		return ui.GetSelectedStyle(focused).Render(line + sectionBadge)
 	}
-	return ui.HeaderStyle.Render(line)
+	return ui.HeaderStyle.Render(line) + ui.MutedStyle.Render(sectionBadge)
 }
 
 func (m model) renderEditProjectLine() string {
@@ -39,7 +41,7 @@ func (m model) renderEditProjectLine() string {
 	)
 }
 
-func renderCategoryLine(name string, estimateMinutes int, aggregateStatus string, selected bool, folded bool, width int, focused bool) string {
+func renderCategoryLine(name string, estimateMinutes int, tracked time.Duration, aggregateStatus string, selected bool, folded bool, width int, focused bool) string {
 	prefix := "  "
 	if selected {
 		prefix = "> "
@@ -76,13 +78,23 @@ func renderCategoryLine(name string, estimateMinutes int, aggregateStatus string
 		}
 	}
 
-	suffix := statusBadge + estimateBadge
+	trackedBadge := ""
+	trackedBadgeText := formatTrackedBadge(tracked)
+	if trackedBadgeText != "" {
+		if selected {
+			trackedBadge = ui.GetSelectedStyle(focused).Render(trackedBadgeText)
+		} else {
+			trackedBadge = ui.MutedStyle.Render(trackedBadgeText)
+		}
+	}
+
+	suffix := statusBadge + estimateBadge + trackedBadge
 
 	if width <= 0 {
 		return style.Render(prefix+foldIndicator+name) + suffix
 	}
 
-	suffixWidth := len(statusBadgeText) + len(estimateBadgeText)
+	suffixWidth := len(statusBadgeText) + len(estimateBadgeText) + ansi.StringWidth(trackedBadgeText)
 	foldWidth := 2
 	available := safeWidth(width, prefixWidth+foldWidth+suffixWidth)
 	wrapped := ansi.Wrap(name, available, "")
@@ -101,9 +113,9 @@ func renderCategoryLine(name string, estimateMinutes int, aggregateStatus string
 	return strings.Join(result, "\n")
 }
 
-func (m model) renderTaskLine(task domain.Task, selected bool, width int, focused bool) string {
+func (m model) renderTaskLine(task domain.Task, selected bool, width int, focused bool, opts components.TaskLineOptions) string {
 	renderer := components.NewTaskLineRenderer(width, m.deps.CfgManager.Get().StatusDisplay, focused)
-	return renderer.Render(task, selected)
+	return renderer.RenderWithOptions(task, selected, opts)
 }
 
 func statusLabel(status, displayMode string) string {
@@ -154,8 +166,8 @@ func (m model) renderEditCategoryLine() string {
 	return renderCursorLine(m.ui.Edit.input.Value(), m.ui.Edit.input.Position(), m.ui.Width, prefixWidth, prefix, ui.CategoryStyle, cursorStyle)
 }
 
-func (m model) renderEditTaskLine(task domain.Task) string {
-	prefix := "> "
+func (m model) renderEditTaskLine(task domain.Task, depth int) string {
+	prefix := "> " + strings.Repeat("  ", depth)
 	statusText := formatStatus(task.Status, m.deps.CfgManager.Get().StatusDisplay)
 	titleStyle := ui.PriorityStyle(task.Priority)
 	icon := ui.PriorityIcon(task.Priority)
@@ -241,28 +253,32 @@ func (m model) renderEditTaskLine(task domain.Task) string {
 }
 
 func (m model) statusLine() string {
-	filterIndicator := ""
+	indicators := ""
 	if m.ui.Filter.HasActiveFilter() {
-		filterIndicator = " [filtered]"
+		indicators = " [filtered]"
 	}
+	indicators += m.timerIndicator()
 	if m.ui.StatusMsg != "" {
-		return ui.StatusLineStyle.Render(m.ui.StatusMsg + filterIndicator)
+		return ui.StatusLineStyle.Render(m.ui.StatusMsg + indicators)
 	}
 	position, ok := m.selectedPosition()
 	if !ok {
-		return ui.StatusLineStyle.Render("No items to display." + filterIndicator)
+		return ui.StatusLineStyle.Render("No items to display." + indicators)
 	}
 	if position.Kind == focusProject {
-		summary := fmt.Sprintf("Project: %s%s", m.project.Name, filterIndicator)
+		summary := fmt.Sprintf("Project: %s%s", m.project.Name, indicators)
 		return ui.StatusLineStyle.Render(summary)
 	}
 	category := m.project.Categories[position.CategoryIndex]
 	if position.Kind == focusCategory {
-		summary := fmt.Sprintf("Category: %s (%d tasks)%s", category.Name, len(category.Tasks), filterIndicator)
+		summary := fmt.Sprintf("Category: %s (%d tasks)%s", category.Name, category.CountTasks(), indicators)
 		return ui.StatusLineStyle.Render(summary)
 	}
-	task := category.Tasks[position.TaskIndex]
-	summary := fmt.Sprintf("Selected: %s / %s (%s)%s", category.Name, task.Title, task.Status, filterIndicator)
+	task := m.taskAt(position)
+	if task == nil {
+		return ui.StatusLineStyle.Render("No items to display." + indicators)
+	}
+	summary := fmt.Sprintf("Selected: %s / %s (%s)%s", category.Name, task.Title, task.Status, indicators)
 	return ui.StatusLineStyle.Render(summary)
 }
 
@@ -285,11 +301,17 @@ func (m model) confirmDeleteView() string {
 	}
 	var message string
 	if position.Kind == focusTask {
-		task := m.project.Categories[position.CategoryIndex].Tasks[position.TaskIndex]
+		task := m.taskAt(position)
+		if task == nil {
+			return ""
+		}
 		message = fmt.Sprintf("Delete task %q?", truncateText(task.Title, 30))
+		if _, total := task.SubtaskProgress(); total > 0 {
+			message = fmt.Sprintf("Delete task %q and %d subtasks?", truncateText(task.Title, 30), total)
+		}
 	} else {
 		cat := m.project.Categories[position.CategoryIndex]
-		message = fmt.Sprintf("Delete category %q and %d tasks?", truncateText(cat.Name, 30), len(cat.Tasks))
+		message = fmt.Sprintf("Delete category %q and %d tasks?", truncateText(cat.Name, 30), cat.CountTasks())
 	}
 	lines := []string{
 		message,
@@ -325,7 +347,7 @@ func (m model) helpView() string {
 		"  G             jump to last item",
 		"  }/{           next/previous category",
 		"  zz            center selection on screen",
-		"  Tab/za        fold/unfold category",
+		"  Tab/za        fold/unfold category or subtasks",
 		"  zc            fold all categories",
 		"  zo            unfold all categories",
 		"  P             switch project",
@@ -333,18 +355,27 @@ func (m model) helpView() string {
 		ui.DialogTitleStyle.Render("Actions:"),
 		"  a             add new task",
 		"  A             add new category",
+		"  +             add subtask",
+		"  >/<           nest under task above/move out of parent",
 		"  enter         edit selected item",
 		"  e             edit in external editor",
 		"  space         toggle task status",
 		"  J/K           reorder task/category up/down",
 		"  s/S           sort tasks by status",
-		"  f             filter tasks by status",
+		"  f             filter tasks by status or tag",
+		"  v             cycle section view (current/future/past/all)",
+		"  m             move task to next section",
 		"  h/l           change priority",
 		"  t             set time estimate",
+		"  D             set deadline",
+		"  T             start/stop timer",
 		"  y             copy selected text",
-		"  x             mark task for cut",
-		"  p             paste cut task",
+		"  x             mark task or category for cut",
+		"  p             paste cut task or category",
 		"  d             delete selected item",
+		"  u/ctrl+r      undo/redo",
+		"  X             open trash (restore deleted items)",
+		"  Z             open archive (archive finished tasks, restore)",
 		"  i             show item info",
 		"  o             options",
 		"  ?             toggle help",
@@ -400,6 +431,25 @@ func (m model) filterView() string {
 		}
 		lines = append(lines, line)
 	}
+	if tags := m.ui.Filter.AvailableTags(); len(tags) > 0 {
+		lines = append(lines, "", ui.DialogTitleStyle.Render("Filter by Tag:"), "")
+		for i, tag := range tags {
+			index := len(filterStatuses) + i
+			prefix := "  "
+			if index == m.ui.Filter.Selected() {
+				prefix = "> "
+			}
+			checkbox := "[ ]"
+			if m.ui.Filter.IsTagEnabled(tag) {
+				checkbox = "[x]"
+			}
+			line := fmt.Sprintf("%s%s #%s", prefix, checkbox, tag)
+			if index == m.ui.Filter.Selected() {
+				line = ui.SelectedStyle.Render(line)
+			}
+			lines = append(lines, line)
+		}
+	}
 	lines = append(lines, "", ui.DialogHintStyle.Render("j/k navigate | space toggle | q/esc/f close"))
 	return ui.HelpDialogStyle.Render(strings.Join(lines, "\n"))
 }
@@ -428,15 +478,18 @@ func (m model) projectPickerView() string {
 		if isSelected {
 			prefix = "> "
 		}
-		name := p.Name
+		name, suffix := p.Name, ""
 		if p.ID == m.project.ID {
-			name += " (current)"
+			suffix = " (current)"
 		}
-		line := prefix + name
+		if p.Archived {
+			suffix += " (archived)"
+		}
+		line := prefix + name + suffix
 		if isSelected {
 			line = ui.SelectedStyle.Render(line)
-		} else if p.ID == m.project.ID {
-			line = prefix + p.Name + ui.DialogHintStyle.Render(" (current)")
+		} else if suffix != "" {
+			line = prefix + name + ui.DialogHintStyle.Render(suffix)
 		}
 		lines = append(lines, line)
 	}
@@ -445,7 +498,10 @@ func (m model) projectPickerView() string {
 		lines = append(lines, ui.DialogHintStyle.Render("  ↓ more below"))
 	}
 
-	hintText := "j/k navigate | J/K reorder | enter select | d delete | esc cancel"
+	hintText := "j/k navigate | J/K reorder | enter select | d delete | a archive | A show archived | esc cancel"
+	if m.ui.Picker.showArchived {
+		hintText = "j/k navigate | J/K reorder | enter select | d delete | a archive | A hide archived | esc cancel"
+	}
 	if m.ui.Picker.isAdding {
 		hintText = "enter create | esc cancel"
 	}
@@ -490,16 +546,19 @@ func (m model) infoView() string {
 	case focusCategory:
 		lines = m.categoryInfoLines(pos.CategoryIndex)
 	case focusTask:
-		lines = m.taskInfoLines(pos.CategoryIndex, pos.TaskIndex)
+		lines = m.taskInfoLines(pos)
 	}
 
 	lines = append(lines, "", ui.DialogHintStyle.Render("i/esc/q close"))
 	return ui.HelpDialogStyle.Render(strings.Join(lines, "\n"))
 }
 
-func (m model) taskInfoLines(catIdx, taskIdx int) []string {
-	task := m.project.Categories[catIdx].Tasks[taskIdx]
-	category := m.project.Categories[catIdx]
+func (m model) taskInfoLines(pos focusPosition) []string {
+	task := m.taskAt(pos)
+	if task == nil {
+		return nil
+	}
+	category := m.project.Categories[pos.CategoryIndex]
 
 	statusDisplay := formatStatusLabel(task.Status)
 	priorityDisplay := formatPriorityLabel(task.Priority)
@@ -525,12 +584,34 @@ func (m model) taskInfoLines(catIdx, taskIdx int) []string {
 	}
 
 	estimateDisplay := FormatEstimateLabel(task.EstimateMinutes)
+	if total := task.TotalEstimateMinutes(); task.HasSubtasks() && total != task.EstimateMinutes {
+		estimateDisplay += fmt.Sprintf(" (%s with subtasks)", FormatEstimateLabel(total))
+	}
+	now := time.Now()
 
 	lines = append(lines,
 		fmt.Sprintf("Status:   %s", statusDisplay),
 		fmt.Sprintf("Priority: %s", priorityDisplay),
 		fmt.Sprintf("Estimate: %s", estimateDisplay),
+		fmt.Sprintf("Tracked:  %s", formatTrackedLabel(task, now)),
+		fmt.Sprintf("Due:      %s", FormatDeadlineLabel(task.Deadline, now)),
+		fmt.Sprintf("Repeats:  %s", formatRecurrenceLabel(task.Recurrence)),
+		fmt.Sprintf("Section:  %s", task.SectionName()),
 		fmt.Sprintf("Category: %s", category.Name),
+	)
+	if done, total := task.SubtaskProgress(); total > 0 {
+		lines = append(lines, fmt.Sprintf("Subtasks: %d/%d done", done, total))
+	}
+	if len(task.Tags) > 0 {
+		lines = append(lines, fmt.Sprintf("Tags:     %s", formatTagList(task.Tags)))
+	}
+	if blockers := m.project.OpenBlockers(task); len(blockers) > 0 {
+		lines = append(lines, "Blocked by:")
+		for _, blocker := range blockers {
+			lines = append(lines, "  "+truncateText(blocker.Title, infoMaxWidth-5))
+		}
+	}
+	lines = append(lines,
 		"",
 		fmt.Sprintf("Created:  %s", FormatDateWithRelative(task.CreatedAt)),
 		fmt.Sprintf("Updated:  %s", FormatDateWithRelative(task.UpdatedAt)),
@@ -540,6 +621,55 @@ func (m model) taskInfoLines(catIdx, taskIdx int) []string {
 		lines = append(lines, fmt.Sprintf("Completed: %s", FormatDateWithRelative(task.CompletionDate)))
 	}
 
+	if task.Description != "" {
+		lines = append(lines, "", "Description:")
+		lines = append(lines, wrapInfoText(task.Description, infoMaxWidth)...)
+	}
+	if task.Notes != "" {
+		lines = append(lines, "", "Notes:")
+		lines = append(lines, wrapInfoText(task.Notes, infoMaxWidth)...)
+	}
+
+	if events := m.ui.InfoActivity; len(events) > 0 {
+		lines = append(lines, "", "History:")
+		if len(events) > infoActivityLimit {
+			lines = append(lines, ui.DialogHintStyle.Render(fmt.Sprintf("  … %d earlier changes", len(events)-infoActivityLimit)))
+			events = events[len(events)-infoActivityLimit:]
+		}
+		for _, event := range events {
+			line := fmt.Sprintf("  %s  %s", formatActivityTime(event.At), event.Summary())
+			lines = append(lines, truncateText(line, infoMaxWidth))
+		}
+	}
+
+	return lines
+}
+
+func formatRecurrenceLabel(r *domain.Recurrence) string {
+	if r == nil {
+		return "Never"
+	}
+	return r.String()
+}
+
+func formatTagList(tags []string) string {
+	return strings.TrimSpace(domain.FormatTitleWithTags("", tags))
+}
+
+func wrapInfoText(text string, width int) []string {
+	const indent = "  "
+	available := safeWidth(width, len(indent))
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		if strings.TrimSpace(paragraph) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		wrapped := ansi.Wrap(paragraph, available, "")
+		for _, line := range strings.Split(wrapped, "\n") {
+			lines = append(lines, indent+line)
+		}
+	}
 	return lines
 }
 
@@ -551,7 +681,7 @@ func (m model) categoryInfoLines(catIdx int) []string {
 	completedCount := 0
 	cancelledCount := 0
 
-	for _, task := range category.Tasks {
+	domain.WalkTasks(category.Tasks, func(task *domain.Task, _ int) {
 		switch task.Status {
 		case domain.StatusTodo:
 			todoCount++
@@ -562,7 +692,7 @@ func (m model) categoryInfoLines(catIdx int) []string {
 		case domain.StatusCancelled:
 			cancelledCount++
 		}
-	}
+	})
 
 	estimateDisplay := FormatEstimateLabel(category.EstimateMinutes)
 
@@ -571,6 +701,7 @@ func (m model) categoryInfoLines(catIdx int) []string {
 		"",
 		fmt.Sprintf("Name:     %s", category.Name),
 		fmt.Sprintf("Estimate: %s", estimateDisplay),
+		fmt.Sprintf("Tracked:  %s", domain.FormatDuration(category.TrackedDuration(time.Now()))),
 		fmt.Sprintf("Created:  %s", FormatDateWithRelative(category.CreatedAt)),
 	}
 
@@ -580,7 +711,7 @@ func (m model) categoryInfoLines(catIdx int) []string {
 
 	lines = append(lines,
 		"",
-		fmt.Sprintf("Total Tasks: %d", len(category.Tasks)),
+		fmt.Sprintf("Total Tasks: %d", category.CountTasks()),
 		"",
 		"Task Breakdown:",
 		fmt.Sprintf("  Todo:        %d", todoCount),
@@ -600,8 +731,8 @@ func (m model) projectInfoLines() []string {
 	cancelledCount := 0
 
 	for _, cat := range m.project.Categories {
-		totalTasks += len(cat.Tasks)
-		for _, task := range cat.Tasks {
+		domain.WalkTasks(cat.Tasks, func(task *domain.Task, _ int) {
+			totalTasks++
 			switch task.Status {
 			case domain.StatusTodo:
 				todoCount++
@@ -612,7 +743,7 @@ func (m model) projectInfoLines() []string {
 			case domain.StatusCancelled:
 				cancelledCount++
 			}
-		}
+		})
 	}
 
 	lines := []string{
@@ -696,3 +827,26 @@ func (m model) estimatePickerView() string {
 	lines = append(lines, "", ui.DialogHintStyle.Render("j/k navigate | enter select | esc cancel"))
 	return ui.HelpDialogStyle.Render(strings.Join(lines, "\n"))
 }
+
+func (m model) deadlinePickerView() string {
+	lines := []string{ui.DialogTitleStyle.Render("Deadline"), ""}
+
+	for i, option := range m.ui.DeadlinePicker.Options {
+		prefix := "  "
+		if i == m.ui.DeadlinePicker.Selected {
+			prefix = "> "
+		}
+		label := option.Label
+		if option.Value != "" {
+			label = fmt.Sprintf("%-12s %s", option.Label, option.Value)
+		}
+		line := prefix + label
+		if i == m.ui.DeadlinePicker.Selected {
+			line = ui.SelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "", ui.DialogHintStyle.Render("j/k navigate | enter select | esc cancel"))
+	return ui.HelpDialogStyle.Render(strings.Join(lines, "\n"))
+}
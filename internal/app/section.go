@@ -0,0 +1,51 @@
+package app
+
+import (
+	"phasionary/internal/app/modes"
+	"phasionary/internal/domain"
+)
+
+func (m *model) cycleSectionView() {
+	selectedID := ""
+	if task, _, ok := m.selectedTask(); ok {
+		selectedID = task.ID
+	}
+
+	m.ui.Filter.CycleSection()
+	m.rebuildPositions()
+	if selectedID != "" {
+		m.selectTaskByID(selectedID)
+	}
+	m.ensureVisible()
+	m.ui.StatusMsg = "Viewing " + m.ui.Filter.SectionLabel() + " tasks"
+}
+
+func (m *model) moveTaskSection() {
+	if !m.ui.Modes.CanPerformAction(modes.ActionChangeSection) {
+		return
+	}
+	task, _, ok := m.selectedTask()
+	if !ok {
+		return
+	}
+	next := task.NextSection()
+	if err := task.SetSection(next); err != nil {
+		m.ui.StatusMsg = err.Error()
+		return
+	}
+	m.storeTaskUpdate()
+	m.rebuildPositions()
+	m.ensureVisible()
+	m.ui.StatusMsg = "Moved to " + next
+}
+
+// viewSection is the section new tasks land in so that they stay visible
+// in the current view.
+func (m *model) viewSection() string {
+	switch m.ui.Filter.Section() {
+	case domain.SectionFuture:
+		return domain.SectionFuture
+	default:
+		return domain.SectionCurrent
+	}
+}
@@ -12,6 +12,7 @@ type Position struct {
 	Kind          FocusKind
 	CategoryIndex int
 	TaskIndex     int
+	SubtaskPath   []int
 }
 
 type Manager struct {
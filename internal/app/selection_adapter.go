@@ -9,6 +9,7 @@ func toSelectionPositions(positions []focusPosition) []selection.Position {
 			Kind:          toSelectionKind(p.Kind),
 			CategoryIndex: p.CategoryIndex,
 			TaskIndex:     p.TaskIndex,
+			SubtaskPath:   p.SubtaskPath,
 		}
 	}
 	return result
@@ -30,6 +31,7 @@ func fromSelectionPosition(p selection.Position) focusPosition {
 		Kind:          fromSelectionKind(p.Kind),
 		CategoryIndex: p.CategoryIndex,
 		TaskIndex:     p.TaskIndex,
+		SubtaskPath:   p.SubtaskPath,
 	}
 }
 
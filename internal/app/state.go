@@ -1,8 +1,12 @@
 package app
 
 import (
+	"slices"
+	"sort"
+
 	"github.com/charmbracelet/bubbles/textinput"
 
+	"phasionary/internal/data"
 	"phasionary/internal/domain"
 )
 
@@ -13,18 +17,109 @@ var filterStatuses = []string{
 	domain.StatusCancelled,
 }
 
+// sectionViews is the cycle order for the section view; an empty entry
+// shows every section.
+var sectionViews = []string{
+	domain.SectionCurrent,
+	domain.SectionFuture,
+	domain.SectionPast,
+	"",
+}
+
 type FilterState struct {
-	selected int
-	enabled  map[string]bool
+	selected    int
+	enabled     map[string]bool
+	tags        []string
+	enabledTags map[string]bool
+	section     string
 }
 
 func NewFilterState() FilterState {
 	return FilterState{
-		selected: 0,
-		enabled:  make(map[string]bool),
+		selected:    0,
+		enabled:     make(map[string]bool),
+		enabledTags: make(map[string]bool),
+		section:     domain.SectionCurrent,
+	}
+}
+
+// IsTaskVisible applies the section, status and tag filters to a top-level
+// task. A task matches a tag when it or one of its subtasks carries it.
+func (f *FilterState) IsTaskVisible(task domain.Task) bool {
+	if f.section != "" && task.SectionName() != f.section {
+		return false
+	}
+	return f.IsStatusVisible(task.Status) && f.isTagVisible(task)
+}
+
+func (f *FilterState) isTagVisible(task domain.Task) bool {
+	if len(f.enabledTags) == 0 {
+		return true
+	}
+	for tag := range f.enabledTags {
+		if task.HasTagInTree(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAvailableTags sets the tags offered in the filter dialog. Tags that are
+// still enabled stay listed even if no task uses them any more.
+func (f *FilterState) SetAvailableTags(tags []string) {
+	f.tags = append([]string(nil), tags...)
+	for tag := range f.enabledTags {
+		if !slices.Contains(f.tags, tag) {
+			f.tags = append(f.tags, tag)
+		}
+	}
+	sort.Strings(f.tags)
+	if f.selected >= f.itemCount() {
+		f.selected = f.itemCount() - 1
+	}
+}
+
+func (f *FilterState) AvailableTags() []string {
+	return f.tags
+}
+
+func (f *FilterState) ToggleTag(tag string) {
+	if f.enabledTags[tag] {
+		delete(f.enabledTags, tag)
+	} else {
+		f.enabledTags[tag] = true
 	}
 }
 
+func (f *FilterState) IsTagEnabled(tag string) bool {
+	return f.enabledTags[tag]
+}
+
+func (f *FilterState) itemCount() int {
+	return len(filterStatuses) + len(f.tags)
+}
+
+func (f *FilterState) Section() string {
+	return f.section
+}
+
+func (f *FilterState) CycleSection() {
+	for i, section := range sectionViews {
+		if section == f.section {
+			f.section = sectionViews[(i+1)%len(sectionViews)]
+			return
+		}
+	}
+	f.section = domain.SectionCurrent
+}
+
+func (f *FilterState) SectionLabel() string {
+	if f.section == "" {
+		return "all"
+	}
+	return f.section
+}
+
 func (f *FilterState) IsStatusVisible(status string) bool {
 	if len(f.enabled) == 0 {
 		return true
@@ -41,7 +136,7 @@ func (f *FilterState) Toggle(status string) {
 }
 
 func (f *FilterState) HasActiveFilter() bool {
-	return len(f.enabled) > 0
+	return len(f.enabled) > 0 || len(f.enabledTags) > 0
 }
 
 func (f *FilterState) MoveUp() {
@@ -51,14 +146,17 @@ func (f *FilterState) MoveUp() {
 }
 
 func (f *FilterState) MoveDown() {
-	if f.selected < len(filterStatuses)-1 {
+	if f.selected < f.itemCount()-1 {
 		f.selected++
 	}
 }
 
 func (f *FilterState) ToggleSelected() {
-	if f.selected >= 0 && f.selected < len(filterStatuses) {
+	switch {
+	case f.selected >= 0 && f.selected < len(filterStatuses):
 		f.Toggle(filterStatuses[f.selected])
+	case f.selected >= len(filterStatuses) && f.selected < f.itemCount():
+		f.ToggleTag(f.tags[f.selected-len(filterStatuses)])
 	}
 }
 
@@ -75,12 +173,13 @@ type OptionsState struct {
 }
 
 type ProjectPickerState struct {
-	projects        []domain.Project
+	projects        []data.ProjectSummary
 	selected        int
 	scrollOffset    int
 	isAdding        bool
 	input           textinput.Model
 	pendingDeleteID string
+	showArchived    bool
 }
 
 func (p *ProjectPickerState) reset() {
@@ -90,6 +189,7 @@ func (p *ProjectPickerState) reset() {
 	p.isAdding = false
 	p.input = textinput.Model{}
 	p.pendingDeleteID = ""
+	p.showArchived = false
 }
 
 func (p *ProjectPickerState) totalItems() int {
@@ -111,6 +211,67 @@ func (p *ProjectPickerState) cancelAdding() {
 	p.input = textinput.Model{}
 }
 
+// TrashViewState holds the trash listing shown by the trash view.
+type TrashViewState struct {
+	items        []data.TrashItem
+	selected     int
+	scrollOffset int
+}
+
+func (t *TrashViewState) moveSelection(delta int) {
+	t.selected += delta
+	if t.selected >= len(t.items) {
+		t.selected = len(t.items) - 1
+	}
+	if t.selected < 0 {
+		t.selected = 0
+	}
+	if t.selected < t.scrollOffset {
+		t.scrollOffset = t.selected
+	}
+	if t.selected >= t.scrollOffset+pickerVisibleItems {
+		t.scrollOffset = t.selected - pickerVisibleItems + 1
+	}
+}
+
+func (t *TrashViewState) selectedItem() (data.TrashItem, bool) {
+	if t.selected < 0 || t.selected >= len(t.items) {
+		return data.TrashItem{}, false
+	}
+	return t.items[t.selected], true
+}
+
+// ArchiveViewState holds the archived tasks shown by the archive view,
+// newest first.
+type ArchiveViewState struct {
+	items        []domain.ArchivedTask
+	selected     int
+	scrollOffset int
+}
+
+func (a *ArchiveViewState) moveSelection(delta int) {
+	a.selected += delta
+	if a.selected >= len(a.items) {
+		a.selected = len(a.items) - 1
+	}
+	if a.selected < 0 {
+		a.selected = 0
+	}
+	if a.selected < a.scrollOffset {
+		a.scrollOffset = a.selected
+	}
+	if a.selected >= a.scrollOffset+pickerVisibleItems {
+		a.scrollOffset = a.selected - pickerVisibleItems + 1
+	}
+}
+
+func (a *ArchiveViewState) selectedItem() (domain.ArchivedTask, bool) {
+	if a.selected < 0 || a.selected >= len(a.items) {
+		return domain.ArchivedTask{}, false
+	}
+	return a.items[a.selected], true
+}
+
 type FoldState struct {
 	folded map[string]bool
 }
@@ -137,15 +298,16 @@ func (f *FoldState) FoldedIDs() []string {
 	return ids
 }
 
-func (f *FoldState) IsFolded(categoryID string) bool {
-	return f.folded[categoryID]
+// IsFolded reports whether a category or a task with subtasks is folded.
+func (f *FoldState) IsFolded(id string) bool {
+	return f.folded[id]
 }
 
-func (f *FoldState) Toggle(categoryID string) {
-	if f.folded[categoryID] {
-		delete(f.folded, categoryID)
+func (f *FoldState) Toggle(id string) {
+	if f.folded[id] {
+		delete(f.folded, id)
 	} else {
-		f.folded[categoryID] = true
+		f.folded[id] = true
 	}
 }
 
@@ -189,3 +351,94 @@ func newEditState(value string, isAdding bool, newID string, kind focusKind) Edi
 		itemType:  kind,
 	}
 }
+
+// historyLimit caps the undo steps kept for each project.
+const historyLimit = 100
+
+// HistoryState keeps undo and redo snapshots per project for the session,
+// so switching projects does not lose them.
+type HistoryState struct {
+	projects map[string]*projectHistory
+}
+
+type projectHistory struct {
+	saved domain.Project // state as of the last save
+	undo  []domain.Project
+	redo  []domain.Project
+}
+
+func NewHistoryState() HistoryState {
+	return HistoryState{projects: make(map[string]*projectHistory)}
+}
+
+func (h *HistoryState) entry(project domain.Project) *projectHistory {
+	if h.projects == nil {
+		h.projects = make(map[string]*projectHistory)
+	}
+	entry, ok := h.projects[project.ID]
+	if !ok {
+		entry = &projectHistory{saved: project.Clone()}
+		h.projects[project.ID] = entry
+	}
+	return entry
+}
+
+// Track sets the baseline for a project that was just loaded, keeping any
+// history recorded for it earlier in the session.
+func (h *HistoryState) Track(project domain.Project) {
+	h.entry(project).saved = project.Clone()
+}
+
+// Record pushes the previously saved state onto the undo stack after a
+// change to project has been saved.
+func (h *HistoryState) Record(project domain.Project) {
+	entry := h.entry(project)
+	entry.undo = append(entry.undo, entry.saved)
+	if len(entry.undo) > historyLimit {
+		entry.undo = entry.undo[len(entry.undo)-historyLimit:]
+	}
+	entry.redo = nil
+	entry.saved = project.Clone()
+}
+
+// Undo applies the state before the last recorded change, typically by
+// saving it. The history only moves once apply succeeds, so a failed save
+// leaves both stacks as they were. It reports whether there was anything
+// to undo.
+func (h *HistoryState) Undo(current domain.Project, apply func(domain.Project) error) (bool, error) {
+	entry := h.entry(current)
+	if len(entry.undo) == 0 {
+		return false, nil
+	}
+	if err := apply(entry.undo[len(entry.undo)-1]); err != nil {
+		return true, err
+	}
+	entry.undo = entry.undo[:len(entry.undo)-1]
+	entry.redo = append(entry.redo, current.Clone())
+	return true, nil
+}
+
+// Redo applies the state that the last undo reverted, like Undo.
+func (h *HistoryState) Redo(current domain.Project, apply func(domain.Project) error) (bool, error) {
+	entry := h.entry(current)
+	if len(entry.redo) == 0 {
+		return false, nil
+	}
+	if err := apply(entry.redo[len(entry.redo)-1]); err != nil {
+		return true, err
+	}
+	entry.redo = entry.redo[:len(entry.redo)-1]
+	entry.undo = append(entry.undo, current.Clone())
+	return true, nil
+}
+
+// Saved returns the project as it was last saved from this session, the
+// common ancestor when merging with changes made elsewhere.
+func (h *HistoryState) Saved(project domain.Project) domain.Project {
+	return h.entry(project).saved.Clone()
+}
+
+// Forget drops the history of a deleted project.
+func (h *HistoryState) Forget(projectID string) {
+	delete(h.projects, projectID)
+}
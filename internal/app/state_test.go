@@ -0,0 +1,156 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"phasionary/internal/domain"
+)
+
+func TestFilterState_Section(t *testing.T) {
+	t.Run("defaults to current section", func(t *testing.T) {
+		f := NewFilterState()
+		assert.Equal(t, domain.SectionCurrent, f.Section())
+		assert.True(t, f.IsTaskVisible(domain.Task{Status: domain.StatusTodo}))
+		assert.False(t, f.IsTaskVisible(domain.Task{Status: domain.StatusTodo, Section: domain.SectionFuture}))
+	})
+
+	t.Run("cycles through sections and all", func(t *testing.T) {
+		f := NewFilterState()
+		f.CycleSection()
+		assert.Equal(t, domain.SectionFuture, f.Section())
+		f.CycleSection()
+		assert.Equal(t, domain.SectionPast, f.Section())
+		f.CycleSection()
+		assert.Equal(t, "", f.Section())
+		assert.Equal(t, "all", f.SectionLabel())
+		f.CycleSection()
+		assert.Equal(t, domain.SectionCurrent, f.Section())
+	})
+
+	t.Run("all view still applies status filter", func(t *testing.T) {
+		f := NewFilterState()
+		for f.Section() != "" {
+			f.CycleSection()
+		}
+		f.Toggle(domain.StatusCompleted)
+		assert.True(t, f.IsTaskVisible(domain.Task{Status: domain.StatusCompleted, Section: domain.SectionPast}))
+		assert.False(t, f.IsTaskVisible(domain.Task{Status: domain.StatusTodo, Section: domain.SectionFuture}))
+	})
+}
+
+func TestFilterState_Tags(t *testing.T) {
+	tagged := domain.Task{Status: domain.StatusTodo, Tags: []string{"ui"}}
+	nested := domain.Task{Status: domain.StatusTodo, Subtasks: []domain.Task{{Tags: []string{"ui"}}}}
+	plain := domain.Task{Status: domain.StatusTodo}
+
+	f := NewFilterState()
+	f.SetAvailableTags([]string{"ui", "api"})
+	assert.Equal(t, []string{"api", "ui"}, f.AvailableTags())
+	assert.True(t, f.IsTaskVisible(plain))
+
+	f.ToggleTag("ui")
+	assert.True(t, f.HasActiveFilter())
+	assert.True(t, f.IsTaskVisible(tagged))
+	assert.True(t, f.IsTaskVisible(nested))
+	assert.False(t, f.IsTaskVisible(plain))
+
+	t.Run("selection moves past statuses into tags", func(t *testing.T) {
+		f := NewFilterState()
+		f.SetAvailableTags([]string{"api"})
+		for range filterStatuses {
+			f.MoveDown()
+		}
+		f.MoveDown()
+		assert.Equal(t, len(filterStatuses), f.Selected())
+		f.ToggleSelected()
+		assert.True(t, f.IsTagEnabled("api"))
+	})
+
+	t.Run("enabled tags stay listed", func(t *testing.T) {
+		f := NewFilterState()
+		f.ToggleTag("gone")
+		f.SetAvailableTags(nil)
+		assert.Equal(t, []string{"gone"}, f.AvailableTags())
+	})
+}
+
+func TestHistoryState(t *testing.T) {
+	project := domain.Project{ID: "p1", Name: "v1"}
+	h := NewHistoryState()
+	h.Track(project)
+	restore := func(p domain.Project) error {
+		project = p
+		h.Track(p)
+		return nil
+	}
+
+	ok, err := h.Undo(project, restore)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	project.Name = "v2"
+	h.Record(project)
+	project.Name = "v3"
+	h.Record(project)
+
+	ok, _ = h.Undo(project, restore)
+	assert.True(t, ok)
+	assert.Equal(t, "v2", project.Name)
+	_, _ = h.Undo(project, restore)
+	assert.Equal(t, "v1", project.Name)
+
+	ok, _ = h.Redo(project, restore)
+	assert.True(t, ok)
+	assert.Equal(t, "v2", project.Name)
+	assert.Equal(t, "v2", h.Saved(project).Name)
+
+	project.Name = "v4"
+	h.Record(project)
+	ok, _ = h.Redo(project, restore)
+	assert.False(t, ok, "a new change clears redo")
+
+	t.Run("failed restore keeps the history", func(t *testing.T) {
+		failed := errors.New("disk full")
+		ok, err := h.Undo(project, func(domain.Project) error { return failed })
+		assert.True(t, ok)
+		assert.ErrorIs(t, err, failed)
+		assert.Equal(t, "v4", project.Name)
+
+		ok, _ = h.Redo(project, restore)
+		assert.False(t, ok, "nothing was undone")
+		_, _ = h.Undo(project, restore)
+		assert.Equal(t, "v2", project.Name)
+		_, _ = h.Redo(project, restore)
+		assert.Equal(t, "v4", project.Name)
+	})
+
+	t.Run("history survives switching projects", func(t *testing.T) {
+		other := domain.Project{ID: "p2", Name: "other"}
+		h.Track(other)
+		h.Track(project)
+		ok, _ := h.Undo(project, restore)
+		assert.True(t, ok)
+		assert.Equal(t, "v2", project.Name)
+	})
+
+	t.Run("bounded per project", func(t *testing.T) {
+		p := domain.Project{ID: "p3"}
+		h.Track(p)
+		for i := 0; i < historyLimit+10; i++ {
+			h.Record(p)
+		}
+		steps := 0
+		for {
+			ok, _ := h.Undo(p, func(domain.Project) error { return nil })
+			if !ok {
+				break
+			}
+			steps++
+		}
+		assert.Equal(t, historyLimit, steps)
+	})
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"phasionary/internal/app/modes"
+	"phasionary/internal/app/selection"
+	"phasionary/internal/domain"
+)
+
+// visibleTask is a task that passes the filter and is not hidden under a
+// folded parent.
+type visibleTask struct {
+	task      *domain.Task
+	taskIndex int
+	path      []int
+}
+
+func (v visibleTask) depth() int {
+	return len(v.path)
+}
+
+// visibleTasks lists the category's tasks in display order. The section
+// view applies to top-level tasks only; subtasks follow their parent.
+func visibleTasks(category *domain.Category, filter *FilterState, fold *FoldState) []visibleTask {
+	var result []visibleTask
+	for tIndex := range category.Tasks {
+		task := &category.Tasks[tIndex]
+		if filter != nil && !filter.IsTaskVisible(*task) {
+			continue
+		}
+		result = append(result, visibleTask{task: task, taskIndex: tIndex})
+		result = appendVisibleSubtasks(result, task, tIndex, nil, filter, fold)
+	}
+	return result
+}
+
+func appendVisibleSubtasks(result []visibleTask, parent *domain.Task, taskIndex int, path []int, filter *FilterState, fold *FoldState) []visibleTask {
+	if fold != nil && fold.IsFolded(parent.ID) {
+		return result
+	}
+	for i := range parent.Subtasks {
+		sub := &parent.Subtasks[i]
+		if filter != nil && !filter.IsStatusVisible(sub.Status) {
+			continue
+		}
+		subPath := append(append([]int(nil), path...), i)
+		result = append(result, visibleTask{task: sub, taskIndex: taskIndex, path: subPath})
+		result = appendVisibleSubtasks(result, sub, taskIndex, subPath, filter, fold)
+	}
+	return result
+}
+
+func (m *model) taskAt(pos focusPosition) *domain.Task {
+	if pos.Kind != focusTask || pos.CategoryIndex < 0 || pos.CategoryIndex >= len(m.project.Categories) {
+		return nil
+	}
+	return m.project.Categories[pos.CategoryIndex].TaskAt(pos.TaskIndex, pos.SubtaskPath)
+}
+
+func (m *model) selectedTask() (*domain.Task, focusPosition, bool) {
+	pos, ok := m.selectedPosition()
+	if !ok {
+		return nil, pos, false
+	}
+	task := m.taskAt(pos)
+	return task, pos, task != nil
+}
+
+func (m *model) selectTaskByID(id string) bool {
+	return m.ui.Selection.SelectByPredicate(func(p selection.Position) bool {
+		if p.Kind != selection.FocusTask {
+			return false
+		}
+		task := m.taskAt(fromSelectionPosition(p))
+		return task != nil && task.ID == id
+	})
+}
+
+func (m *model) selectCategoryByID(id string) bool {
+	return m.ui.Selection.SelectByPredicate(func(p selection.Position) bool {
+		return p.Kind == selection.FocusCategory && p.CategoryIndex >= 0 &&
+			m.project.Categories[p.CategoryIndex].ID == id
+	})
+}
+
+func (m *model) unfoldTask(id string) {
+	if m.ui.Fold.IsFolded(id) {
+		m.ui.Fold.Toggle(id)
+		m.saveFoldState()
+	}
+}
+
+func (m *model) startAddingSubtask() {
+	parent, _, ok := m.selectedTask()
+	if !ok {
+		return
+	}
+	sub, err := domain.NewTask("")
+	if err != nil {
+		return
+	}
+	parent.AddSubtask(sub)
+	m.unfoldTask(parent.ID)
+
+	m.rebuildPositions()
+	m.selectTaskByID(sub.ID)
+	m.ui.Modes.ToEdit()
+	m.ui.Edit = newEditState("", true, sub.ID, focusTask)
+	m.ensureVisible()
+}
+
+// indentTask makes the selected task a subtask of the sibling above it.
+func (m *model) indentTask() {
+	if !m.ui.Modes.CanPerformAction(modes.ActionMoveItem) {
+		return
+	}
+	_, pos, ok := m.selectedTask()
+	if !ok {
+		return
+	}
+	siblings, idx := m.project.Categories[pos.CategoryIndex].SiblingsOf(pos.TaskIndex, pos.SubtaskPath)
+	if idx <= 0 {
+		m.ui.StatusMsg = "No task above to nest under"
+		return
+	}
+	task := (*siblings)[idx]
+	*siblings = append((*siblings)[:idx], (*siblings)[idx+1:]...)
+	parent := &(*siblings)[idx-1]
+	parent.AddSubtask(task)
+	m.unfoldTask(parent.ID)
+
+	m.rebuildPositions()
+	m.selectTaskByID(task.ID)
+	m.ensureVisible()
+	m.storeTaskUpdate()
+}
+
+// outdentTask moves the selected subtask out of its parent, placing it just
+// after the parent.
+func (m *model) outdentTask() {
+	if !m.ui.Modes.CanPerformAction(modes.ActionMoveItem) {
+		return
+	}
+	_, pos, ok := m.selectedTask()
+	if !ok || len(pos.SubtaskPath) == 0 {
+		return
+	}
+	category := &m.project.Categories[pos.CategoryIndex]
+	parentPath := pos.SubtaskPath[:len(pos.SubtaskPath)-1]
+	parentSiblings, parentIdx := category.SiblingsOf(pos.TaskIndex, parentPath)
+	if parentSiblings == nil {
+		return
+	}
+	parent := &(*parentSiblings)[parentIdx]
+	subIdx := pos.SubtaskPath[len(pos.SubtaskPath)-1]
+	task := parent.Subtasks[subIdx]
+	if err := parent.RemoveSubtask(subIdx); err != nil {
+		return
+	}
+	if len(parentPath) == 0 {
+		// Keep the task in the section its parent is shown in.
+		task.Section = parent.Section
+	}
+	domain.InsertTaskAt(parentSiblings, parentIdx+1, task)
+
+	m.rebuildPositions()
+	m.selectTaskByID(task.ID)
+	m.ensureVisible()
+	m.storeTaskUpdate()
+}
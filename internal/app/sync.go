@@ -1,14 +1,74 @@
 package app
 
-import "phasionary/internal/domain"
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
 
+	"phasionary/internal/data"
+	"phasionary/internal/domain"
+)
+
+// storeTaskUpdate saves the project and records the change for undo. When
+// the save fails the change stays in memory but is not recorded, so the
+// last saved state remains the base for merging and it is saved with the
+// next change.
 func (m *model) storeTaskUpdate() {
-	if m.deps.Store == nil {
+	if err := m.writeProject(); err != nil {
+		m.ui.StatusMsg = "Save failed: " + err.Error()
 		return
 	}
-	if err := m.deps.Store.SaveProject(m.project); err != nil {
-		m.ui.StatusMsg = "Save failed: " + err.Error()
+	m.ui.History.Record(m.project)
+}
+
+// writeProject saves the project. When another process saved it since we
+// loaded it, the two versions are merged and the merge is saved instead.
+func (m *model) writeProject() error {
+	if m.deps.Store == nil {
+		return nil
+	}
+	err := m.deps.Store.SaveProject(&m.project)
+	if !errors.Is(err, data.ErrConflict) {
+		return err
+	}
+	theirs, err := m.deps.Store.LoadProject(m.project.ID)
+	if err != nil {
+		return err
+	}
+	merged := domain.MergeProjects(m.ui.History.Saved(m.project), m.project, theirs)
+	if err := m.deps.Store.SaveProject(&merged); err != nil {
+		return err
+	}
+	m.replaceProject(merged)
+	m.ui.StatusMsg = "Merged with changes saved elsewhere"
+	return nil
+}
+
+// replaceProject swaps in another version of the current project, keeping
+// the selection on the same task when it still exists.
+func (m *model) replaceProject(project domain.Project) {
+	var selectedID string
+	if task, _, ok := m.selectedTask(); ok {
+		selectedID = task.ID
+	}
+	m.project = project
+	m.rebuildPositions()
+	if selectedID != "" {
+		m.selectTaskByID(selectedID)
+	}
+	m.ensureVisible()
+}
+
+// unreadableStatus summarises project files the store had to skip.
+func unreadableStatus(files []data.UnreadableFile) string {
+	if len(files) == 1 {
+		f := files[0]
+		if f.QuarantinePath != "" {
+			return fmt.Sprintf("Skipped unreadable project file %s (moved to %s)", filepath.Base(f.Path), filepath.Base(f.QuarantinePath))
+		}
+		return fmt.Sprintf("Skipped unreadable project file %s: %v", filepath.Base(f.Path), f.Err)
 	}
+	return fmt.Sprintf("Skipped %d unreadable project files in %s", len(files), filepath.Dir(files[0].Path))
 }
 
 func rebuildPositions(categories []domain.Category, filter *FilterState, fold *FoldState) []focusPosition {
@@ -18,7 +78,8 @@ func rebuildPositions(categories []domain.Category, filter *FilterState, fold *F
 		CategoryIndex: -1,
 		TaskIndex:     -1,
 	})
-	for cIndex, category := range categories {
+	for cIndex := range categories {
+		category := &categories[cIndex]
 		positions = append(positions, focusPosition{
 			Kind:          focusCategory,
 			CategoryIndex: cIndex,
@@ -27,14 +88,12 @@ func rebuildPositions(categories []domain.Category, filter *FilterState, fold *F
 		if fold != nil && fold.IsFolded(category.ID) {
 			continue
 		}
-		for tIndex, task := range category.Tasks {
-			if filter != nil && !filter.IsStatusVisible(task.Status) {
-				continue
-			}
+		for _, visible := range visibleTasks(category, filter, fold) {
 			positions = append(positions, focusPosition{
 				Kind:          focusTask,
 				CategoryIndex: cIndex,
-				TaskIndex:     tIndex,
+				TaskIndex:     visible.taskIndex,
+				SubtaskPath:   visible.path,
 			})
 		}
 	}
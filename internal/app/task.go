@@ -1,11 +1,14 @@
 package app
 
 import (
+	"fmt"
 	"sort"
+	"time"
 
 	"phasionary/internal/app/components"
 	"phasionary/internal/app/modes"
 	"phasionary/internal/app/selection"
+	"phasionary/internal/data"
 	"phasionary/internal/domain"
 )
 
@@ -35,25 +38,34 @@ func (m *model) confirmDeleteAction() {
 }
 
 func (m *model) deleteTask(position focusPosition) {
-	catIndex := position.CategoryIndex
-	taskIndex := position.TaskIndex
-
-	task := m.project.Categories[catIndex].Tasks[taskIndex]
-	taskCopy := task
+	task := m.taskAt(position)
+	if task == nil {
+		return
+	}
+	taskCopy := task.Clone()
 	m.ui.Clipboard = ClipboardState{
 		Task:     &taskCopy,
 		IsCut:    false,
 		SourceID: "",
 	}
+	if item, ok := data.TrashTask(m.project, taskCopy.ID); ok {
+		m.moveToTrash(item)
+	}
 
-	_ = m.project.Categories[catIndex].RemoveTask(taskIndex)
+	m.project.RemoveTaskByID(taskCopy.ID)
+	m.project.PruneDependencies()
 	m.rebuildAndClamp()
 	m.storeTaskUpdate()
 }
 
 func (m *model) deleteCategory(position focusPosition) {
 	catIndex := position.CategoryIndex
+	if catIndex < 0 || catIndex >= len(m.project.Categories) {
+		return
+	}
+	m.moveToTrash(data.TrashCategory(m.project, catIndex))
 	_ = m.project.RemoveCategory(catIndex)
+	m.project.PruneDependencies()
 	m.rebuildAndClamp()
 	m.storeTaskUpdate()
 }
@@ -67,25 +79,35 @@ func (m *model) toggleSelectedTask() {
 	if !m.ui.Modes.CanPerformAction(modes.ActionToggleTask) {
 		return
 	}
-	position, ok := m.selectedPosition()
-	if !ok || position.Kind != focusTask {
+	task, _, ok := m.selectedTask()
+	if !ok {
 		return
 	}
-	task := &m.project.Categories[position.CategoryIndex].Tasks[position.TaskIndex]
-	if task.CycleStatus() {
-		m.storeTaskUpdate()
+	_, _, catIdx, _ := m.project.LocateTask(task.ID)
+	before := m.project.Categories[catIdx].CountTasks()
+	changed, next := m.project.CycleTaskStatus(task.ID)
+	if !changed {
+		return
+	}
+	// Completing a recurring task adds its next occurrence, and cycling on
+	// to cancelled takes it back.
+	if m.project.Categories[catIdx].CountTasks() != before {
+		m.rebuildPositions()
 	}
+	if next != nil {
+		m.ui.StatusMsg = "Next occurrence created"
+	}
+	m.storeTaskUpdate()
 }
 
 func (m *model) increasePriority() {
 	if !m.ui.Modes.CanPerformAction(modes.ActionChangePriority) {
 		return
 	}
-	position, ok := m.selectedPosition()
-	if !ok || position.Kind != focusTask {
+	task, _, ok := m.selectedTask()
+	if !ok {
 		return
 	}
-	task := &m.project.Categories[position.CategoryIndex].Tasks[position.TaskIndex]
 	if task.IncreasePriority() {
 		m.storeTaskUpdate()
 	}
@@ -95,11 +117,10 @@ func (m *model) decreasePriority() {
 	if !m.ui.Modes.CanPerformAction(modes.ActionChangePriority) {
 		return
 	}
-	position, ok := m.selectedPosition()
-	if !ok || position.Kind != focusTask {
+	task, _, ok := m.selectedTask()
+	if !ok {
 		return
 	}
-	task := &m.project.Categories[position.CategoryIndex].Tasks[position.TaskIndex]
 	if task.DecreasePriority() {
 		m.storeTaskUpdate()
 	}
@@ -116,7 +137,11 @@ func (m *model) openEstimatePicker() {
 
 	var currentEstimate int
 	if position.Kind == focusTask {
-		currentEstimate = m.project.Categories[position.CategoryIndex].Tasks[position.TaskIndex].EstimateMinutes
+		task := m.taskAt(position)
+		if task == nil {
+			return
+		}
+		currentEstimate = task.EstimateMinutes
 	} else {
 		currentEstimate = m.project.Categories[position.CategoryIndex].EstimateMinutes
 	}
@@ -132,7 +157,10 @@ func (m *model) selectEstimate(minutes int) {
 	}
 
 	if position.Kind == focusTask {
-		task := &m.project.Categories[position.CategoryIndex].Tasks[position.TaskIndex]
+		task := m.taskAt(position)
+		if task == nil {
+			return
+		}
 		task.SetEstimate(minutes)
 	} else {
 		category := &m.project.Categories[position.CategoryIndex]
@@ -141,23 +169,49 @@ func (m *model) selectEstimate(minutes int) {
 	m.storeTaskUpdate()
 }
 
+func (m *model) openDeadlinePicker() {
+	if !m.ui.Modes.CanPerformAction(modes.ActionChangeDeadline) {
+		return
+	}
+	task, _, ok := m.selectedTask()
+	if !ok {
+		return
+	}
+	m.ui.DeadlinePicker = components.NewDeadlinePickerState(task.Deadline, time.Now())
+	m.ui.Modes.ToDeadlinePicker()
+}
+
+func (m *model) selectDeadline(deadline string) {
+	task, _, ok := m.selectedTask()
+	if !ok {
+		return
+	}
+	if task.Deadline == deadline {
+		return
+	}
+	if err := task.SetDeadline(deadline); err != nil {
+		m.ui.StatusMsg = err.Error()
+		return
+	}
+	m.storeTaskUpdate()
+}
+
 func (m *model) moveTaskDown() {
 	if !m.ui.Modes.CanPerformAction(modes.ActionMoveItem) {
 		return
 	}
-	position, ok := m.selectedPosition()
-	if !ok || position.Kind != focusTask {
+	_, position, ok := m.selectedTask()
+	if !ok {
 		return
 	}
-	catIndex := position.CategoryIndex
-	taskIndex := position.TaskIndex
-	tasks := m.project.Categories[catIndex].Tasks
+	siblings, taskIndex := m.project.Categories[position.CategoryIndex].SiblingsOf(position.TaskIndex, position.SubtaskPath)
+	tasks := *siblings
 	if taskIndex >= len(tasks)-1 {
 		return
 	}
 	tasks[taskIndex], tasks[taskIndex+1] = tasks[taskIndex+1], tasks[taskIndex]
 	m.rebuildPositions()
-	m.ui.Selection.MoveBy(1)
+	m.selectTaskByID(tasks[taskIndex+1].ID)
 	m.ensureVisible()
 	m.storeTaskUpdate()
 }
@@ -166,19 +220,18 @@ func (m *model) moveTaskUp() {
 	if !m.ui.Modes.CanPerformAction(modes.ActionMoveItem) {
 		return
 	}
-	position, ok := m.selectedPosition()
-	if !ok || position.Kind != focusTask {
+	_, position, ok := m.selectedTask()
+	if !ok {
 		return
 	}
-	catIndex := position.CategoryIndex
-	taskIndex := position.TaskIndex
+	siblings, taskIndex := m.project.Categories[position.CategoryIndex].SiblingsOf(position.TaskIndex, position.SubtaskPath)
 	if taskIndex <= 0 {
 		return
 	}
-	tasks := m.project.Categories[catIndex].Tasks
+	tasks := *siblings
 	tasks[taskIndex], tasks[taskIndex-1] = tasks[taskIndex-1], tasks[taskIndex]
 	m.rebuildPositions()
-	m.ui.Selection.MoveBy(-1)
+	m.selectTaskByID(tasks[taskIndex-1].ID)
 	m.ensureVisible()
 	m.storeTaskUpdate()
 }
@@ -269,6 +322,13 @@ func estimateOrder(estimate int) int {
 	return 0
 }
 
+func deadlineOrder(deadline string) int {
+	if deadline == "" {
+		return 1
+	}
+	return 0
+}
+
 func sortCategoryTasks(tasks []domain.Task, ascending bool) {
 	sort.SliceStable(tasks, func(i, j int) bool {
 		orderI := statusOrder(tasks[i].Status)
@@ -287,6 +347,20 @@ func sortCategoryTasks(tasks []domain.Task, ascending bool) {
 			}
 			return prioI > prioJ
 		}
+		dlOrderI := deadlineOrder(tasks[i].Deadline)
+		dlOrderJ := deadlineOrder(tasks[j].Deadline)
+		if dlOrderI != dlOrderJ {
+			if ascending {
+				return dlOrderI < dlOrderJ
+			}
+			return dlOrderI > dlOrderJ
+		}
+		if tasks[i].Deadline != tasks[j].Deadline {
+			if ascending {
+				return tasks[i].Deadline < tasks[j].Deadline
+			}
+			return tasks[i].Deadline > tasks[j].Deadline
+		}
 		estOrderI := estimateOrder(tasks[i].EstimateMinutes)
 		estOrderJ := estimateOrder(tasks[j].EstimateMinutes)
 		if estOrderI != estOrderJ {
@@ -310,6 +384,14 @@ func sortCategoryTasks(tasks []domain.Task, ascending bool) {
 	})
 }
 
+// sortTaskTree sorts tasks and, recursively, every list of subtasks.
+func sortTaskTree(tasks []domain.Task, ascending bool) {
+	sortCategoryTasks(tasks, ascending)
+	for i := range tasks {
+		sortTaskTree(tasks[i].Subtasks, ascending)
+	}
+}
+
 func (m *model) sortTasksByStatus() {
 	ascending := true
 	m.ui.LastSortAscending = &ascending
@@ -328,25 +410,18 @@ func (m *model) sortTasksByStatusOrder(ascending bool) {
 	}
 
 	var selectedTaskID string
-	position, hasSelection := m.selectedPosition()
-	if hasSelection && position.Kind == focusTask {
-		selectedTaskID = m.project.Categories[position.CategoryIndex].Tasks[position.TaskIndex].ID
+	if task, _, ok := m.selectedTask(); ok {
+		selectedTaskID = task.ID
 	}
 
 	for i := range m.project.Categories {
-		sortCategoryTasks(m.project.Categories[i].Tasks, ascending)
+		sortTaskTree(m.project.Categories[i].Tasks, ascending)
 	}
 
 	m.rebuildPositions()
 
 	if selectedTaskID != "" {
-		m.ui.Selection.SelectByPredicate(func(p selection.Position) bool {
-			if p.Kind != selection.FocusTask {
-				return false
-			}
-			task := m.project.Categories[p.CategoryIndex].Tasks[p.TaskIndex]
-			return task.ID == selectedTaskID
-		})
+		m.selectTaskByID(selectedTaskID)
 	}
 
 	m.ensureVisible()
@@ -358,17 +433,22 @@ func (m *model) cutSelectedTask() {
 		return
 	}
 	position, ok := m.selectedPosition()
-	if !ok || position.Kind != focusTask {
-		m.ui.StatusMsg = "Can only cut tasks"
+	if ok && position.Kind == focusCategory {
+		m.cutCategory(position.CategoryIndex)
+		return
+	}
+	task, _, ok := m.selectedTask()
+	if !ok {
+		m.ui.StatusMsg = "Can only cut tasks and categories"
 		return
 	}
 
-	task := m.project.Categories[position.CategoryIndex].Tasks[position.TaskIndex]
-	taskCopy := task
+	taskCopy := task.Clone()
 	m.ui.Clipboard = ClipboardState{
-		Task:     &taskCopy,
-		IsCut:    true,
-		SourceID: task.ID,
+		Task:      &taskCopy,
+		IsCut:     true,
+		SourceID:  task.ID,
+		ProjectID: m.project.ID,
 	}
 
 	title := task.Title
@@ -378,80 +458,203 @@ func (m *model) cutSelectedTask() {
 	m.ui.StatusMsg = "Marked for cut: " + title
 }
 
+func (m *model) cutCategory(index int) {
+	cat := m.project.Categories[index]
+	m.ui.Clipboard = ClipboardState{
+		Category:  &cat,
+		IsCut:     true,
+		SourceID:  cat.ID,
+		ProjectID: m.project.ID,
+	}
+	m.ui.StatusMsg = "Marked category for cut: " + truncateText(cat.Name, 30)
+}
+
 func (m *model) pasteTask() {
-	if m.ui.Clipboard.Task == nil {
+	if m.ui.Clipboard.Task == nil && m.ui.Clipboard.Category == nil {
 		m.ui.StatusMsg = "Nothing to paste"
 		return
 	}
 
-	newID, err := domain.NewID()
-	if err != nil {
+	position, ok := m.selectedPosition()
+	if !ok || len(m.project.Categories) == 0 {
+		m.ui.StatusMsg = "No category to paste into"
+		return
+	}
+	if m.ui.Clipboard.Category != nil {
+		m.pasteCategory(position)
+		return
+	}
+	if m.ui.Clipboard.IsCut && m.ui.Clipboard.ProjectID != m.project.ID {
+		m.pasteFromProject(position)
+		return
+	}
+
+	newTask := m.ui.Clipboard.Task.Clone()
+	if err := newTask.ReassignIDs(); err != nil {
 		m.ui.StatusMsg = "Failed to create task ID"
 		return
 	}
+	newID := newTask.ID
+	newTask.UpdatedAt = domain.NowTimestamp()
+	if !m.ui.Clipboard.IsCut {
+		newTask.ClearTimeEntries()
+		domain.RetargetCopies([]domain.Task{*m.ui.Clipboard.Task}, []domain.Task{newTask})
+	}
 
-	newTask := domain.Task{
-		ID:              newID,
-		Title:           m.ui.Clipboard.Task.Title,
-		Status:          m.ui.Clipboard.Task.Status,
-		CreatedAt:       m.ui.Clipboard.Task.CreatedAt,
-		UpdatedAt:       domain.NowTimestamp(),
-		Priority:        m.ui.Clipboard.Task.Priority,
-		CompletionDate:  m.ui.Clipboard.Task.CompletionDate,
-		EstimateMinutes: m.ui.Clipboard.Task.EstimateMinutes,
+	m.insertAtSelection(&m.project, position, newTask)
+
+	if m.ui.Clipboard.IsCut {
+		m.project.RemoveTaskByID(m.ui.Clipboard.SourceID)
+		m.project.RetargetDependencies(*m.ui.Clipboard.Task, newTask)
+	} else {
+		// A copy taken from another project may still point at tasks there.
+		m.project.PruneDependencies()
 	}
 
-	position, ok := m.selectedPosition()
-	var catIndex, taskIndex int
+	statusMsg := "Pasted!"
+	if m.ui.Clipboard.IsCut {
+		statusMsg = "Moved!"
+	}
+	m.ui.Clipboard = ClipboardState{}
 
-	if !ok || len(m.project.Categories) == 0 {
-		m.ui.StatusMsg = "No category to paste into"
+	m.rebuildPositions()
+	m.selectTaskByID(newID)
+	m.ensureVisible()
+	m.storeTaskUpdate()
+	m.ui.StatusMsg = statusMsg
+}
+
+// pasteCategory moves a cut category in front of the selected one. A
+// category cut in another project is moved over with its tasks and added
+// at the end, like a task pasted from there.
+func (m *model) pasteCategory(position focusPosition) {
+	clip := m.ui.Clipboard
+	if clip.ProjectID != m.project.ID {
+		source, err := m.deps.Store.LoadProject(clip.ProjectID)
+		if err != nil {
+			m.ui.StatusMsg = fmt.Sprintf("Error loading project: %v", err)
+			return
+		}
+		moved, err := data.MoveCategory(m.deps.Store, &source, &m.project, clip.SourceID)
+		if err != nil && moved.ID == "" {
+			m.ui.StatusMsg = "Move failed: " + err.Error()
+			return
+		}
+		m.ui.Clipboard = ClipboardState{}
+		m.ui.History.Forget(source.ID)
+		m.ui.History.Forget(m.project.ID)
+		m.ui.History.Track(m.project)
+		m.refreshOtherTimer()
+		m.rebuildPositions()
+		m.selectCategoryByID(moved.ID)
+		m.ensureVisible()
+		if err != nil {
+			m.ui.StatusMsg = "Move incomplete: " + err.Error()
+			return
+		}
+		m.ui.StatusMsg = "Moved category from " + source.Name
 		return
 	}
 
+	from := -1
+	for i := range m.project.Categories {
+		if m.project.Categories[i].ID == clip.SourceID {
+			from = i
+			break
+		}
+	}
+	m.ui.Clipboard = ClipboardState{}
+	if from < 0 {
+		m.ui.StatusMsg = "Category no longer exists"
+		return
+	}
+	to := max(position.CategoryIndex, 0)
+	if to == from {
+		return
+	}
+	cat := m.project.Categories[from]
+	_ = m.project.RemoveCategory(from)
+	if to > from {
+		to--
+	}
+	m.project.InsertCategory(to, cat)
+	m.rebuildPositions()
+	m.selectCategoryByID(cat.ID)
+	m.ensureVisible()
+	m.storeTaskUpdate()
+	m.ui.StatusMsg = "Moved!"
+}
+
+// insertAtSelection puts task into project at the selected position: before
+// the selected task, or at the top of the selected category.
+func (m *model) insertAtSelection(project *domain.Project, position focusPosition, task domain.Task) {
+	var catIndex int
+	var siblings *[]domain.Task
+	taskIndex := 0
 	switch position.Kind {
 	case focusProject:
 		catIndex = 0
-		taskIndex = 0
 	case focusCategory:
 		catIndex = position.CategoryIndex
-		taskIndex = 0
 	case focusTask:
 		catIndex = position.CategoryIndex
-		taskIndex = position.TaskIndex
+		siblings, taskIndex = project.Categories[catIndex].SiblingsOf(position.TaskIndex, position.SubtaskPath)
+		anchor := project.Categories[catIndex].TaskAt(position.TaskIndex, position.SubtaskPath)
+		clip := m.ui.Clipboard
+		if clip.IsCut && clip.ProjectID == project.ID && anchor != nil &&
+			(anchor.ID == clip.SourceID || clip.Task.HasDescendant(anchor.ID)) {
+			// Pasting into the cut subtree would drop the task along with
+			// its source, so put it back where it came from instead.
+			siblings, taskIndex, catIndex, _ = project.LocateTask(clip.SourceID)
+		}
 	}
-
-	if m.ui.Clipboard.IsCut {
-		m.removeTaskByID(m.ui.Clipboard.SourceID)
+	category := &project.Categories[catIndex]
+	if siblings == nil {
+		siblings = &category.Tasks
 	}
 
-	m.project.Categories[catIndex].InsertTask(taskIndex, newTask)
+	if siblings == &category.Tasks {
+		if section := m.ui.Filter.Section(); section != "" && section != task.SectionName() {
+			if err := task.SetSection(section); err != nil {
+				task.Section = m.viewSection()
+			}
+		}
+	}
+	domain.InsertTaskAt(siblings, taskIndex, task)
+	category.UpdatedAt = domain.NowTimestamp()
+}
 
-	statusMsg := "Pasted!"
-	if m.ui.Clipboard.IsCut {
-		statusMsg = "Moved!"
+// pasteFromProject moves a task cut in another project into this one. It
+// keeps the task's IDs and saves both projects, so the undo history of
+// both is dropped: undoing either side alone would lose or duplicate the
+// task.
+func (m *model) pasteFromProject(position focusPosition) {
+	clip := m.ui.Clipboard
+	source, err := m.deps.Store.LoadProject(clip.ProjectID)
+	if err != nil {
+		m.ui.StatusMsg = fmt.Sprintf("Error loading project: %v", err)
+		return
+	}
+	moved, err := data.MoveTask(m.deps.Store, &source, &m.project, clip.SourceID, func(project *domain.Project, task domain.Task) error {
+		m.insertAtSelection(project, position, task)
+		return nil
+	})
+	if err != nil && moved.ID == "" {
+		m.ui.StatusMsg = "Move failed: " + err.Error()
+		return
 	}
 	m.ui.Clipboard = ClipboardState{}
+	m.ui.History.Forget(source.ID)
+	m.ui.History.Forget(m.project.ID)
+	m.ui.History.Track(m.project)
+	m.refreshOtherTimer()
 
 	m.rebuildPositions()
-	m.ui.Selection.SelectByPredicate(func(p selection.Position) bool {
-		if p.Kind != selection.FocusTask {
-			return false
-		}
-		return m.project.Categories[p.CategoryIndex].Tasks[p.TaskIndex].ID == newID
-	})
+	m.selectTaskByID(moved.ID)
 	m.ensureVisible()
-	m.storeTaskUpdate()
-	m.ui.StatusMsg = statusMsg
-}
-
-func (m *model) removeTaskByID(id string) {
-	for catIdx := range m.project.Categories {
-		for taskIdx, task := range m.project.Categories[catIdx].Tasks {
-			if task.ID == id {
-				_ = m.project.Categories[catIdx].RemoveTask(taskIdx)
-				return
-			}
-		}
+	if err != nil {
+		m.ui.StatusMsg = "Move incomplete: " + err.Error()
+		return
 	}
+	m.ui.StatusMsg = "Moved from " + source.Name
 }
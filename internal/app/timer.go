@@ -0,0 +1,82 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"phasionary/internal/app/modes"
+	"phasionary/internal/data"
+	"phasionary/internal/domain"
+)
+
+// timerTickMsg refreshes the elapsed time shown for a running timer.
+type timerTickMsg struct{}
+
+func timerTick() tea.Cmd {
+	return tea.Tick(time.Minute, func(time.Time) tea.Msg {
+		return timerTickMsg{}
+	})
+}
+
+func (m *model) toggleTimer() tea.Cmd {
+	if !m.ui.Modes.CanPerformAction(modes.ActionTrackTime) {
+		return nil
+	}
+	task, _, ok := m.selectedTask()
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	if task.IsTimerRunning() {
+		_ = task.StopTimer(now)
+		m.ui.StatusMsg = fmt.Sprintf("Timer stopped (%s tracked)", domain.FormatDuration(task.TrackedDuration(now)))
+		m.storeTaskUpdate()
+		return nil
+	}
+
+	if m.deps.Store != nil {
+		if _, err := data.StopTimersExcept(m.deps.Store, m.project.ID, now); err != nil {
+			m.ui.StatusMsg = "Failed to stop other timers: " + err.Error()
+			return nil
+		}
+	}
+	m.project.StopTimers(now)
+	m.ui.OtherTimer = nil
+	_ = task.StartTimer(now)
+	if task.Status == domain.StatusTodo {
+		_ = task.SetStatus(domain.StatusInProgress)
+	}
+	m.storeTaskUpdate()
+	return timerTick()
+}
+
+// refreshOtherTimer looks up a timer running in another project, which the
+// status line shows while none runs in the open one.
+func (m *model) refreshOtherTimer() {
+	m.ui.OtherTimer = nil
+	if m.deps.Store == nil {
+		return
+	}
+	if running, ok, err := data.FindRunningTimer(m.deps.Store, m.project.ID); err == nil && ok {
+		m.ui.OtherTimer = &running
+	}
+}
+
+// timerRunning reports whether a timer runs in any project.
+func (m model) timerRunning() bool {
+	return m.project.RunningTimer() != nil || m.ui.OtherTimer != nil
+}
+
+func (m model) timerIndicator() string {
+	if task := m.project.RunningTimer(); task != nil {
+		since, _ := task.RunningSince()
+		return fmt.Sprintf(" [⏱ %s %s]", truncateText(task.Title, 30), domain.FormatDuration(time.Since(since)))
+	}
+	if other := m.ui.OtherTimer; other != nil {
+		since, _ := other.Task.RunningSince()
+		return fmt.Sprintf(" [⏱ %s: %s %s]", truncateText(other.ProjectName, 20), truncateText(other.Task.Title, 30), domain.FormatDuration(time.Since(since)))
+	}
+	return ""
+}
@@ -0,0 +1,148 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"phasionary/internal/data"
+	"phasionary/internal/ui"
+)
+
+// moveToTrash keeps a copy of an item about to be deleted. Failing to write
+// it is reported but does not block the deletion.
+func (m *model) moveToTrash(item data.TrashItem) {
+	if m.deps.Trash == nil {
+		return
+	}
+	if _, err := m.deps.Trash.Add(item); err != nil {
+		m.ui.StatusMsg = "Could not move to trash: " + err.Error()
+	}
+}
+
+func (m *model) openTrash() {
+	if m.deps.Trash == nil {
+		return
+	}
+	items, err := m.deps.Trash.List()
+	if err != nil {
+		m.ui.StatusMsg = fmt.Sprintf("Error loading trash: %v", err)
+		return
+	}
+	m.ui.TrashView = TrashViewState{items: items}
+	m.ui.Modes.ToTrash()
+}
+
+func (m model) handleTrashKey(msg tea.KeyMsg) model {
+	switch msg.String() {
+	case "j", "down":
+		m.ui.TrashView.moveSelection(1)
+	case "k", "up":
+		m.ui.TrashView.moveSelection(-1)
+	case "enter", "r":
+		m.restoreTrashItem()
+	case "d":
+		m.purgeTrashItem()
+	case "q", "esc", "X":
+		m.ui.TrashView = TrashViewState{}
+		m.ui.Modes.ToNormal()
+	}
+	return m
+}
+
+// restoreTrashItem puts the selected item back. Items of the open project
+// are restored in memory so the change can be undone like any other edit.
+func (m *model) restoreTrashItem() {
+	item, ok := m.ui.TrashView.selectedItem()
+	if !ok {
+		return
+	}
+	var err error
+	if item.ProjectID == m.project.ID && item.Kind != data.TrashKindProject {
+		err = data.RestoreInto(&m.project, item)
+		if err == nil {
+			m.rebuildPositions()
+			m.storeTaskUpdate()
+		}
+		if err == nil || errors.Is(err, data.ErrAlreadyRestored) {
+			if removeErr := m.deps.Trash.Remove(item.ID); removeErr != nil {
+				err = removeErr
+			}
+		}
+	} else {
+		err = m.deps.Trash.Restore(m.deps.Store, item)
+	}
+	switch {
+	case errors.Is(err, data.ErrAlreadyRestored):
+		m.ui.StatusMsg = fmt.Sprintf("%q is already back", item.Title())
+	case err != nil:
+		m.ui.StatusMsg = fmt.Sprintf("Restore failed: %v", err)
+		return
+	default:
+		m.ui.StatusMsg = fmt.Sprintf("Restored %s %q", item.Kind, item.Title())
+	}
+	m.removeTrashViewItem(item.ID)
+}
+
+func (m *model) purgeTrashItem() {
+	item, ok := m.ui.TrashView.selectedItem()
+	if !ok {
+		return
+	}
+	if err := m.deps.Trash.Remove(item.ID); err != nil && !errors.Is(err, data.ErrTrashItemNotFound) {
+		m.ui.StatusMsg = fmt.Sprintf("Delete failed: %v", err)
+		return
+	}
+	m.ui.StatusMsg = fmt.Sprintf("Deleted %q for good", item.Title())
+	m.removeTrashViewItem(item.ID)
+}
+
+func (m *model) removeTrashViewItem(id string) {
+	view := &m.ui.TrashView
+	for i := range view.items {
+		if view.items[i].ID == id {
+			view.items = append(view.items[:i], view.items[i+1:]...)
+			break
+		}
+	}
+	view.moveSelection(0)
+}
+
+func (m model) trashView() string {
+	view := m.ui.TrashView
+	lines := []string{ui.DialogTitleStyle.Render("Trash:"), ""}
+	if len(view.items) == 0 {
+		lines = append(lines, ui.MutedStyle.Render("  (empty)"))
+	}
+
+	visibleEnd := view.scrollOffset + pickerVisibleItems
+	if visibleEnd > len(view.items) {
+		visibleEnd = len(view.items)
+	}
+	if view.scrollOffset > 0 {
+		lines = append(lines, ui.DialogHintStyle.Render("  ↑ more above"))
+	}
+	for i := view.scrollOffset; i < visibleEnd; i++ {
+		item := view.items[i]
+		prefix := "  "
+		if i == view.selected {
+			prefix = "> "
+		}
+		line := fmt.Sprintf("%s%-8s %s", prefix, item.Kind, truncateText(item.Title(), 30))
+		detail := strings.TrimSpace(item.Location() + "  " + FormatRelativeTime(item.DeletedAt))
+		if i == view.selected {
+			line = ui.SelectedStyle.Render(line) + "  " + ui.DialogHintStyle.Render(detail)
+		} else {
+			line += "  " + ui.DialogHintStyle.Render(detail)
+		}
+		lines = append(lines, line)
+	}
+	if visibleEnd < len(view.items) {
+		lines = append(lines, ui.DialogHintStyle.Render("  ↓ more below"))
+	}
+
+	lines = append(lines, "", ui.DialogHintStyle.Render("j/k navigate | enter/r restore | d delete for good | esc close"))
+	return ui.HelpDialogStyle.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce groups the burst of events a single save produces.
+const watchDebounce = 100 * time.Millisecond
+
+// projectFileChangedMsg reports the projects whose JSON files were written
+// during one debounce window.
+type projectFileChangedMsg struct {
+	projectIDs []string
+}
+
+func (msg projectFileChangedMsg) changed(projectID string) bool {
+	return slices.Contains(msg.projectIDs, projectID)
+}
+
+func (msg projectFileChangedMsg) changedOtherThan(projectID string) bool {
+	return slices.ContainsFunc(msg.projectIDs, func(id string) bool { return id != projectID })
+}
+
+// ProjectWatcher reports changes to project files in the data directory.
+type ProjectWatcher struct {
+	watcher *fsnotify.Watcher
+}
+
+func NewProjectWatcher(dir string) (*ProjectWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(dir); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+	return &ProjectWatcher{watcher: watcher}, nil
+}
+
+func (w *ProjectWatcher) Close() error {
+	return w.watcher.Close()
+}
+
+// Wait returns a command that blocks until a project file changes. It must
+// be issued again after each message to keep watching.
+func (w *ProjectWatcher) Wait() tea.Cmd {
+	if w == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		for {
+			select {
+			case event, ok := <-w.watcher.Events:
+				if !ok {
+					return nil
+				}
+				id, relevant := projectIDFromEvent(event)
+				if !relevant {
+					continue
+				}
+				return projectFileChangedMsg{projectIDs: w.collect(id, watchDebounce)}
+			case _, ok := <-w.watcher.Errors:
+				if !ok {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// collect gathers the projects changed during the window that starts with
+// the change to first, so a burst touching several files reports them all.
+func (w *ProjectWatcher) collect(first string, window time.Duration) []string {
+	ids := []string{first}
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return ids
+			}
+			if id, relevant := projectIDFromEvent(event); relevant && !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		case <-timer.C:
+			return ids
+		}
+	}
+}
+
+func projectIDFromEvent(event fsnotify.Event) (string, bool) {
+	if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+		return "", false
+	}
+	name := filepath.Base(event.Name)
+	if filepath.Ext(name) != ".json" || strings.HasPrefix(name, ".") {
+		return "", false
+	}
+	return strings.TrimSuffix(name, ".json"), true
+}
+
+// reloadFromDisk picks up a change another process made to the current
+// project. Our own saves also trigger the watcher; those are recognised by
+// the unchanged revision and ignored.
+func (m *model) reloadFromDisk() {
+	if m.deps.Store == nil || m.project.ID == "" {
+		return
+	}
+	if !m.ui.Modes.IsNormal() {
+		m.ui.ReloadPending = true
+		return
+	}
+	m.ui.ReloadPending = false
+	project, err := m.deps.Store.LoadProject(m.project.ID)
+	if err != nil || project.ID != m.project.ID || project.Revision == m.project.Revision {
+		return
+	}
+	m.replaceProject(project)
+	m.ui.History.Track(project)
+	m.ui.StatusMsg = "Reloaded from disk"
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"phasionary/internal/fsutil"
+)
+
+func TestProjectIDFromEvent(t *testing.T) {
+	id, ok := projectIDFromEvent(fsnotify.Event{Name: "/data/abc.json", Op: fsnotify.Create})
+	assert.True(t, ok)
+	assert.Equal(t, "abc", id)
+
+	_, ok = projectIDFromEvent(fsnotify.Event{Name: "/data/.abc.json.123.tmp", Op: fsnotify.Write})
+	assert.False(t, ok)
+	_, ok = projectIDFromEvent(fsnotify.Event{Name: "/data/abc.lock", Op: fsnotify.Write})
+	assert.False(t, ok)
+	_, ok = projectIDFromEvent(fsnotify.Event{Name: "/data/abc.json", Op: fsnotify.Chmod})
+	assert.False(t, ok)
+}
+
+func TestProjectWatcher_ReportsAtomicSaves(t *testing.T) {
+	dir := t.TempDir()
+	watcher, err := NewProjectWatcher(dir)
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	msgs := make(chan any, 1)
+	go func() { msgs <- watcher.Wait()() }()
+
+	require.NoError(t, fsutil.WriteFileAtomic(filepath.Join(dir, "p1.json"), []byte("{}"), 0o644))
+	select {
+	case msg := <-msgs:
+		assert.Equal(t, projectFileChangedMsg{projectIDs: []string{"p1"}}, msg)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change reported")
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestProjectWatcher_ReportsEveryFileInABurst(t *testing.T) {
+	dir := t.TempDir()
+	watcher, err := NewProjectWatcher(dir)
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	msgs := make(chan any, 1)
+	go func() { msgs <- watcher.Wait()() }()
+
+	require.NoError(t, fsutil.WriteFileAtomic(filepath.Join(dir, "other.json"), []byte("{}"), 0o644))
+	require.NoError(t, fsutil.WriteFileAtomic(filepath.Join(dir, "open.json"), []byte("{}"), 0o644))
+	select {
+	case msg := <-msgs:
+		changed := msg.(projectFileChangedMsg)
+		assert.True(t, changed.changed("other"))
+		assert.True(t, changed.changed("open"))
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change reported")
+	}
+}
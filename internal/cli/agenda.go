@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"phasionary/internal/data"
+	"phasionary/internal/domain"
+)
+
+func newAgendaCmd() *cobra.Command {
+	var days int
+
+	cmd := &cobra.Command{
+		Use:   "agenda",
+		Short: "List overdue and upcoming tasks across all projects",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if days < 0 {
+				return errors.New("--days must not be negative")
+			}
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			projects, err := data.LoadProjects(store)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			var items []AgendaItem
+			for _, project := range projects {
+				for _, cat := range project.Categories {
+					domain.WalkTasks(cat.Tasks, func(task *domain.Task, _ int) {
+						if task.IsDone() {
+							return
+						}
+						remaining, ok := task.DaysUntilDeadline(now)
+						if !ok || remaining > days {
+							return
+						}
+						items = append(items, AgendaItem{
+							ID:        task.ID,
+							Title:     task.Title,
+							Status:    task.Status,
+							Priority:  task.Priority,
+							Project:   project.Name,
+							Category:  cat.Name,
+							Deadline:  task.Deadline,
+							DaysLeft:  remaining,
+							IsOverdue: remaining < 0,
+						})
+					})
+				}
+			}
+
+			sort.SliceStable(items, func(i, j int) bool {
+				return items[i].Deadline < items[j].Deadline
+			})
+
+			return writeAgenda(cmd.OutOrStdout(), items)
+		},
+	}
+
+	cmd.Flags().IntVar(&days, "days", 7, "include tasks due within this many days")
+
+	return cmd
+}
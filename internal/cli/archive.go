@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"phasionary/internal/domain"
+)
+
+func newArchiveCmd() *cobra.Command {
+	var olderThan string
+
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Move finished tasks out of the project into its archive",
+		Long:  "Move completed and cancelled top-level tasks, with their subtasks, into the project's archive. Archived tasks are left out of task lists and the TUI board but can be listed and restored.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cutoff time.Time
+			if olderThan != "" {
+				var err error
+				if cutoff, err = domain.ParseSince(olderThan, time.Now()); err != nil {
+					return err
+				}
+			}
+
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			project, err := store.LoadProject(viper.GetString("project"))
+			if err != nil {
+				return err
+			}
+
+			archived := project.ArchiveFinished(cutoff)
+			if len(archived) == 0 {
+				writeSuccess(cmd.OutOrStdout(), "No finished tasks to archive")
+				return nil
+			}
+			if err := store.SaveProject(&project); err != nil {
+				return err
+			}
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Archived %d tasks from %s", len(archived), project.Name))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "only tasks finished before this (YYYY-MM-DD, today, yesterday, 2h, 7d, 2w, 1m)")
+
+	cmd.AddCommand(newArchiveListCmd())
+	cmd.AddCommand(newArchiveRestoreCmd())
+
+	return cmd
+}
+
+func newArchiveListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List archived tasks",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			project, err := store.LoadProject(viper.GetString("project"))
+			if err != nil {
+				return err
+			}
+			return writeArchive(cmd.OutOrStdout(), project.Archive)
+		},
+	}
+}
+
+func newArchiveRestoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <id-or-title>",
+		Short: "Put an archived task back in its category",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			project, err := store.LoadProject(viper.GetString("project"))
+			if err != nil {
+				return err
+			}
+
+			entry, err := resolveArchived(project, args[0])
+			if err != nil {
+				return fmt.Errorf("archived task %q not found", args[0])
+			}
+			task, err := project.RestoreArchived(entry.Task.ID)
+			if err != nil {
+				return err
+			}
+			if err := store.SaveProject(&project); err != nil {
+				return err
+			}
+
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Restored task: %s", task.Title))
+			return nil
+		},
+	}
+}
+
+// resolveArchived finds an archived task by ID, ID prefix or title.
+func resolveArchived(project domain.Project, selector string) (domain.ArchivedTask, error) {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return domain.ArchivedTask{}, ErrNotFound
+	}
+	needle := domain.NormalizeName(selector)
+	for _, entry := range project.Archive {
+		if entry.Task.ID == selector ||
+			(len(selector) >= 4 && strings.HasPrefix(strings.ToLower(entry.Task.ID), strings.ToLower(selector))) ||
+			domain.NormalizeName(entry.Task.Title) == needle {
+			return entry, nil
+		}
+	}
+	return domain.ArchivedTask{}, ErrNotFound
+}
@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"phasionary/internal/data"
+	"phasionary/internal/domain"
+)
+
+func newBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Browse and restore project snapshots",
+		Long:  "Projects are snapshotted once a day and every backup_every_saves saves. Snapshots older than backup_retention_days are pruned, keeping the newest one.",
+	}
+
+	cmd.AddCommand(newBackupListCmd())
+	cmd.AddCommand(newBackupRestoreCmd())
+	cmd.AddCommand(newBackupDiffCmd())
+
+	return cmd
+}
+
+func newBackupListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "list [project]",
+		Aliases:           []string{"ls"},
+		Short:             "List the snapshots of a project",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeProjects,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			projectID, err := resolveBackupProject(store, args)
+			if err != nil {
+				return err
+			}
+			snapshots, err := store.Snapshots(projectID)
+			if err != nil {
+				return err
+			}
+			return writeBackups(cmd.OutOrStdout(), store, snapshots)
+		},
+	}
+}
+
+func newBackupRestoreCmd() *cobra.Command {
+	var at string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:               "restore <project> --at <time>",
+		Short:             "Restore a project as it was at a point in time",
+		Long:              "Restore the newest snapshot taken at or before --at. The current version is snapshotted first, so a restore can itself be undone.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeProjects,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if at == "" {
+				return errors.New("--at is required")
+			}
+			when, err := parseBackupTime(at, time.Now())
+			if err != nil {
+				return err
+			}
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			projectID, err := resolveBackupProject(store, args)
+			if err != nil {
+				return err
+			}
+			snapshot, err := store.SnapshotAt(projectID, when)
+			if err != nil {
+				if errors.Is(err, data.ErrSnapshotNotFound) {
+					return fmt.Errorf("no snapshot taken at or before %s", when.Local().Format("2006-01-02 15:04"))
+				}
+				return err
+			}
+
+			if !force {
+				fmt.Fprintf(cmd.OutOrStdout(), "Restore snapshot from %s? [y/N]: ", snapshot.TakenAt.Local().Format("2006-01-02 15:04"))
+				var response string
+				if _, err := fmt.Fscanln(cmd.InOrStdin(), &response); err != nil {
+					return nil
+				}
+				if response != "y" && response != "Y" {
+					fmt.Fprintln(cmd.OutOrStdout(), "Cancelled.")
+					return nil
+				}
+			}
+
+			project, err := store.RestoreSnapshot(snapshot)
+			if err != nil {
+				return err
+			}
+
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Restored %s to %s", project.Name, snapshot.TakenAt.Local().Format("2006-01-02 15:04")))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&at, "at", "", "point in time (2026-05-01, \"2026-05-01 14:30\", RFC 3339, or 3h, 2d, 1w ago)")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "skip confirmation prompt")
+
+	return cmd
+}
+
+func newBackupDiffCmd() *cobra.Command {
+	var at string
+
+	cmd := &cobra.Command{
+		Use:               "diff [project]",
+		Short:             "Show task changes since a snapshot",
+		Long:              "Compare a snapshot with the current project task by task. Without --at, the newest snapshot is used.",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeProjects,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			projectID, err := resolveBackupProject(store, args)
+			if err != nil {
+				return err
+			}
+			when := time.Now()
+			if at != "" {
+				if when, err = parseBackupTime(at, when); err != nil {
+					return err
+				}
+			}
+			snapshot, err := store.SnapshotAt(projectID, when)
+			if err != nil {
+				if errors.Is(err, data.ErrSnapshotNotFound) {
+					return errors.New("no snapshot to compare with")
+				}
+				return err
+			}
+			before, err := store.LoadSnapshot(snapshot)
+			if err != nil {
+				return err
+			}
+			current, err := store.LoadProject(projectID)
+			if err != nil && !errors.Is(err, data.ErrProjectNotFound) {
+				return err
+			}
+
+			return writeBackupDiff(cmd.OutOrStdout(), snapshot, domain.DiffProjects(before, current))
+		},
+	}
+
+	cmd.Flags().StringVar(&at, "at", "", "compare with the snapshot at this time instead of the newest")
+
+	return cmd
+}
+
+// resolveBackupProject finds the project whose snapshots a command works
+// on. A project that no longer exists can still be named by its ID.
+func resolveBackupProject(store *data.Store, args []string) (string, error) {
+	selector := viper.GetString("project")
+	if len(args) > 0 {
+		selector = args[0]
+	}
+	project, err := store.LoadProject(selector)
+	if err == nil {
+		return project.ID, nil
+	}
+	if !errors.Is(err, data.ErrProjectNotFound) || selector == "" {
+		return "", err
+	}
+	if info, statErr := os.Stat(filepath.Join(store.BackupDir(), selector)); statErr == nil && info.IsDir() {
+		return selector, nil
+	}
+	return "", fmt.Errorf("project %q not found", selector)
+}
+
+// parseBackupTime reads a point in time: a date (meaning the end of that
+// day), a date and time, an RFC 3339 timestamp, or an age such as "3h",
+// "2d" or "1w".
+func parseBackupTime(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if t, err := time.Parse(time.RFC3339, input); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, input, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation(domain.DateLayout, input, now.Location()); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	if len(input) > 1 {
+		n, err := strconv.Atoi(input[:len(input)-1])
+		if err == nil && n >= 0 {
+			switch input[len(input)-1] {
+			case 'm':
+				return now.Add(-time.Duration(n) * time.Minute), nil
+			case 'h':
+				return now.Add(-time.Duration(n) * time.Hour), nil
+			case 'd':
+				return now.AddDate(0, 0, -n), nil
+			case 'w':
+				return now.AddDate(0, 0, -7*n), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s (use YYYY-MM-DD, \"YYYY-MM-DD HH:MM\", or an age like 3h, 2d, 1w)", input)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"phasionary/internal/data"
 	"phasionary/internal/domain"
 )
 
@@ -39,6 +40,7 @@ func newCategoryCmd() *cobra.Command {
 	cmd.AddCommand(newCategoryAddCmd())
 	cmd.AddCommand(newCategoryEditCmd())
 	cmd.AddCommand(newCategoryDeleteCmd())
+	cmd.AddCommand(newCategoryMoveCmd())
 
 	return cmd
 }
@@ -100,7 +102,7 @@ func newCategoryAddCmd() *cobra.Command {
 			}
 
 			project.AddCategory(cat)
-			if err := store.SaveProject(project); err != nil {
+			if err := store.SaveProject(&project); err != nil {
 				return err
 			}
 
@@ -149,7 +151,7 @@ func newCategoryEditCmd() *cobra.Command {
 			cat.UpdatedAt = domain.NowTimestamp()
 			project.Categories[catIdx] = *cat
 
-			if err := store.SaveProject(project); err != nil {
+			if err := store.SaveProject(&project); err != nil {
 				return err
 			}
 
@@ -188,7 +190,7 @@ func newCategoryDeleteCmd() *cobra.Command {
 			}
 
 			if len(cat.Tasks) > 0 && !force {
-				fmt.Fprintf(cmd.OutOrStdout(), "Category %q has %d tasks. Delete anyway? [y/N]: ", cat.Name, len(cat.Tasks))
+				fmt.Fprintf(cmd.OutOrStdout(), "Category %q has %d tasks. Delete anyway? [y/N]: ", cat.Name, cat.CountTasks())
 				var response string
 				if _, err := fmt.Fscanln(cmd.InOrStdin(), &response); err != nil {
 					return nil
@@ -199,14 +201,19 @@ func newCategoryDeleteCmd() *cobra.Command {
 				}
 			}
 
+			item := data.TrashCategory(project, catIdx)
 			if err := project.RemoveCategory(catIdx); err != nil {
 				return err
 			}
-			if err := store.SaveProject(project); err != nil {
+			project.PruneDependencies()
+			if err := store.SaveProject(&project); err != nil {
+				return err
+			}
+			if _, err := store.Trash().Add(item); err != nil {
 				return err
 			}
 
-			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Deleted category: %s", cat.Name))
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Moved category to trash: %s", item.CategoryName))
 			return nil
 		},
 	}
@@ -215,3 +222,64 @@ func newCategoryDeleteCmd() *cobra.Command {
 
 	return cmd
 }
+
+func newCategoryMoveCmd() *cobra.Command {
+	var (
+		toProject string
+		copyCat   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:               "move <name-or-id> --to-project <project>",
+		Aliases:           []string{"cm"},
+		Short:             "Move or copy a category to another project",
+		Long:              "Move a category and its tasks to the end of another project, keeping their IDs and history. With --copy the original stays and the copy gets new IDs.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCategories,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if toProject == "" {
+				return fmt.Errorf("--to-project is required")
+			}
+
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			project, err := store.LoadProject(viper.GetString("project"))
+			if err != nil {
+				return err
+			}
+
+			cat, _, err := resolveCategory(project, args[0])
+			if err != nil {
+				return fmt.Errorf("category %q not found", args[0])
+			}
+
+			target, err := store.LoadProject(toProject)
+			if err != nil {
+				return err
+			}
+
+			if copyCat {
+				copied, err := data.CopyCategory(store, &target, *cat)
+				if err != nil {
+					return err
+				}
+				writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Copied category %s to %s (%s)", copied.Name, target.Name, copied.ID))
+				return nil
+			}
+			moved, err := data.MoveCategory(store, &project, &target, cat.ID)
+			if err != nil {
+				return err
+			}
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Moved category %s to %s", moved.Name, target.Name))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&toProject, "to-project", "", "target project (name or id)")
+	cmd.Flags().BoolVar(&copyCat, "copy", false, "copy the category instead of moving it")
+	_ = cmd.RegisterFlagCompletionFunc("to-project", completeProjects)
+
+	return cmd
+}
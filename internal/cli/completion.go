@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"phasionary/internal/data"
 	"phasionary/internal/domain"
 )
 
@@ -48,12 +49,15 @@ func completeProjects(cmd *cobra.Command, args []string, toComplete string) ([]s
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveError
 	}
-	projects, err := store.ListProjects()
+	projects, err := store.ListSummaries()
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveError
 	}
 	var completions []string
 	for _, p := range projects {
+		if p.Encrypted && p.Name == data.LockedName {
+			continue
+		}
 		completions = append(completions, p.Name)
 	}
 	return completions, cobra.ShellCompDirectiveNoFileComp
@@ -70,9 +74,9 @@ func completeTasks(cmd *cobra.Command, args []string, toComplete string) ([]stri
 	}
 	var completions []string
 	for _, cat := range project.Categories {
-		for _, task := range cat.Tasks {
+		domain.WalkTasks(cat.Tasks, func(task *domain.Task, _ int) {
 			completions = append(completions, task.ID)
-		}
+		})
 	}
 	return completions, cobra.ShellCompDirectiveNoFileComp
 }
@@ -110,6 +114,26 @@ func completePriorities(cmd *cobra.Command, args []string, toComplete string) ([
 	}, cobra.ShellCompDirectiveNoFileComp
 }
 
+func completeSections(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{
+		domain.SectionCurrent,
+		domain.SectionFuture,
+		domain.SectionPast,
+	}, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := storeFromViper()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	project, err := store.LoadProject(viper.GetString("project"))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return project.Tags(), cobra.ShellCompDirectiveNoFileComp
+}
+
 func completeExportFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	return []string{"json", "markdown"}, cobra.ShellCompDirectiveNoFileComp
 }
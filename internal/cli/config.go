@@ -3,6 +3,7 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -88,10 +89,44 @@ func newConfigSetCmd() *cobra.Command {
 				err = cfgManager.Update(func(c *config.Config) {
 					c.StatusDisplay = value
 				})
+			case "storage":
+				if value != config.StorageJSON && value != config.StorageSQLite {
+					return fmt.Errorf("invalid value for storage: %s (use json or sqlite)", value)
+				}
+				err = cfgManager.Update(func(c *config.Config) {
+					c.Storage = value
+				})
 			case "default_project":
 				err = cfgManager.Update(func(c *config.Config) {
 					c.DefaultProject = value
 				})
+			case "trash_retention_days", "backup_every_saves", "backup_retention_days":
+				n, convErr := strconv.Atoi(value)
+				if convErr != nil || n < 0 {
+					return fmt.Errorf("invalid value for %s: %s (use a whole number, 0 to disable)", key, value)
+				}
+				err = cfgManager.Update(func(c *config.Config) {
+					switch key {
+					case "trash_retention_days":
+						c.TrashRetentionDays = n
+					case "backup_every_saves":
+						c.BackupEverySaves = n
+					case "backup_retention_days":
+						c.BackupRetentionDays = n
+					}
+				})
+			case "key_file":
+				err = cfgManager.Update(func(c *config.Config) {
+					c.KeyFile = value
+				})
+			case "git_history":
+				enabled, convErr := strconv.ParseBool(value)
+				if convErr != nil {
+					return fmt.Errorf("invalid value for git_history: %s (use true or false)", value)
+				}
+				err = cfgManager.Update(func(c *config.Config) {
+					c.GitHistory = enabled
+				})
 			default:
 				return fmt.Errorf("unknown config key: %s", key)
 			}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newTaskBlockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "block <id-or-title> <blocker-id-or-title>",
+		Aliases:           []string{"tb"},
+		Short:             "Mark a task as blocked by another task",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeTasks,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			project, err := store.LoadProject(viper.GetString("project"))
+			if err != nil {
+				return err
+			}
+
+			task, _, _, err := resolveTask(project, args[0])
+			if err != nil {
+				return fmt.Errorf("task %q not found", args[0])
+			}
+			blocker, _, _, err := resolveTask(project, args[1])
+			if err != nil {
+				return fmt.Errorf("task %q not found", args[1])
+			}
+
+			if err := project.AddDependency(task.ID, blocker.ID); err != nil {
+				return err
+			}
+			if err := store.SaveProject(&project); err != nil {
+				return err
+			}
+
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Task %s is now blocked by %s", task.Title, blocker.Title))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newTaskUnblockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "unblock <id-or-title> <blocker-id-or-title>",
+		Aliases:           []string{"tub"},
+		Short:             "Remove a blocking link between two tasks",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeTasks,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			project, err := store.LoadProject(viper.GetString("project"))
+			if err != nil {
+				return err
+			}
+
+			task, _, _, err := resolveTask(project, args[0])
+			if err != nil {
+				return fmt.Errorf("task %q not found", args[0])
+			}
+			blocker, _, _, err := resolveTask(project, args[1])
+			if err != nil {
+				return fmt.Errorf("task %q not found", args[1])
+			}
+
+			if !project.RemoveDependency(task.ID, blocker.ID) {
+				return fmt.Errorf("task %q is not blocked by %q", task.Title, blocker.Title)
+			}
+			if err := store.SaveProject(&project); err != nil {
+				return err
+			}
+
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Task %s is no longer blocked by %s", task.Title, blocker.Title))
+			return nil
+		},
+	}
+	return cmd
+}
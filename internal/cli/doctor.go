@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"phasionary/internal/data"
+)
+
+func newDoctorCmd() *cobra.Command {
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check project files and UI state for problems",
+		Long:  "Check every project file against the rules phasionary relies on: known statuses, priorities and sections, unique IDs and names, well-formed timestamps and dependencies. With --fix, repair what can be repaired without guessing and drop state entries for projects and categories that no longer exist.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			state := data.NewStateManager(store.Dir, "")
+			if err := state.Load(); err != nil {
+				return fmt.Errorf("load state: %w", err)
+			}
+			issues, err := store.Doctor(state, fix)
+			if err != nil {
+				return err
+			}
+			if err := writeDoctor(cmd.OutOrStdout(), issues); err != nil {
+				return err
+			}
+			remaining := 0
+			for _, issue := range issues {
+				if !issue.Fixed {
+					remaining++
+				}
+			}
+			if remaining > 0 {
+				return fmt.Errorf("%d problem(s) left", remaining)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "repair the problems that can be fixed safely")
+
+	return cmd
+}
@@ -120,7 +120,13 @@ func newImportCmd() *cobra.Command {
 				if err != nil {
 					return err
 				}
-				if err := store.SaveProject(project); err != nil {
+				// Importing over an existing project replaces it on purpose.
+				if existing, err := store.LoadProject(project.ID); project.ID != "" && err == nil && existing.ID == project.ID {
+					project.Revision = existing.Revision
+				} else {
+					project.Revision = 0
+				}
+				if err := store.SaveProject(&project); err != nil {
 					return err
 				}
 				writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Imported project: %s (%s)", project.Name, project.ID))
@@ -129,7 +135,7 @@ func newImportCmd() *cobra.Command {
 				if err != nil {
 					return err
 				}
-				if err := store.SaveProject(project); err != nil {
+				if err := store.SaveProject(&project); err != nil {
 					return err
 				}
 				writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Imported project: %s (%s)", project.Name, project.ID))
@@ -48,34 +48,35 @@ func parseTimeEstimate(input string) (int, error) {
 	return int(total), nil
 }
 
-func resolveTask(project domain.Project, selector string) (*domain.Task, string, int, int, error) {
+// resolveTask finds a task or subtask by ID, ID prefix or title. The returned
+// pointer refers into project, so changes to it are saved with the project.
+func resolveTask(project domain.Project, selector string) (*domain.Task, string, int, error) {
 	selector = strings.TrimSpace(selector)
 	if selector == "" {
-		return nil, "", -1, -1, ErrNotFound
+		return nil, "", -1, ErrNotFound
 	}
 
 	needle := domain.NormalizeName(selector)
 	minPrefixLen := 4
 
 	for cIdx := range project.Categories {
-		for tIdx := range project.Categories[cIdx].Tasks {
-			task := &project.Categories[cIdx].Tasks[tIdx]
-
-			if task.ID == selector {
-				return task, project.Categories[cIdx].Name, cIdx, tIdx, nil
-			}
-
-			if len(selector) >= minPrefixLen && strings.HasPrefix(strings.ToLower(task.ID), strings.ToLower(selector)) {
-				return task, project.Categories[cIdx].Name, cIdx, tIdx, nil
+		var found *domain.Task
+		domain.WalkTasks(project.Categories[cIdx].Tasks, func(task *domain.Task, _ int) {
+			if found != nil {
+				return
 			}
-
-			if domain.NormalizeName(task.Title) == needle {
-				return task, project.Categories[cIdx].Name, cIdx, tIdx, nil
+			if task.ID == selector ||
+				(len(selector) >= minPrefixLen && strings.HasPrefix(strings.ToLower(task.ID), strings.ToLower(selector))) ||
+				domain.NormalizeName(task.Title) == needle {
+				found = task
 			}
+		})
+		if found != nil {
+			return found, project.Categories[cIdx].Name, cIdx, nil
 		}
 	}
 
-	return nil, "", -1, -1, ErrNotFound
+	return nil, "", -1, ErrNotFound
 }
 
 func resolveCategory(project domain.Project, selector string) (*domain.Category, int, error) {
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"phasionary/internal/data"
+)
+
+const historyHint = "git history is off; enable it with `phasionary config set git_history true`"
+
+func newHistoryCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the git history of the projects directory",
+		Long:  "List the commits made after saves when git_history is enabled. Covers all projects unless one is selected with --project.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			if store.History == nil {
+				return errors.New(historyHint)
+			}
+			file := ""
+			if selector := viper.GetString("project"); selector != "" {
+				project, err := store.LoadProject(selector)
+				if err != nil {
+					return err
+				}
+				file = project.ID + ".json"
+			}
+			commits, err := store.History.Log(file, limit)
+			if err != nil {
+				return err
+			}
+			return writeHistory(cmd.OutOrStdout(), commits)
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "number of commits to show (0 for all)")
+
+	return cmd
+}
+
+func newRevertCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revert <rev>",
+		Short: "Undo the changes of one history commit",
+		Long:  "Undo the changes a commit from `phasionary history` made, keeping edits made since. The revert is committed too, so it can be reverted in turn.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			names, err := store.RevertCommit(args[0])
+			if err != nil {
+				if errors.Is(err, data.ErrHistoryDisabled) {
+					return errors.New(historyHint)
+				}
+				return err
+			}
+			if len(names) == 0 {
+				writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Nothing to revert in %s", args[0]))
+				return nil
+			}
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Reverted %s in %s", args[0], strings.Join(names, ", ")))
+			return nil
+		},
+	}
+}
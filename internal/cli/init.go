@@ -7,19 +7,30 @@ import (
 	"github.com/spf13/viper"
 
 	"phasionary/internal/config"
-	"phasionary/internal/data"
 )
 
 func newInitCmd() *cobra.Command {
+	var local bool
+
 	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize the data directory with a default project",
+		Long:  "Initialize the data directory with a default project. With --local, create a .phasionary directory in the working directory instead; commands run in it or below use it in preference to the global data directory.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			dataDir, err := config.ResolveDataDir(viper.GetString("data"))
+			var dataDir string
+			var err error
+			if local {
+				dataDir, err = config.CreateLocalDir(".")
+			} else {
+				dataDir, err = config.ResolveDataDir(viper.GetString("data"))
+			}
+			if err != nil {
+				return err
+			}
+			store, err := openStore(dataDir)
 			if err != nil {
 				return err
 			}
-			store := data.NewStore(dataDir)
 			project, err := store.InitDefault()
 			if err != nil {
 				return err
@@ -29,5 +40,8 @@ func newInitCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&local, "local", false, "create a .phasionary directory in the working directory")
+
 	return cmd
 }
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"phasionary/internal/data"
+	"phasionary/internal/domain"
+)
+
+func newLogCmd() *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "Show recent task activity",
+		Long:  "Show task changes recorded in the activity log. Covers all projects unless one is selected with --project.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, err := domain.ParseSince(since, time.Now())
+			if err != nil {
+				return err
+			}
+
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			names, err := projectNames(store)
+			if err != nil {
+				return err
+			}
+			var events []data.ActivityEvent
+			if selector := viper.GetString("project"); selector != "" {
+				project, err := store.LoadProject(selector)
+				if err != nil {
+					return err
+				}
+				events, err = store.ActivityLog().Project(project.ID)
+				if err != nil {
+					return err
+				}
+			} else {
+				events, err = store.ActivityLog().All()
+				if err != nil {
+					return err
+				}
+			}
+
+			cutoff := from.UTC().Format(time.RFC3339)
+			recent := make([]data.ActivityEvent, 0, len(events))
+			for _, event := range events {
+				if event.At >= cutoff {
+					recent = append(recent, event)
+				}
+			}
+			return writeActivity(cmd.OutOrStdout(), recent, names, true)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "start of the log (YYYY-MM-DD, today, yesterday, 2h, 7d, 2w, 1m)")
+
+	return cmd
+}
+
+func newTaskLogCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "log <id-or-title>",
+		Aliases:           []string{"tl"},
+		Short:             "Show the history of a task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTasks,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			project, err := store.LoadProject(viper.GetString("project"))
+			if err != nil {
+				return err
+			}
+			task, _, _, err := resolveTask(project, args[0])
+			if err != nil {
+				return fmt.Errorf("task %q not found", args[0])
+			}
+
+			events, err := store.ActivityLog().Task(project.ID, task.ID)
+			if err != nil {
+				return err
+			}
+			return writeActivity(cmd.OutOrStdout(), events, map[string]string{project.ID: project.Name}, false)
+		},
+	}
+	return cmd
+}
+
+func projectNames(store *data.Store) (map[string]string, error) {
+	projects, err := store.ListSummaries()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(projects))
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+	return names, nil
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade project files to the current schema",
+		Long:  "Older project files are upgraded in memory whenever they are read and rewritten on the next save. migrate rewrites them all at once, keeping a copy of each original in its project's backups.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			plans, err := store.PlanMigrations()
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				return writeMigrations(cmd.OutOrStdout(), plans)
+			}
+			for _, plan := range plans {
+				if err := store.Migrate(plan); err != nil {
+					return fmt.Errorf("migrate %s: %w", plan.Name, err)
+				}
+				writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Migrated %s from schema %d to %d", plan.Name, plan.From, plan.To))
+			}
+			if len(plans) == 0 {
+				writeSuccess(cmd.OutOrStdout(), "All projects are up to date.")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "list the upgrades without writing anything")
+
+	return cmd
+}
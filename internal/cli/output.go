@@ -6,9 +6,11 @@ import (
 	"io"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/viper"
 
+	"phasionary/internal/data"
 	"phasionary/internal/domain"
 )
 
@@ -34,13 +36,18 @@ type ProjectListItem struct {
 	ID        string `json:"id"`
 	Name      string `json:"name"`
 	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+	Tasks     int    `json:"tasks"`
+	Completed int    `json:"completed"`
+	Encrypted bool   `json:"encrypted,omitempty"`
+	Archived  bool   `json:"archived,omitempty"`
 }
 
 type ProjectsOutput struct {
 	Projects []ProjectListItem `json:"projects"`
 }
 
-func writeProjects(w io.Writer, projects []domain.Project) error {
+func writeProjects(w io.Writer, projects []data.ProjectSummary) error {
 	if getOutputFormat() == FormatJSON {
 		output := ProjectsOutput{
 			Projects: make([]ProjectListItem, 0, len(projects)),
@@ -50,6 +57,11 @@ func writeProjects(w io.Writer, projects []domain.Project) error {
 				ID:        p.ID,
 				Name:      p.Name,
 				CreatedAt: p.CreatedAt,
+				UpdatedAt: p.UpdatedAt,
+				Tasks:     p.Tasks,
+				Completed: p.Completed,
+				Encrypted: p.Encrypted,
+				Archived:  p.Archived,
 			})
 		}
 		return writeJSON(w, output)
@@ -63,9 +75,16 @@ func writeProjects(w io.Writer, projects []domain.Project) error {
 	}
 
 	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(tw, "NAME\tID")
+	fmt.Fprintln(tw, "NAME\tTASKS\tID")
 	for _, p := range projects {
-		fmt.Fprintf(tw, "%s\t%s\n", p.Name, p.ID)
+		name, tasks := p.Name, fmt.Sprintf("%d/%d", p.Completed, p.Tasks)
+		if p.Encrypted {
+			name, tasks = name+" (encrypted)", "-"
+		}
+		if p.Archived {
+			name += " (archived)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, tasks, p.ID)
 	}
 	return tw.Flush()
 }
@@ -90,7 +109,7 @@ func writeCategories(w io.Writer, categories []domain.Category) error {
 			output.Categories = append(output.Categories, CategoryListItem{
 				ID:              c.ID,
 				Name:            c.Name,
-				TaskCount:       len(c.Tasks),
+				TaskCount:       c.CountTasks(),
 				EstimateMinutes: c.EstimateMinutes,
 			})
 		}
@@ -107,18 +126,28 @@ func writeCategories(w io.Writer, categories []domain.Category) error {
 	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
 	fmt.Fprintln(tw, "NAME\tTASKS\tID")
 	for _, c := range categories {
-		fmt.Fprintf(tw, "%s\t%d\t%s\n", c.Name, len(c.Tasks), c.ID)
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", c.Name, c.CountTasks(), c.ID)
 	}
 	return tw.Flush()
 }
 
 type TaskListItem struct {
-	ID              string `json:"id"`
-	Title           string `json:"title"`
-	Status          string `json:"status"`
-	Priority        string `json:"priority,omitempty"`
-	Category        string `json:"category"`
-	EstimateMinutes int    `json:"estimate_minutes,omitempty"`
+	ID              string             `json:"id"`
+	Title           string             `json:"title"`
+	Status          string             `json:"status"`
+	Priority        string             `json:"priority,omitempty"`
+	Category        string             `json:"category"`
+	EstimateMinutes int                `json:"estimate_minutes,omitempty"`
+	TrackedMinutes  int                `json:"tracked_minutes,omitempty"`
+	TimerRunning    bool               `json:"timer_running,omitempty"`
+	Deadline        string             `json:"deadline,omitempty"`
+	Section         string             `json:"section"`
+	Parent          string             `json:"parent,omitempty"`
+	BlockedBy       []string           `json:"blocked_by,omitempty"`
+	Blocked         bool               `json:"blocked"`
+	Tags            []string           `json:"tags,omitempty"`
+	Recurrence      *domain.Recurrence `json:"recurrence,omitempty"`
+	Depth           int                `json:"-"`
 }
 
 type TasksOutput struct {
@@ -139,13 +168,564 @@ func writeTaskList(w io.Writer, tasks []TaskListItem) error {
 	}
 
 	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(tw, "CATEGORY\tSTATUS\tPRIORITY\tTITLE")
+	fmt.Fprintln(tw, "CATEGORY\tSTATUS\tPRIORITY\tDUE\tTITLE")
 	for _, t := range tasks {
 		priority := t.Priority
 		if priority == "" {
 			priority = "-"
 		}
-		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.Category, t.Status, priority, t.Title)
+		deadline := t.Deadline
+		if deadline == "" {
+			deadline = "-"
+		}
+		title := strings.Repeat("  ", t.Depth) + domain.FormatTitleWithTags(t.Title, t.Tags)
+		if t.Blocked {
+			title += " [blocked]"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.Category, t.Status, priority, deadline, title)
+	}
+	return tw.Flush()
+}
+
+type AgendaItem struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	Priority  string `json:"priority,omitempty"`
+	Project   string `json:"project"`
+	Category  string `json:"category"`
+	Deadline  string `json:"deadline"`
+	DaysLeft  int    `json:"days_left"`
+	IsOverdue bool   `json:"overdue"`
+}
+
+type AgendaOutput struct {
+	Tasks []AgendaItem `json:"tasks"`
+}
+
+func writeAgenda(w io.Writer, items []AgendaItem) error {
+	if getOutputFormat() == FormatJSON {
+		output := AgendaOutput{Tasks: items}
+		if output.Tasks == nil {
+			output.Tasks = []AgendaItem{}
+		}
+		return writeJSON(w, output)
+	}
+
+	if len(items) == 0 {
+		if !isQuiet() {
+			fmt.Fprintln(w, "Nothing due.")
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DUE\tWHEN\tPROJECT\tCATEGORY\tTITLE")
+	for _, item := range items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", item.Deadline, formatDaysLeft(item.DaysLeft), item.Project, item.Category, item.Title)
+	}
+	return tw.Flush()
+}
+
+func formatDaysLeft(days int) string {
+	switch {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	case days == -1:
+		return "1 day overdue"
+	case days < 0:
+		return fmt.Sprintf("%d days overdue", -days)
+	default:
+		return fmt.Sprintf("in %d days", days)
+	}
+}
+
+type TimeReportOutput struct {
+	Since        string              `json:"since"`
+	TotalMinutes int                 `json:"total_minutes"`
+	Projects     []ProjectTimeReport `json:"projects"`
+}
+
+type ProjectTimeReport struct {
+	Project        string               `json:"project"`
+	TrackedMinutes int                  `json:"tracked_minutes"`
+	Categories     []CategoryTimeReport `json:"categories"`
+}
+
+type CategoryTimeReport struct {
+	Category        string `json:"category"`
+	TrackedMinutes  int    `json:"tracked_minutes"`
+	EstimateMinutes int    `json:"estimate_minutes,omitempty"`
+	Tasks           int    `json:"tasks"`
+}
+
+func writeTimeReport(w io.Writer, report TimeReportOutput) error {
+	if getOutputFormat() == FormatJSON {
+		return writeJSON(w, report)
+	}
+
+	if len(report.Projects) == 0 {
+		if !isQuiet() {
+			fmt.Fprintf(w, "No time tracked since %s.\n", report.Since)
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROJECT\tCATEGORY\tTASKS\tTRACKED\tESTIMATE")
+	for _, project := range report.Projects {
+		for _, cat := range project.Categories {
+			estimate := "-"
+			if cat.EstimateMinutes > 0 {
+				estimate = formatDuration(cat.EstimateMinutes)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", project.Project, cat.Category, cat.Tasks, formatDuration(cat.TrackedMinutes), estimate)
+		}
+		if len(report.Projects) > 1 {
+			fmt.Fprintf(tw, "%s\t(total)\t\t%s\t\n", project.Project, formatDuration(project.TrackedMinutes))
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nTotal since %s: %s\n", report.Since, formatDuration(report.TotalMinutes))
+	return nil
+}
+
+type ArchiveListItem struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`
+	Category   string `json:"category"`
+	FinishedAt string `json:"finished_at,omitempty"`
+	ArchivedAt string `json:"archived_at"`
+}
+
+type ArchiveOutput struct {
+	Tasks []ArchiveListItem `json:"tasks"`
+}
+
+func writeArchive(w io.Writer, archive []domain.ArchivedTask) error {
+	listItems := make([]ArchiveListItem, 0, len(archive))
+	for _, entry := range archive {
+		listItems = append(listItems, ArchiveListItem{
+			ID:         entry.Task.ID,
+			Title:      entry.Task.Title,
+			Status:     entry.Task.Status,
+			Category:   entry.CategoryName,
+			FinishedAt: entry.Task.FinishedAt(),
+			ArchivedAt: entry.ArchivedAt,
+		})
+	}
+
+	if getOutputFormat() == FormatJSON {
+		return writeJSON(w, ArchiveOutput{Tasks: listItems})
+	}
+
+	if len(listItems) == 0 {
+		if !isQuiet() {
+			fmt.Fprintln(w, "Archive is empty.")
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tFINISHED\tCATEGORY\tTITLE")
+	for _, item := range listItems {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", shortTrashID(item.ID), item.Status, formatLocalTimestamp(item.FinishedAt), item.Category, item.Title)
+	}
+	return tw.Flush()
+}
+
+type TrashListItem struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Title     string `json:"title"`
+	Project   string `json:"project"`
+	Category  string `json:"category,omitempty"`
+	DeletedAt string `json:"deleted_at"`
+}
+
+type TrashOutput struct {
+	Items []TrashListItem `json:"items"`
+}
+
+func writeTrash(w io.Writer, items []data.TrashItem) error {
+	listItems := make([]TrashListItem, 0, len(items))
+	for _, item := range items {
+		listItems = append(listItems, TrashListItem{
+			ID:        item.ID,
+			Kind:      item.Kind,
+			Title:     item.Title(),
+			Project:   item.ProjectName,
+			Category:  item.CategoryName,
+			DeletedAt: item.DeletedAt,
+		})
+	}
+
+	if getOutputFormat() == FormatJSON {
+		return writeJSON(w, TrashOutput{Items: listItems})
+	}
+
+	if len(listItems) == 0 {
+		if !isQuiet() {
+			fmt.Fprintln(w, "Trash is empty.")
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tKIND\tDELETED\tPROJECT\tCATEGORY\tTITLE")
+	for _, item := range listItems {
+		category := item.Category
+		if category == "" || item.Kind == data.TrashKindCategory {
+			category = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", shortTrashID(item.ID), item.Kind, formatLocalTimestamp(item.DeletedAt), item.Project, category, item.Title)
+	}
+	return tw.Flush()
+}
+
+func shortTrashID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// formatLocalTimestamp shows an RFC 3339 timestamp in local time.
+func formatLocalTimestamp(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+type BackupListItem struct {
+	TakenAt    string `json:"taken_at"`
+	Revision   int    `json:"revision"`
+	Categories int    `json:"categories"`
+	Tasks      int    `json:"tasks"`
+}
+
+type BackupsOutput struct {
+	Snapshots []BackupListItem `json:"snapshots"`
+}
+
+func writeBackups(w io.Writer, store *data.Store, snapshots []data.Snapshot) error {
+	listItems := make([]BackupListItem, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		item := BackupListItem{
+			TakenAt:  snapshot.TakenAt.UTC().Format(time.RFC3339),
+			Revision: snapshot.Revision,
+		}
+		if project, err := store.LoadSnapshot(snapshot); err == nil {
+			item.Categories = len(project.Categories)
+			for _, cat := range project.Categories {
+				item.Tasks += cat.CountTasks()
+			}
+		}
+		listItems = append(listItems, item)
+	}
+
+	if getOutputFormat() == FormatJSON {
+		return writeJSON(w, BackupsOutput{Snapshots: listItems})
+	}
+
+	if len(listItems) == 0 {
+		if !isQuiet() {
+			fmt.Fprintln(w, "No snapshots yet.")
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TAKEN\tREVISION\tCATEGORIES\tTASKS")
+	for _, item := range listItems {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", formatLocalTimestamp(item.TakenAt), item.Revision, item.Categories, item.Tasks)
+	}
+	return tw.Flush()
+}
+
+type BackupDiffItem struct {
+	Change   string   `json:"change"`
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Category string   `json:"category"`
+	Fields   []string `json:"fields,omitempty"`
+}
+
+type BackupDiffOutput struct {
+	SnapshotTakenAt string           `json:"snapshot_taken_at"`
+	Changes         []BackupDiffItem `json:"changes"`
+}
+
+func writeBackupDiff(w io.Writer, snapshot data.Snapshot, changes []domain.TaskChange) error {
+	output := BackupDiffOutput{
+		SnapshotTakenAt: snapshot.TakenAt.UTC().Format(time.RFC3339),
+		Changes:         make([]BackupDiffItem, 0, len(changes)),
+	}
+	for _, change := range changes {
+		output.Changes = append(output.Changes, BackupDiffItem{
+			Change:   change.Kind,
+			ID:       change.TaskID,
+			Title:    change.Title,
+			Category: change.Category,
+			Fields:   change.Fields,
+		})
+	}
+
+	if getOutputFormat() == FormatJSON {
+		return writeJSON(w, output)
+	}
+
+	if !isQuiet() {
+		fmt.Fprintf(w, "Changes since snapshot from %s:\n\n", formatLocalTimestamp(output.SnapshotTakenAt))
+	}
+	if len(output.Changes) == 0 {
+		if !isQuiet() {
+			fmt.Fprintln(w, "No task changes.")
+		}
+		return nil
+	}
+
+	markers := map[string]string{
+		domain.ChangeAdded:   "+",
+		domain.ChangeRemoved: "-",
+		domain.ChangeChanged: "~",
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, item := range output.Changes {
+		title := item.Title
+		if len(item.Fields) > 0 {
+			title += " (" + strings.Join(item.Fields, ", ") + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", markers[item.Change], item.Category, title)
+	}
+	return tw.Flush()
+}
+
+type MigrationItem struct {
+	ID    string   `json:"id"`
+	Name  string   `json:"name"`
+	From  int      `json:"from"`
+	To    int      `json:"to"`
+	Steps []string `json:"steps"`
+}
+
+type MigrationsOutput struct {
+	Migrations []MigrationItem `json:"migrations"`
+}
+
+func writeMigrations(w io.Writer, plans []data.MigrationPlan) error {
+	listItems := make([]MigrationItem, 0, len(plans))
+	for _, plan := range plans {
+		listItems = append(listItems, MigrationItem{
+			ID:    plan.ProjectID,
+			Name:  plan.Name,
+			From:  plan.From,
+			To:    plan.To,
+			Steps: plan.Steps,
+		})
+	}
+
+	if getOutputFormat() == FormatJSON {
+		return writeJSON(w, MigrationsOutput{Migrations: listItems})
+	}
+
+	if len(listItems) == 0 {
+		if !isQuiet() {
+			fmt.Fprintln(w, "All projects are up to date.")
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROJECT\tFROM\tTO\tSTEPS")
+	for _, item := range listItems {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", item.Name, item.From, item.To, strings.Join(item.Steps, "; "))
+	}
+	return tw.Flush()
+}
+
+type DoctorItem struct {
+	Path    string `json:"path"`
+	Project string `json:"project,omitempty"`
+	Problem string `json:"problem"`
+	Fixable bool   `json:"fixable"`
+	Fixed   bool   `json:"fixed"`
+}
+
+type DoctorOutput struct {
+	Issues []DoctorItem `json:"issues"`
+}
+
+func writeDoctor(w io.Writer, issues []data.DoctorIssue) error {
+	listItems := make([]DoctorItem, 0, len(issues))
+	for _, issue := range issues {
+		listItems = append(listItems, DoctorItem{
+			Path:    issue.Path,
+			Project: issue.Project,
+			Problem: issue.Message,
+			Fixable: issue.Fixable,
+			Fixed:   issue.Fixed,
+		})
+	}
+
+	if getOutputFormat() == FormatJSON {
+		return writeJSON(w, DoctorOutput{Issues: listItems})
+	}
+
+	if len(listItems) == 0 {
+		if !isQuiet() {
+			fmt.Fprintln(w, "No problems found.")
+		}
+		return nil
+	}
+
+	fixable := 0
+	path := ""
+	for _, item := range listItems {
+		if item.Path != path {
+			path = item.Path
+			if item.Project != "" {
+				fmt.Fprintf(w, "%s (%s)\n", path, item.Project)
+			} else {
+				fmt.Fprintln(w, path)
+			}
+		}
+		mark := ""
+		switch {
+		case item.Fixed:
+			mark = " [fixed]"
+		case item.Fixable:
+			mark = " [fixable]"
+			fixable++
+		}
+		fmt.Fprintf(w, "  - %s%s\n", item.Problem, mark)
+	}
+	if fixable > 0 && !isQuiet() {
+		fmt.Fprintf(w, "\nRun `phasionary doctor --fix` to repair the %d fixable problem(s).\n", fixable)
+	}
+	return nil
+}
+
+type HistoryItem struct {
+	Hash    string `json:"hash"`
+	Date    string `json:"date"`
+	Author  string `json:"author"`
+	Message string `json:"message"`
+}
+
+type HistoryOutput struct {
+	Commits []HistoryItem `json:"commits"`
+}
+
+func writeHistory(w io.Writer, commits []data.GitCommit) error {
+	listItems := make([]HistoryItem, 0, len(commits))
+	for _, commit := range commits {
+		listItems = append(listItems, HistoryItem{
+			Hash:    commit.Hash,
+			Date:    commit.At.UTC().Format(time.RFC3339),
+			Author:  commit.Author,
+			Message: commit.Subject,
+		})
+	}
+
+	if getOutputFormat() == FormatJSON {
+		return writeJSON(w, HistoryOutput{Commits: listItems})
+	}
+
+	if len(listItems) == 0 {
+		if !isQuiet() {
+			fmt.Fprintln(w, "No history yet.")
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REV\tDATE\tAUTHOR\tMESSAGE")
+	for i, item := range listItems {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", commits[i].Short, formatLocalTimestamp(item.Date), item.Author, item.Message)
+	}
+	return tw.Flush()
+}
+
+type ActivityItem struct {
+	At        string `json:"at"`
+	ProjectID string `json:"project_id"`
+	Project   string `json:"project,omitempty"`
+	TaskID    string `json:"task_id"`
+	Task      string `json:"task"`
+	Kind      string `json:"kind"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	Actor     string `json:"actor,omitempty"`
+	Via       string `json:"via,omitempty"`
+	Summary   string `json:"summary"`
+}
+
+type ActivityOutput struct {
+	Events []ActivityItem `json:"events"`
+}
+
+// writeActivity prints a timeline of events. showTask adds the project and
+// task columns, which a single task's history does not need.
+func writeActivity(w io.Writer, events []data.ActivityEvent, projectNames map[string]string, showTask bool) error {
+	items := make([]ActivityItem, 0, len(events))
+	for _, event := range events {
+		items = append(items, ActivityItem{
+			At:        event.At,
+			ProjectID: event.ProjectID,
+			Project:   projectNames[event.ProjectID],
+			TaskID:    event.TaskID,
+			Task:      event.Task,
+			Kind:      event.Kind,
+			From:      event.From,
+			To:        event.To,
+			Actor:     event.Actor,
+			Via:       event.Via,
+			Summary:   event.Summary(),
+		})
+	}
+
+	if getOutputFormat() == FormatJSON {
+		return writeJSON(w, ActivityOutput{Events: items})
+	}
+
+	if len(items) == 0 {
+		if !isQuiet() {
+			fmt.Fprintln(w, "No activity recorded.")
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if showTask {
+		fmt.Fprintln(tw, "WHEN\tBY\tPROJECT\tTASK\tCHANGE")
+	} else {
+		fmt.Fprintln(tw, "WHEN\tBY\tCHANGE")
+	}
+	for _, item := range items {
+		by := item.Actor
+		if item.Via != "" {
+			by = strings.TrimSpace(by + " (" + item.Via + ")")
+		}
+		if by == "" {
+			by = "-"
+		}
+		if showTask {
+			project := item.Project
+			if project == "" {
+				project = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", formatLocalTimestamp(item.At), by, project, item.Task, item.Summary)
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", formatLocalTimestamp(item.At), by, item.Summary)
+		}
 	}
 	return tw.Flush()
 }
@@ -155,18 +735,41 @@ type TaskDetailOutput struct {
 }
 
 type TaskDetail struct {
-	ID              string `json:"id"`
-	Title           string `json:"title"`
-	Status          string `json:"status"`
-	Priority        string `json:"priority,omitempty"`
-	Category        string `json:"category"`
-	EstimateMinutes int    `json:"estimate_minutes,omitempty"`
-	CreatedAt       string `json:"created_at"`
-	UpdatedAt       string `json:"updated_at"`
-	CompletionDate  string `json:"completion_date,omitempty"`
+	ID              string             `json:"id"`
+	Title           string             `json:"title"`
+	Status          string             `json:"status"`
+	Priority        string             `json:"priority,omitempty"`
+	Category        string             `json:"category"`
+	EstimateMinutes int                `json:"estimate_minutes,omitempty"`
+	TrackedMinutes  int                `json:"tracked_minutes,omitempty"`
+	TimerRunning    bool               `json:"timer_running,omitempty"`
+	Deadline        string             `json:"deadline,omitempty"`
+	Section         string             `json:"section"`
+	CreatedAt       string             `json:"created_at"`
+	UpdatedAt       string             `json:"updated_at"`
+	CompletionDate  string             `json:"completion_date,omitempty"`
+	Description     string             `json:"description,omitempty"`
+	Notes           string             `json:"notes,omitempty"`
+	Tags            []string           `json:"tags,omitempty"`
+	Recurrence      *domain.Recurrence `json:"recurrence,omitempty"`
+	Subtasks        []SubtaskItem      `json:"subtasks,omitempty"`
+	BlockedBy       []BlockerItem      `json:"blocked_by,omitempty"`
 }
 
-func writeTaskDetail(w io.Writer, task domain.Task, categoryName string) error {
+type BlockerItem struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+type SubtaskItem struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Depth  int    `json:"depth"`
+}
+
+func writeTaskDetail(w io.Writer, project domain.Project, task domain.Task, categoryName string) error {
 	detail := TaskDetail{
 		ID:              task.ID,
 		Title:           task.Title,
@@ -174,10 +777,35 @@ func writeTaskDetail(w io.Writer, task domain.Task, categoryName string) error {
 		Priority:        task.Priority,
 		Category:        categoryName,
 		EstimateMinutes: task.EstimateMinutes,
+		TrackedMinutes:  int(task.TotalTrackedDuration(time.Now()) / time.Minute),
+		TimerRunning:    task.IsTimerRunning(),
+		Deadline:        task.Deadline,
+		Section:         task.SectionName(),
 		CreatedAt:       task.CreatedAt,
 		UpdatedAt:       task.UpdatedAt,
 		CompletionDate:  task.CompletionDate,
+		Description:     task.Description,
+		Notes:           task.Notes,
+		Tags:            task.Tags,
+		Recurrence:      task.Recurrence,
 	}
+	for _, id := range task.BlockedBy {
+		if blocker, _, ok := project.FindTask(id); ok {
+			detail.BlockedBy = append(detail.BlockedBy, BlockerItem{
+				ID:     blocker.ID,
+				Title:  blocker.Title,
+				Status: blocker.Status,
+			})
+		}
+	}
+	domain.WalkTasks(task.Subtasks, func(sub *domain.Task, depth int) {
+		detail.Subtasks = append(detail.Subtasks, SubtaskItem{
+			ID:     sub.ID,
+			Title:  sub.Title,
+			Status: sub.Status,
+			Depth:  depth,
+		})
+	})
 
 	if getOutputFormat() == FormatJSON {
 		output := TaskDetailOutput{Task: detail}
@@ -188,20 +816,66 @@ func writeTaskDetail(w io.Writer, task domain.Task, categoryName string) error {
 	fmt.Fprintf(w, "ID:       %s\n", detail.ID)
 	fmt.Fprintf(w, "Category: %s\n", detail.Category)
 	fmt.Fprintf(w, "Status:   %s\n", detail.Status)
+	fmt.Fprintf(w, "Section:  %s\n", detail.Section)
 	if detail.Priority != "" {
 		fmt.Fprintf(w, "Priority: %s\n", detail.Priority)
 	}
 	if detail.EstimateMinutes > 0 {
 		fmt.Fprintf(w, "Estimate: %s\n", formatDuration(detail.EstimateMinutes))
 	}
+	if detail.TrackedMinutes > 0 || detail.TimerRunning {
+		running := ""
+		if detail.TimerRunning {
+			running = " (running)"
+		}
+		fmt.Fprintf(w, "Tracked:  %s%s\n", formatDuration(detail.TrackedMinutes), running)
+	}
+	if detail.Deadline != "" {
+		fmt.Fprintf(w, "Due:      %s\n", detail.Deadline)
+	}
+	if len(detail.Tags) > 0 {
+		fmt.Fprintf(w, "Tags:     %s\n", strings.TrimSpace(domain.FormatTitleWithTags("", detail.Tags)))
+	}
+	if detail.Recurrence != nil {
+		fmt.Fprintf(w, "Repeats:  %s\n", detail.Recurrence)
+	}
 	fmt.Fprintf(w, "Created:  %s\n", detail.CreatedAt)
 	fmt.Fprintf(w, "Updated:  %s\n", detail.UpdatedAt)
 	if detail.CompletionDate != "" {
 		fmt.Fprintf(w, "Completed: %s\n", detail.CompletionDate)
 	}
+	if detail.Description != "" {
+		fmt.Fprintf(w, "\nDescription:\n%s\n", indentText(detail.Description, "  "))
+	}
+	if detail.Notes != "" {
+		fmt.Fprintf(w, "\nNotes:\n%s\n", indentText(detail.Notes, "  "))
+	}
+	if len(detail.BlockedBy) > 0 {
+		fmt.Fprintln(w, "\nBlocked by:")
+		for _, blocker := range detail.BlockedBy {
+			fmt.Fprintf(w, "  - [%s] %s (%s)\n", blocker.Status, blocker.Title, blocker.ID)
+		}
+	}
+	if len(detail.Subtasks) > 0 {
+		done, total := task.SubtaskProgress()
+		fmt.Fprintf(w, "\nSubtasks (%d/%d done):\n", done, total)
+		for _, sub := range detail.Subtasks {
+			fmt.Fprintf(w, "%s- [%s] %s (%s)\n", strings.Repeat("  ", sub.Depth+1), sub.Status, sub.Title, sub.ID)
+		}
+	}
 	return nil
 }
 
+func indentText(text, indent string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 type ProjectDetailOutput struct {
 	Project ProjectDetail `json:"project"`
 }
@@ -214,13 +888,14 @@ type ProjectDetail struct {
 	CategoryCount int      `json:"category_count"`
 	TaskCount     int      `json:"task_count"`
 	Categories    []string `json:"categories"`
+	Archived      bool     `json:"archived,omitempty"`
 }
 
 func writeProjectDetail(w io.Writer, project domain.Project) error {
 	taskCount := 0
 	categories := make([]string, 0, len(project.Categories))
 	for _, c := range project.Categories {
-		taskCount += len(c.Tasks)
+		taskCount += c.CountTasks()
 		categories = append(categories, c.Name)
 	}
 
@@ -232,6 +907,7 @@ func writeProjectDetail(w io.Writer, project domain.Project) error {
 		CategoryCount: len(project.Categories),
 		TaskCount:     taskCount,
 		Categories:    categories,
+		Archived:      project.Archived,
 	}
 
 	if getOutputFormat() == FormatJSON {
@@ -245,6 +921,9 @@ func writeProjectDetail(w io.Writer, project domain.Project) error {
 	fmt.Fprintf(w, "Tasks:      %d\n", detail.TaskCount)
 	fmt.Fprintf(w, "Created:    %s\n", detail.CreatedAt)
 	fmt.Fprintf(w, "Updated:    %s\n", detail.UpdatedAt)
+	if detail.Archived {
+		fmt.Fprintln(w, "Archived:   yes")
+	}
 	if len(categories) > 0 {
 		fmt.Fprintf(w, "\nCategories: %s\n", strings.Join(categories, ", "))
 	}
@@ -270,7 +949,7 @@ func writeCategoryDetail(w io.Writer, cat domain.Category) error {
 		Name:            cat.Name,
 		CreatedAt:       cat.CreatedAt,
 		UpdatedAt:       cat.UpdatedAt,
-		TaskCount:       len(cat.Tasks),
+		TaskCount:       cat.CountTasks(),
 		EstimateMinutes: cat.EstimateMinutes,
 	}
 
@@ -292,6 +971,39 @@ func writeCategoryDetail(w io.Writer, cat domain.Category) error {
 	return nil
 }
 
+type TagListItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type TagsOutput struct {
+	Tags []TagListItem `json:"tags"`
+}
+
+func writeTags(w io.Writer, tags []TagListItem) error {
+	if getOutputFormat() == FormatJSON {
+		output := TagsOutput{Tags: tags}
+		if output.Tags == nil {
+			output.Tags = []TagListItem{}
+		}
+		return writeJSON(w, output)
+	}
+
+	if len(tags) == 0 {
+		if !isQuiet() {
+			fmt.Fprintln(w, "No tags found.")
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TAG\tTASKS")
+	for _, tag := range tags {
+		fmt.Fprintf(tw, "#%s\t%d\n", tag.Name, tag.Count)
+	}
+	return tw.Flush()
+}
+
 func writeJSON(w io.Writer, v any) error {
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
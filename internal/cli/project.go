@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -11,6 +13,8 @@ import (
 )
 
 func newProjectsCmd() *cobra.Command {
+	var all bool
+
 	cmd := &cobra.Command{
 		Use:     "projects",
 		Aliases: []string{"ps"},
@@ -20,13 +24,25 @@ func newProjectsCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			projects, err := store.ListProjects()
+			projects, err := store.ListSummaries()
 			if err != nil {
 				return err
 			}
+			if !all {
+				active := projects[:0]
+				for _, p := range projects {
+					if !p.Archived {
+						active = append(active, p)
+					}
+				}
+				projects = active
+			}
 			return writeProjects(cmd.OutOrStdout(), projects)
 		},
 	}
+
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "include archived projects")
+
 	return cmd
 }
 
@@ -41,6 +57,10 @@ func newProjectCmd() *cobra.Command {
 	cmd.AddCommand(newProjectEditCmd())
 	cmd.AddCommand(newProjectDeleteCmd())
 	cmd.AddCommand(newProjectUseCmd())
+	cmd.AddCommand(newProjectEncryptCmd(true))
+	cmd.AddCommand(newProjectEncryptCmd(false))
+	cmd.AddCommand(newProjectArchiveCmd(true))
+	cmd.AddCommand(newProjectArchiveCmd(false))
 
 	return cmd
 }
@@ -121,7 +141,7 @@ func newProjectEditCmd() *cobra.Command {
 			}
 
 			project.Name = name
-			if err := store.SaveProject(project); err != nil {
+			if err := store.SaveProject(&project); err != nil {
 				return err
 			}
 
@@ -174,7 +194,7 @@ func newProjectDeleteCmd() *cobra.Command {
 				return err
 			}
 
-			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Deleted project: %s", project.Name))
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Moved project to trash: %s", project.Name))
 			return nil
 		},
 	}
@@ -221,10 +241,200 @@ func newProjectUseCmd() *cobra.Command {
 	return cmd
 }
 
+// newProjectArchiveCmd builds "project archive", or "project unarchive" when
+// archive is false.
+func newProjectArchiveCmd(archive bool) *cobra.Command {
+	use, short, done := "unarchive", "Bring an archived project back", "Unarchived project"
+	if archive {
+		use, short, done = "archive", "Hide a finished project from listings", "Archived project"
+	}
+
+	cmd := &cobra.Command{
+		Use:               use + " [name-or-id]",
+		Short:             short,
+		Long:              short + ". Archived projects are left out of `projects` (unless --all is given) and of the project picker (unless revealed with A), but can still be opened by name.",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeProjects,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			selector := viper.GetString("project")
+			if len(args) > 0 {
+				selector = args[0]
+			}
+			project, err := store.LoadProject(selector)
+			if err != nil {
+				return err
+			}
+			if project.Archived == archive {
+				writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Project %s is already %sd", project.Name, use))
+				return nil
+			}
+			project.Archived = archive
+			if err := store.SaveProject(&project); err != nil {
+				return err
+			}
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("%s: %s", done, project.Name))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// newProjectEncryptCmd builds "project encrypt", or "project decrypt" when
+// encrypt is false.
+func newProjectEncryptCmd(encrypt bool) *cobra.Command {
+	use, short, done := "decrypt", "Store a project in plain text again", "Decrypted project"
+	if encrypt {
+		use, short, done = "encrypt", "Encrypt a project and its snapshots", "Encrypted project"
+	}
+
+	cmd := &cobra.Command{
+		Use:               use + " [name-or-id]",
+		Short:             short,
+		Long:              short + ". The passphrase comes from PHASIONARY_KEY, a key file (PHASIONARY_KEY_FILE or key_file in the config) or a prompt. Activity, trash entries and git history recorded before encrypting are left as they were.",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeProjects,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			if encrypt && !store.Keys.HasPassphrase() {
+				passphrase, err := newPassphrase()
+				if err != nil {
+					return err
+				}
+				store.Keys.SetPassphrase(passphrase)
+			}
+			selector := viper.GetString("project")
+			if len(args) > 0 {
+				selector = args[0]
+			}
+			project, err := store.LoadProject(selector)
+			if err != nil {
+				return err
+			}
+			if project.Encrypted == encrypt {
+				writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Project %s is already %sed", project.Name, use))
+				return nil
+			}
+			if _, err := store.SetEncrypted(project.ID, encrypt); err != nil {
+				return err
+			}
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("%s: %s", done, project.Name))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// newPassphrase asks for a new passphrase twice.
+func newPassphrase() ([]byte, error) {
+	if !config.CanPrompt() {
+		return nil, fmt.Errorf("no passphrase: set %s or a key file", config.EnvKey)
+	}
+	passphrase, err := config.PromptPassphrase("New passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase must not be empty")
+	}
+	again, err := config.PromptPassphrase("Repeat passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if string(again) != string(passphrase) {
+		return nil, errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
 func storeFromViper() (*data.Store, error) {
 	dataDir, err := config.ResolveDataDir(viper.GetString("data"))
 	if err != nil {
 		return nil, err
 	}
-	return data.NewStore(dataDir), nil
+	return openStore(dataDir)
+}
+
+// openStore opens the projects directory dataDir with the configured storage,
+// backups and git history.
+func openStore(dataDir string) (*data.Store, error) {
+	cfgManager, err := configFromViper()
+	if err != nil {
+		return nil, err
+	}
+	store, err := newStore(dataDir, cfgManager.Get())
+	if err != nil {
+		return nil, err
+	}
+	store.Backups = backupPolicy(cfgManager.Get())
+	if cfgManager.Get().GitHistory && cfgManager.Get().Storage == config.StorageSQLite {
+		fmt.Fprintln(os.Stderr, "warning: git history only works with JSON storage")
+	} else if cfgManager.Get().GitHistory && !config.IsLocalDataDir(dataDir) {
+		// A local data directory is versioned by the repository around it.
+		history := data.NewGitHistory(store.Dir)
+		if err := history.Init(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: git history disabled: %v\n", err)
+		} else {
+			store.History = history
+		}
+	}
+	return store, nil
+}
+
+func configFromViper() (*config.Manager, error) {
+	configPath, err := config.ResolveConfigPath(viper.GetString("config"))
+	if err != nil {
+		return nil, err
+	}
+	cfgManager := config.NewManager(configPath)
+	if err := cfgManager.Load(); err != nil {
+		return nil, err
+	}
+	return cfgManager, nil
+}
+
+// backupPolicy turns the backup settings into the store's snapshot policy.
+func backupPolicy(cfg config.Config) data.BackupPolicy {
+	policy := data.DefaultBackupPolicy()
+	policy.EverySaves = cfg.BackupEverySaves
+	policy.Retention = cfg.BackupRetention()
+	return policy
+}
+
+// newStore opens the data directory with the configured storage, warning on
+// stderr about project files that cannot be read.
+func newStore(dataDir string, cfg config.Config) (*data.Store, error) {
+	store := data.NewStore(dataDir)
+	if cfg.Storage == config.StorageSQLite {
+		var err error
+		if store, err = data.OpenSQLiteStore(dataDir); err != nil {
+			return nil, err
+		}
+	}
+	store.Via = "cli"
+	passphrase, err := config.Passphrase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if passphrase != nil {
+		store.Keys.SetPassphrase(passphrase)
+	} else if config.CanPrompt() {
+		store.Keys.Prompt = func() ([]byte, error) {
+			return config.PromptPassphrase("Passphrase for encrypted projects: ")
+		}
+	}
+	store.OnUnreadable = func(f data.UnreadableFile) {
+		if f.QuarantinePath != "" {
+			fmt.Fprintf(os.Stderr, "warning: skipped unreadable project file %s: %v (moved to %s)\n", f.Path, f.Err, f.QuarantinePath)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "warning: skipped unreadable project file %s: %v\n", f.Path, f.Err)
+	}
+	return store, nil
 }
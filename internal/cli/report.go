@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"phasionary/internal/data"
+	"phasionary/internal/domain"
+)
+
+func newReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Summarise project activity",
+	}
+
+	cmd.AddCommand(newReportTimeCmd())
+
+	return cmd
+}
+
+func newReportTimeCmd() *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "time",
+		Short: "Summarise tracked time per project and category",
+		Long:  "Summarise tracked time per project and category. Covers all projects unless one is selected with --project.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			now := time.Now()
+			from, err := domain.ParseSince(since, now)
+			if err != nil {
+				return err
+			}
+
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			var projects []domain.Project
+			if selector := viper.GetString("project"); selector != "" {
+				project, err := store.LoadProject(selector)
+				if err != nil {
+					return err
+				}
+				projects = []domain.Project{project}
+			} else {
+				projects, err = data.LoadProjects(store)
+				if err != nil {
+					return err
+				}
+			}
+
+			report := TimeReportOutput{Since: from.Format(domain.DateLayout), Projects: []ProjectTimeReport{}}
+			for _, project := range projects {
+				projectReport := ProjectTimeReport{Project: project.Name, Categories: []CategoryTimeReport{}}
+				// Time tracked on archived tasks still counts for their category.
+				archived := make(map[string][]domain.Task)
+				for _, entry := range project.Archive {
+					archived[entry.CategoryID] = append(archived[entry.CategoryID], entry.Task)
+				}
+				for _, cat := range project.Categories {
+					catReport := CategoryTimeReport{Category: cat.Name}
+					domain.WalkTasks(append(slices.Clone(cat.Tasks), archived[cat.ID]...), func(task *domain.Task, _ int) {
+						tracked := task.TrackedBetween(from, now)
+						if tracked <= 0 {
+							return
+						}
+						catReport.TrackedMinutes += int(tracked / time.Minute)
+						catReport.EstimateMinutes += task.EstimateMinutes
+						catReport.Tasks++
+					})
+					if catReport.Tasks == 0 {
+						continue
+					}
+					projectReport.TrackedMinutes += catReport.TrackedMinutes
+					projectReport.Categories = append(projectReport.Categories, catReport)
+				}
+				if len(projectReport.Categories) == 0 {
+					continue
+				}
+				report.TotalMinutes += projectReport.TrackedMinutes
+				report.Projects = append(report.Projects, projectReport)
+			}
+
+			return writeTimeReport(cmd.OutOrStdout(), report)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "start of the report (YYYY-MM-DD, today, yesterday, 2h, 7d, 2w, 1m)")
+
+	return cmd
+}
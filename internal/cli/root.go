@@ -82,10 +82,22 @@ func newRootCmd() *cobra.Command {
 	cmd.AddCommand(newProjectsCmd())
 	cmd.AddCommand(newTaskCmd())
 	cmd.AddCommand(newTasksCmd())
+	cmd.AddCommand(newAgendaCmd())
+	cmd.AddCommand(newReportCmd())
+	cmd.AddCommand(newLogCmd())
+	cmd.AddCommand(newTagsCmd())
 	cmd.AddCommand(newCategoryCmd())
 	cmd.AddCommand(newCategoriesCmd())
 	cmd.AddCommand(newExportCmd())
 	cmd.AddCommand(newImportCmd())
+	cmd.AddCommand(newTrashCmd())
+	cmd.AddCommand(newArchiveCmd())
+	cmd.AddCommand(newBackupCmd())
+	cmd.AddCommand(newMigrateCmd())
+	cmd.AddCommand(newDoctorCmd())
+	cmd.AddCommand(newHistoryCmd())
+	cmd.AddCommand(newRevertCmd())
+	cmd.AddCommand(newStorageCmd())
 	cmd.AddCommand(newConfigCmd())
 	cmd.AddCommand(newVersionCmd())
 	cmd.AddCommand(newCompletionCmd())
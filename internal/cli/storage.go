@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"phasionary/internal/config"
+)
+
+func newStorageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "storage",
+		Short: "Show or change where projects are stored",
+		Long:  "Projects are stored as JSON files by default. The sqlite storage keeps them in a single database next to the projects directory, which lets task filters run without loading every project.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgManager, err := configFromViper()
+			if err != nil {
+				return err
+			}
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+			fmt.Fprintf(cmd.OutOrStdout(), "%s (%s)\n", cfgManager.Get().Storage, store.Location())
+			return nil
+		},
+	}
+
+	cmd.AddCommand(newStorageMigrateCmd())
+
+	return cmd
+}
+
+func newStorageMigrateCmd() *cobra.Command {
+	var to string
+
+	cmd := &cobra.Command{
+		Use:   "migrate --to <json|sqlite>",
+		Short: "Copy every project to another storage and switch to it",
+		Long:  "Copy every project to the other storage, then set storage in the config. Projects the other storage still holds from an earlier switch, and that have since been deleted here, are removed from it. The old copy is left in place and can be removed by hand.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if to != config.StorageJSON && to != config.StorageSQLite {
+				return fmt.Errorf("invalid --to: %s (use json or sqlite)", to)
+			}
+			cfgManager, err := configFromViper()
+			if err != nil {
+				return err
+			}
+			current := cfgManager.Get()
+			if current.Storage == to {
+				return fmt.Errorf("projects are already stored as %s", to)
+			}
+			dataDir, err := config.ResolveDataDir(viper.GetString("data"))
+			if err != nil {
+				return err
+			}
+			source, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			defer source.Close()
+			target := current
+			target.Storage = to
+			dest, err := newStore(dataDir, target)
+			if err != nil {
+				return err
+			}
+			defer dest.Close()
+
+			copied, removed, err := source.CopyProjects(dest)
+			if err != nil {
+				return err
+			}
+			if err := cfgManager.Update(func(c *config.Config) {
+				c.Storage = to
+			}); err != nil {
+				return err
+			}
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Copied %d projects to %s", copied, dest.Location()))
+			if removed > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "Removed %d stale projects left there by an earlier switch.\n", removed)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Now using %s storage. The previous copy in %s was left in place.\n", to, source.Location())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "target storage (json or sqlite)")
+	_ = cmd.RegisterFlagCompletionFunc("to", cobra.FixedCompletions([]string{config.StorageJSON, config.StorageSQLite}, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
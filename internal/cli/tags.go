@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newTagsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tags",
+		Short: "List tags with the number of tasks using them",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			project, err := store.LoadProject(viper.GetString("project"))
+			if err != nil {
+				return err
+			}
+
+			var tags []TagListItem
+			for name, count := range project.TagCounts() {
+				tags = append(tags, TagListItem{Name: name, Count: count})
+			}
+			sort.Slice(tags, func(i, j int) bool {
+				if tags[i].Count != tags[j].Count {
+					return tags[i].Count > tags[j].Count
+				}
+				return tags[i].Name < tags[j].Name
+			})
+
+			return writeTags(cmd.OutOrStdout(), tags)
+		},
+	}
+	return cmd
+}
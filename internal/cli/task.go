@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"phasionary/internal/data"
 	"phasionary/internal/domain"
 )
 
@@ -16,6 +18,10 @@ func newTasksCmd() *cobra.Command {
 		status   string
 		category string
 		priority string
+		section  string
+		overdue  bool
+		ready    bool
+		tag      string
 	)
 
 	cmd := &cobra.Command{
@@ -42,28 +48,44 @@ func newTasksCmd() *cobra.Command {
 					return err
 				}
 			}
+			if section != "" {
+				if err := domain.ValidateSection(section); err != nil {
+					return err
+				}
+			}
 
+			matches, err := store.FindTasks(project.ID, data.TaskFilter{
+				Status:   status,
+				Priority: priority,
+				Category: category,
+				Section:  section,
+				Tag:      tag,
+				Overdue:  overdue,
+				Ready:    ready,
+				Now:      time.Now(),
+			})
+			if err != nil {
+				return err
+			}
 			var tasks []TaskListItem
-			for _, cat := range project.Categories {
-				if category != "" && domain.NormalizeName(cat.Name) != domain.NormalizeName(category) {
-					continue
-				}
-				for _, task := range cat.Tasks {
-					if status != "" && task.Status != status {
-						continue
-					}
-					if priority != "" && task.Priority != priority {
-						continue
-					}
-					tasks = append(tasks, TaskListItem{
-						ID:              task.ID,
-						Title:           task.Title,
-						Status:          task.Status,
-						Priority:        task.Priority,
-						Category:        cat.Name,
-						EstimateMinutes: task.EstimateMinutes,
-					})
-				}
+			for _, match := range matches {
+				task := match.Task
+				tasks = append(tasks, TaskListItem{
+					ID:              task.ID,
+					Title:           task.Title,
+					Status:          task.Status,
+					Priority:        task.Priority,
+					Category:        match.Category,
+					EstimateMinutes: task.EstimateMinutes,
+					Deadline:        task.Deadline,
+					Section:         match.Section,
+					Parent:          match.ParentID,
+					BlockedBy:       task.BlockedBy,
+					Blocked:         match.Blocked,
+					Tags:            task.Tags,
+					Recurrence:      task.Recurrence,
+					Depth:           match.Depth,
+				})
 			}
 
 			return writeTaskList(cmd.OutOrStdout(), tasks)
@@ -73,10 +95,16 @@ func newTasksCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&status, "status", "s", "", "filter by status (todo, in_progress, completed, cancelled)")
 	cmd.Flags().StringVarP(&category, "category", "C", "", "filter by category name")
 	cmd.Flags().StringVar(&priority, "priority", "", "filter by priority (high, medium, low)")
+	cmd.Flags().StringVar(&section, "section", "", "filter by section (current, future, past)")
+	cmd.Flags().BoolVar(&overdue, "overdue", false, "only show open tasks past their deadline")
+	cmd.Flags().BoolVar(&ready, "ready", false, "only show todo tasks that are not blocked")
+	cmd.Flags().StringVar(&tag, "tag", "", "filter by tag")
 
 	_ = cmd.RegisterFlagCompletionFunc("status", completeStatuses)
 	_ = cmd.RegisterFlagCompletionFunc("category", completeCategories)
 	_ = cmd.RegisterFlagCompletionFunc("priority", completePriorities)
+	_ = cmd.RegisterFlagCompletionFunc("section", completeSections)
+	_ = cmd.RegisterFlagCompletionFunc("tag", completeTags)
 
 	return cmd
 }
@@ -94,6 +122,11 @@ func newTaskCmd() *cobra.Command {
 	cmd.AddCommand(newTaskStatusCmd())
 	cmd.AddCommand(newTaskPriorityCmd())
 	cmd.AddCommand(newTaskMoveCmd())
+	cmd.AddCommand(newTaskBlockCmd())
+	cmd.AddCommand(newTaskUnblockCmd())
+	cmd.AddCommand(newTaskStartCmd())
+	cmd.AddCommand(newTaskStopCmd())
+	cmd.AddCommand(newTaskLogCmd())
 
 	return cmd
 }
@@ -115,12 +148,12 @@ func newTaskShowCmd() *cobra.Command {
 				return err
 			}
 
-			task, catName, _, _, err := resolveTask(project, args[0])
+			task, catName, _, err := resolveTask(project, args[0])
 			if err != nil {
 				return fmt.Errorf("task %q not found", args[0])
 			}
 
-			return writeTaskDetail(cmd.OutOrStdout(), *task, catName)
+			return writeTaskDetail(cmd.OutOrStdout(), project, *task, catName)
 		},
 	}
 	return cmd
@@ -131,6 +164,14 @@ func newTaskAddCmd() *cobra.Command {
 		categoryName string
 		priority     string
 		estimate     string
+		due          string
+		section      string
+		description  string
+		notes        string
+		parent       string
+		tags         []string
+		every        string
+		fromDone     bool
 	)
 
 	cmd := &cobra.Command{
@@ -139,8 +180,8 @@ func newTaskAddCmd() *cobra.Command {
 		Short:   "Add a task",
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if strings.TrimSpace(categoryName) == "" {
-				return errors.New("--category is required")
+			if strings.TrimSpace(categoryName) == "" && strings.TrimSpace(parent) == "" {
+				return errors.New("--category or --parent is required")
 			}
 			store, err := storeFromViper()
 			if err != nil {
@@ -171,6 +212,51 @@ func newTaskAddCmd() *cobra.Command {
 				task.EstimateMinutes = minutes
 			}
 
+			if due != "" {
+				deadline, err := domain.ParseDeadline(due, time.Now())
+				if err != nil {
+					return err
+				}
+				task.Deadline = deadline
+			}
+
+			if section != "" {
+				if err := task.SetSection(section); err != nil {
+					return err
+				}
+			}
+
+			task.Description = strings.TrimSpace(description)
+			task.Notes = strings.TrimSpace(notes)
+
+			if err := task.SetTags(tags); err != nil {
+				return err
+			}
+
+			if every != "" {
+				rule, err := domain.ParseRecurrence(every)
+				if err != nil {
+					return err
+				}
+				if rule != nil {
+					rule.FromCompletion = fromDone
+				}
+				task.Recurrence = rule
+			}
+
+			if parent != "" {
+				parentTask, _, _, err := resolveTask(project, parent)
+				if err != nil {
+					return fmt.Errorf("task %q not found", parent)
+				}
+				parentTask.AddSubtask(task)
+				if err := store.SaveProject(&project); err != nil {
+					return err
+				}
+				writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Created subtask: %s (%s) under %s", task.Title, task.ID, parentTask.Title))
+				return nil
+			}
+
 			cat, catIdx, err := resolveCategory(project, categoryName)
 			if err != nil {
 				return fmt.Errorf("category %q not found", categoryName)
@@ -178,7 +264,7 @@ func newTaskAddCmd() *cobra.Command {
 
 			cat.Tasks = append(cat.Tasks, task)
 			project.Categories[catIdx] = *cat
-			if err := store.SaveProject(project); err != nil {
+			if err := store.SaveProject(&project); err != nil {
 				return err
 			}
 
@@ -187,21 +273,38 @@ func newTaskAddCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&categoryName, "category", "C", "", "category name (required)")
+	cmd.Flags().StringVarP(&categoryName, "category", "C", "", "category name (required unless --parent is set)")
+	cmd.Flags().StringVar(&parent, "parent", "", "add as a subtask of this task (id or title)")
 	cmd.Flags().StringVar(&priority, "priority", "", "priority: high|medium|low")
 	cmd.Flags().StringVarP(&estimate, "estimate", "e", "", "time estimate: 30, 2h, 1.5h, 2h30m")
+	cmd.Flags().StringVar(&due, "due", "", "deadline: YYYY-MM-DD, today, tomorrow, fri, +3d, 2w")
+	cmd.Flags().StringVar(&section, "section", "", "section: current|future")
+	cmd.Flags().StringVar(&description, "description", "", "task description")
+	cmd.Flags().StringVar(&notes, "notes", "", "task notes")
+	cmd.Flags().StringSliceVar(&tags, "tags", nil, "comma-separated tags")
+	cmd.Flags().StringVar(&every, "every", "", "repeat on completion: daily, weekly, monthly, 3d, 2w, 1m")
+	cmd.Flags().BoolVar(&fromDone, "from-completion", false, "schedule the next occurrence from the completion date instead of the deadline")
 
 	_ = cmd.RegisterFlagCompletionFunc("category", completeCategories)
 	_ = cmd.RegisterFlagCompletionFunc("priority", completePriorities)
+	_ = cmd.RegisterFlagCompletionFunc("section", completeSections)
+	_ = cmd.RegisterFlagCompletionFunc("parent", completeTasks)
 
 	return cmd
 }
 
 func newTaskEditCmd() *cobra.Command {
 	var (
-		title    string
-		priority string
-		estimate string
+		title       string
+		priority    string
+		estimate    string
+		due         string
+		section     string
+		description string
+		notes       string
+		tags        []string
+		every       string
+		fromDone    bool
 	)
 
 	cmd := &cobra.Command{
@@ -220,7 +323,7 @@ func newTaskEditCmd() *cobra.Command {
 				return err
 			}
 
-			task, _, catIdx, taskIdx, err := resolveTask(project, args[0])
+			task, _, _, err := resolveTask(project, args[0])
 			if err != nil {
 				return fmt.Errorf("task %q not found", args[0])
 			}
@@ -240,9 +343,55 @@ func newTaskEditCmd() *cobra.Command {
 				}
 				task.SetEstimate(minutes)
 			}
+			if cmd.Flags().Changed("due") {
+				deadline, err := domain.ParseDeadline(due, time.Now())
+				if err != nil {
+					return err
+				}
+				if err := task.SetDeadline(deadline); err != nil {
+					return err
+				}
+			}
+			if section != "" {
+				if err := task.SetSection(section); err != nil {
+					return err
+				}
+			}
+			if cmd.Flags().Changed("description") {
+				task.SetDescription(description)
+			}
+			if cmd.Flags().Changed("notes") {
+				task.SetNotes(notes)
+			}
+			if cmd.Flags().Changed("tags") {
+				if err := task.SetTags(tags); err != nil {
+					return err
+				}
+			}
+			if cmd.Flags().Changed("every") || cmd.Flags().Changed("from-completion") {
+				rule := task.Recurrence
+				if cmd.Flags().Changed("every") {
+					rule, err = domain.ParseRecurrence(every)
+					if err != nil {
+						return err
+					}
+				}
+				if rule == nil && cmd.Flags().Changed("from-completion") {
+					return errors.New("--from-completion needs a recurring task (set --every)")
+				}
+				if rule != nil {
+					updated := *rule
+					if cmd.Flags().Changed("from-completion") {
+						updated.FromCompletion = fromDone
+					}
+					rule = &updated
+				}
+				if err := task.SetRecurrence(rule); err != nil {
+					return err
+				}
+			}
 
-			project.Categories[catIdx].Tasks[taskIdx] = *task
-			if err := store.SaveProject(project); err != nil {
+			if err := store.SaveProject(&project); err != nil {
 				return err
 			}
 
@@ -254,8 +403,16 @@ func newTaskEditCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&title, "title", "t", "", "new title")
 	cmd.Flags().StringVar(&priority, "priority", "", "priority: high|medium|low")
 	cmd.Flags().StringVarP(&estimate, "estimate", "e", "", "time estimate: 30, 2h, 1.5h, 2h30m")
+	cmd.Flags().StringVar(&due, "due", "", "deadline: YYYY-MM-DD, today, tomorrow, fri, +3d, 2w (none clears it)")
+	cmd.Flags().StringVar(&section, "section", "", "section: current|future|past (past requires a completed or cancelled task)")
+	cmd.Flags().StringVar(&description, "description", "", "task description (empty string clears it)")
+	cmd.Flags().StringVar(&notes, "notes", "", "task notes (empty string clears them)")
+	cmd.Flags().StringSliceVar(&tags, "tags", nil, "comma-separated tags, replacing the current ones (empty string clears them)")
+	cmd.Flags().StringVar(&every, "every", "", "repeat on completion: daily, weekly, monthly, 3d, 2w, 1m (none stops it)")
+	cmd.Flags().BoolVar(&fromDone, "from-completion", false, "schedule the next occurrence from the completion date instead of the deadline")
 
 	_ = cmd.RegisterFlagCompletionFunc("priority", completePriorities)
+	_ = cmd.RegisterFlagCompletionFunc("section", completeSections)
 
 	return cmd
 }
@@ -279,13 +436,17 @@ func newTaskDeleteCmd() *cobra.Command {
 				return err
 			}
 
-			task, _, catIdx, taskIdx, err := resolveTask(project, args[0])
+			task, _, _, err := resolveTask(project, args[0])
 			if err != nil {
 				return fmt.Errorf("task %q not found", args[0])
 			}
 
 			if !force {
-				fmt.Fprintf(cmd.OutOrStdout(), "Delete task %q? [y/N]: ", task.Title)
+				prompt := fmt.Sprintf("Delete task %q?", task.Title)
+				if _, total := task.SubtaskProgress(); total > 0 {
+					prompt = fmt.Sprintf("Delete task %q and %d subtasks?", task.Title, total)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s [y/N]: ", prompt)
 				var response string
 				if _, err := fmt.Fscanln(cmd.InOrStdin(), &response); err != nil {
 					return nil
@@ -296,14 +457,20 @@ func newTaskDeleteCmd() *cobra.Command {
 				}
 			}
 
-			if err := project.Categories[catIdx].RemoveTask(taskIdx); err != nil {
+			item, ok := data.TrashTask(project, task.ID)
+			if !ok {
+				return fmt.Errorf("task %q not found", args[0])
+			}
+			removed, _ := project.RemoveTaskByID(task.ID)
+			project.PruneDependencies()
+			if err := store.SaveProject(&project); err != nil {
 				return err
 			}
-			if err := store.SaveProject(project); err != nil {
+			if _, err := store.Trash().Add(item); err != nil {
 				return err
 			}
 
-			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Deleted task: %s", task.Title))
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Moved task to trash: %s", removed.Title))
 			return nil
 		},
 	}
@@ -342,21 +509,25 @@ func newTaskStatusCmd() *cobra.Command {
 				return err
 			}
 
-			task, _, catIdx, taskIdx, err := resolveTask(project, selector)
+			task, _, _, err := resolveTask(project, selector)
 			if err != nil {
 				return fmt.Errorf("task %q not found", selector)
 			}
 
-			if err := task.SetStatus(status); err != nil {
+			title := task.Title
+			next, err := project.SetTaskStatus(task.ID, status)
+			if err != nil {
 				return err
 			}
 
-			project.Categories[catIdx].Tasks[taskIdx] = *task
-			if err := store.SaveProject(project); err != nil {
+			if err := store.SaveProject(&project); err != nil {
 				return err
 			}
 
-			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Updated task %s to %s", task.Title, status))
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Updated task %s to %s", title, status))
+			if next != nil {
+				writeSuccess(cmd.OutOrStdout(), "Created next occurrence")
+			}
 			return nil
 		},
 	}
@@ -392,7 +563,7 @@ func newTaskPriorityCmd() *cobra.Command {
 				return err
 			}
 
-			task, _, catIdx, taskIdx, err := resolveTask(project, selector)
+			task, _, _, err := resolveTask(project, selector)
 			if err != nil {
 				return fmt.Errorf("task %q not found", selector)
 			}
@@ -401,8 +572,7 @@ func newTaskPriorityCmd() *cobra.Command {
 				return err
 			}
 
-			project.Categories[catIdx].Tasks[taskIdx] = *task
-			if err := store.SaveProject(project); err != nil {
+			if err := store.SaveProject(&project); err != nil {
 				return err
 			}
 
@@ -414,11 +584,18 @@ func newTaskPriorityCmd() *cobra.Command {
 }
 
 func newTaskMoveCmd() *cobra.Command {
+	var (
+		toProject    string
+		categoryName string
+		copyTask     bool
+	)
+
 	cmd := &cobra.Command{
-		Use:     "move <id-or-title> <category>",
+		Use:     "move <id-or-title> [category]",
 		Aliases: []string{"tm"},
-		Short:   "Move task to different category",
-		Args:    cobra.ExactArgs(2),
+		Short:   "Move or copy a task to another category or project",
+		Long:    "Move a task, with its subtasks, to another category. With --to-project it moves to another project, keeping its IDs and history; the category defaults to one with the same name. With --copy the original stays and the copy gets new IDs.",
+		Args:    cobra.RangeArgs(1, 2),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if len(args) == 0 {
 				return completeTasks(cmd, args, toComplete)
@@ -427,7 +604,12 @@ func newTaskMoveCmd() *cobra.Command {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			selector := args[0]
-			targetCategory := args[1]
+			if len(args) == 2 {
+				if categoryName != "" {
+					return errors.New("give the category either as an argument or with --category")
+				}
+				categoryName = args[1]
+			}
 
 			store, err := storeFromViper()
 			if err != nil {
@@ -438,33 +620,69 @@ func newTaskMoveCmd() *cobra.Command {
 				return err
 			}
 
-			task, _, srcCatIdx, taskIdx, err := resolveTask(project, selector)
+			task, srcCatName, srcCatIdx, err := resolveTask(project, selector)
 			if err != nil {
 				return fmt.Errorf("task %q not found", selector)
 			}
 
-			_, dstCatIdx, err := resolveCategory(project, targetCategory)
+			target := project
+			if toProject != "" {
+				if target, err = store.LoadProject(toProject); err != nil {
+					return err
+				}
+			}
+			if categoryName == "" {
+				if target.ID == project.ID {
+					return errors.New("a category is required")
+				}
+				categoryName = srcCatName
+			}
+			_, dstCatIdx, err := resolveCategory(target, categoryName)
 			if err != nil {
-				return fmt.Errorf("category %q not found", targetCategory)
+				return fmt.Errorf("category %q not found in %s", categoryName, target.Name)
 			}
-
-			if srcCatIdx == dstCatIdx {
-				return fmt.Errorf("task is already in category %q", targetCategory)
+			place := func(p *domain.Project, t domain.Task) error {
+				p.Categories[dstCatIdx].AddTask(t)
+				return nil
 			}
 
-			taskCopy := *task
-			if err := project.Categories[srcCatIdx].RemoveTask(taskIdx); err != nil {
-				return err
-			}
-			project.Categories[dstCatIdx].AddTask(taskCopy)
+			switch {
+			case copyTask:
+				copied, err := data.CopyTask(store, &target, *task, place)
+				if err != nil {
+					return err
+				}
+				writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Copied task %s to %s / %s (%s)", copied.Title, target.Name, target.Categories[dstCatIdx].Name, copied.ID))
+			case target.ID != project.ID:
+				moved, err := data.MoveTask(store, &project, &target, task.ID, place)
+				if err != nil {
+					return err
+				}
+				writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Moved task %s to %s / %s", moved.Title, target.Name, target.Categories[dstCatIdx].Name))
+			default:
+				if srcCatIdx == dstCatIdx {
+					return fmt.Errorf("task is already in category %q", categoryName)
+				}
+				moved, ok := project.RemoveTaskByID(task.ID)
+				if !ok {
+					return fmt.Errorf("task %q not found", selector)
+				}
+				project.Categories[dstCatIdx].AddTask(moved)
 
-			if err := store.SaveProject(project); err != nil {
-				return err
-			}
+				if err := store.SaveProject(&project); err != nil {
+					return err
+				}
 
-			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Moved task %s to %s", task.Title, project.Categories[dstCatIdx].Name))
+				writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Moved task %s to %s", moved.Title, project.Categories[dstCatIdx].Name))
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&toProject, "to-project", "", "move to another project (name or id)")
+	cmd.Flags().StringVarP(&categoryName, "category", "C", "", "target category")
+	cmd.Flags().BoolVar(&copyTask, "copy", false, "copy the task instead of moving it")
+	_ = cmd.RegisterFlagCompletionFunc("to-project", completeProjects)
+
 	return cmd
 }
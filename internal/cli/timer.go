@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"phasionary/internal/data"
+	"phasionary/internal/domain"
+)
+
+func newTaskStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "start <id-or-title>",
+		Aliases:           []string{"tstart"},
+		Short:             "Start tracking time on a task",
+		Long:              "Start tracking time on a task. Any timer running in this or another project is stopped first.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeTasks,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			project, err := store.LoadProject(viper.GetString("project"))
+			if err != nil {
+				return err
+			}
+
+			task, _, _, err := resolveTask(project, args[0])
+			if err != nil {
+				return fmt.Errorf("task %q not found", args[0])
+			}
+			if task.IsTimerRunning() {
+				return fmt.Errorf("timer already running on %s", task.Title)
+			}
+
+			now := time.Now()
+			stopped, err := data.StopTimersExcept(store, project.ID, now)
+			if err != nil {
+				return err
+			}
+			stopped += project.StopTimers(now)
+			if err := task.StartTimer(now); err != nil {
+				return err
+			}
+			if task.Status == domain.StatusTodo {
+				_ = task.SetStatus(domain.StatusInProgress)
+			}
+			if err := store.SaveProject(&project); err != nil {
+				return err
+			}
+
+			if stopped > 0 {
+				writeSuccess(cmd.OutOrStdout(), "Stopped running timer")
+			}
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Started timer on %s", task.Title))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newTaskStopCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "stop [id-or-title]",
+		Aliases:           []string{"tstop"},
+		Short:             "Stop tracking time on a task",
+		Long:              "Stop tracking time on a task. Without an argument, stops whichever timer is running in any project.",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeTasks,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+
+			if len(args) == 0 {
+				projects, err := data.LoadProjects(store)
+				if err != nil {
+					return err
+				}
+				for _, project := range projects {
+					task := project.RunningTimer()
+					if task == nil {
+						continue
+					}
+					title := task.Title
+					_ = task.StopTimer(now)
+					tracked := task.TrackedDuration(now)
+					if err := store.SaveProject(&project); err != nil {
+						return err
+					}
+					writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Stopped timer on %s (%s tracked)", title, domain.FormatDuration(tracked)))
+					return nil
+				}
+				return errors.New("no timer running")
+			}
+
+			project, err := store.LoadProject(viper.GetString("project"))
+			if err != nil {
+				return err
+			}
+			task, _, _, err := resolveTask(project, args[0])
+			if err != nil {
+				return fmt.Errorf("task %q not found", args[0])
+			}
+			if err := task.StopTimer(now); err != nil {
+				return fmt.Errorf("no timer running on %s", task.Title)
+			}
+			if err := store.SaveProject(&project); err != nil {
+				return err
+			}
+
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Stopped timer on %s (%s tracked)", task.Title, domain.FormatDuration(task.TrackedDuration(now))))
+			return nil
+		},
+	}
+	return cmd
+}
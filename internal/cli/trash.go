@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"phasionary/internal/data"
+)
+
+func newTrashCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "List, restore or purge deleted items",
+		Long:  "Deleted tasks, categories and projects are kept in the trash until they are older than trash_retention_days (see `phasionary config`).",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTrashList(cmd)
+		},
+	}
+
+	cmd.AddCommand(newTrashListCmd())
+	cmd.AddCommand(newTrashRestoreCmd())
+	cmd.AddCommand(newTrashPurgeCmd())
+
+	return cmd
+}
+
+func newTrashListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List items in the trash",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTrashList(cmd)
+		},
+	}
+}
+
+func runTrashList(cmd *cobra.Command) error {
+	trash, err := trashFromViper()
+	if err != nil {
+		return err
+	}
+	items, err := trash.List()
+	if err != nil {
+		return err
+	}
+	return writeTrash(cmd.OutOrStdout(), items)
+}
+
+func newTrashRestoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <id>",
+		Short: "Put a deleted item back where it was",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := storeFromViper()
+			if err != nil {
+				return err
+			}
+			trash, err := trashFromViper()
+			if err != nil {
+				return err
+			}
+			item, err := trash.Get(args[0])
+			if err != nil {
+				if errors.Is(err, data.ErrTrashItemNotFound) {
+					return fmt.Errorf("trash item %q not found", args[0])
+				}
+				return err
+			}
+			if err := trash.Restore(store, item); err != nil {
+				if errors.Is(err, data.ErrAlreadyRestored) {
+					return fmt.Errorf("%s %q already exists; removed it from the trash", item.Kind, item.Title())
+				}
+				return err
+			}
+
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Restored %s: %s", item.Kind, item.Title()))
+			return nil
+		},
+	}
+}
+
+func newTrashPurgeCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "purge [id]",
+		Short: "Delete items from the trash for good",
+		Long:  "Delete one item from the trash for good, or empty the whole trash when no ID is given.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			trash, err := trashFromViper()
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 1 {
+				item, err := trash.Get(args[0])
+				if err != nil {
+					if errors.Is(err, data.ErrTrashItemNotFound) {
+						return fmt.Errorf("trash item %q not found", args[0])
+					}
+					return err
+				}
+				if err := trash.Remove(item.ID); err != nil {
+					return err
+				}
+				writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Purged %s: %s", item.Kind, item.Title()))
+				return nil
+			}
+
+			items, err := trash.List()
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				writeSuccess(cmd.OutOrStdout(), "Trash is empty.")
+				return nil
+			}
+			if !force {
+				fmt.Fprintf(cmd.OutOrStdout(), "Permanently delete %d items in the trash? [y/N]: ", len(items))
+				var response string
+				if _, err := fmt.Fscanln(cmd.InOrStdin(), &response); err != nil {
+					return nil
+				}
+				if response != "y" && response != "Y" {
+					fmt.Fprintln(cmd.OutOrStdout(), "Cancelled.")
+					return nil
+				}
+			}
+			purged, err := trash.Purge(0, time.Now())
+			if err != nil {
+				return err
+			}
+
+			writeSuccess(cmd.OutOrStdout(), fmt.Sprintf("Purged %d items", purged))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "skip confirmation prompt")
+
+	return cmd
+}
+
+// trashFromViper opens the trash and drops items older than the configured
+// retention.
+func trashFromViper() (*data.Trash, error) {
+	store, err := storeFromViper()
+	if err != nil {
+		return nil, err
+	}
+	cfgManager, err := configFromViper()
+	if err != nil {
+		return nil, err
+	}
+	trash := store.Trash()
+	if _, err := trash.PurgeExpired(cfgManager.Get().TrashRetention(), time.Now()); err != nil {
+		return nil, err
+	}
+	return trash, nil
+}
@@ -1,29 +1,89 @@
 package config
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 const (
 	EnvDataPath   = "PHASIONARY_DATA_PATH"
 	EnvConfigPath = "PHASIONARY_CONFIG_PATH"
+	EnvKey        = "PHASIONARY_KEY"
+	EnvKeyFile    = "PHASIONARY_KEY_FILE"
+
+	// LocalDirName is the project-local data directory, usually kept at the
+	// root of a repository so the plan is versioned with the code.
+	LocalDirName = ".phasionary"
 
 	StatusDisplayText  = "text"
 	StatusDisplayIcons = "icons"
+
+	StorageJSON   = "json"
+	StorageSQLite = "sqlite"
+
+	DefaultTrashRetentionDays  = 30
+	DefaultBackupEverySaves    = 20
+	DefaultBackupRetentionDays = 30
 )
 
 // Config holds user preferences.
 type Config struct {
 	StatusDisplay  string `json:"status_display,omitempty"`
 	DefaultProject string `json:"default_project,omitempty"`
+	// Storage is where projects are kept: StorageJSON, one file per
+	// project, or StorageSQLite, a single database.
+	Storage string `json:"storage,omitempty"`
+	// TrashRetentionDays is how long deleted items stay in the trash. Zero
+	// keeps them until purged by hand.
+	TrashRetentionDays int `json:"trash_retention_days"`
+	// BackupEverySaves snapshots a project after this many saves, on top of
+	// the daily snapshot. Zero leaves only the daily one.
+	BackupEverySaves int `json:"backup_every_saves"`
+	// BackupRetentionDays is how long project snapshots are kept. Zero keeps
+	// them forever.
+	BackupRetentionDays int `json:"backup_retention_days"`
+	// GitHistory keeps the projects directory in a local git repository,
+	// committing after saves.
+	GitHistory bool `json:"git_history"`
+	// KeyFile holds the passphrase for encrypted projects.
+	KeyFile string `json:"key_file,omitempty"`
 }
 
 // DefaultConfig returns a Config with default values.
 func DefaultConfig() Config {
-	return Config{StatusDisplay: StatusDisplayText}
+	return Config{
+		StatusDisplay:       StatusDisplayText,
+		Storage:             StorageJSON,
+		TrashRetentionDays:  DefaultTrashRetentionDays,
+		BackupEverySaves:    DefaultBackupEverySaves,
+		BackupRetentionDays: DefaultBackupRetentionDays,
+	}
+}
+
+// TrashRetention returns the trash retention as a duration, or zero when
+// trash is never purged automatically.
+func (c Config) TrashRetention() time.Duration {
+	if c.TrashRetentionDays <= 0 {
+		return 0
+	}
+	return time.Duration(c.TrashRetentionDays) * 24 * time.Hour
 }
 
+// BackupRetention returns how long snapshots are kept, or zero to keep them
+// forever.
+func (c Config) BackupRetention() time.Duration {
+	if c.BackupRetentionDays <= 0 {
+		return 0
+	}
+	return time.Duration(c.BackupRetentionDays) * 24 * time.Hour
+}
+
+// ResolveDataDir returns the projects directory path.
+// Priority: input > PHASIONARY_DATA_PATH > a .phasionary directory in the
+// working directory or one of its parents > ~/.local/share/phasionary
 func ResolveDataDir(input string) (string, error) {
 	if input != "" {
 		return filepath.Join(input, "projects"), nil
@@ -31,6 +91,11 @@ func ResolveDataDir(input string) (string, error) {
 	if env := os.Getenv(EnvDataPath); env != "" {
 		return filepath.Join(env, "projects"), nil
 	}
+	if wd, err := os.Getwd(); err == nil {
+		if local, ok := FindLocalDir(wd); ok {
+			return filepath.Join(local, "projects"), nil
+		}
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
@@ -38,6 +103,65 @@ func ResolveDataDir(input string) (string, error) {
 	return filepath.Join(home, ".local", "share", "phasionary", "projects"), nil
 }
 
+// localIgnore keeps per-user state, snapshots and files written while saving
+// out of the repository.
+const localIgnore = `state.json
+index.json
+backups/
+projects/*.lock
+projects/.*.tmp
+projects/*.corrupt-*
+phasionary.db-shm
+phasionary.db-wal
+`
+
+// FindLocalDir walks up from dir, like git looking for .git, and returns the
+// first .phasionary directory it finds.
+func FindLocalDir(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		candidate := filepath.Join(dir, LocalDirName)
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// CreateLocalDir creates a .phasionary directory in dir, with a .gitignore
+// for the files that should not be committed, and returns its projects
+// directory path.
+func CreateLocalDir(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	local := filepath.Join(dir, LocalDirName)
+	if err := os.MkdirAll(filepath.Join(local, "projects"), 0o755); err != nil {
+		return "", err
+	}
+	ignore := filepath.Join(local, ".gitignore")
+	if _, err := os.Stat(ignore); errors.Is(err, fs.ErrNotExist) {
+		if err := os.WriteFile(ignore, []byte(localIgnore), 0o644); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(local, "projects"), nil
+}
+
+// IsLocalDataDir reports whether a projects directory lives in a .phasionary
+// directory.
+func IsLocalDataDir(dataDir string) bool {
+	return filepath.Base(filepath.Dir(dataDir)) == LocalDirName
+}
+
 // ResolveConfigDir returns the config directory path.
 // Priority: input > PHASIONARY_CONFIG_PATH > XDG_CONFIG_HOME > ~/.config/phasionary
 func ResolveConfigDir(input string) (string, error) {
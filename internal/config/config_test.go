@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -43,6 +44,75 @@ func TestResolveConfigDir(t *testing.T) {
 	})
 }
 
+func TestResolveDataDir(t *testing.T) {
+	t.Run("input takes priority", func(t *testing.T) {
+		dir, err := ResolveDataDir("/custom/path")
+		require.NoError(t, err)
+		assert.Equal(t, "/custom/path/projects", dir)
+	})
+
+	t.Run("env var takes second priority", func(t *testing.T) {
+		t.Setenv(EnvDataPath, "/env/path")
+		dir, err := ResolveDataDir("")
+		require.NoError(t, err)
+		assert.Equal(t, "/env/path/projects", dir)
+	})
+
+	t.Run("local directory in a parent takes third priority", func(t *testing.T) {
+		t.Setenv(EnvDataPath, "")
+		root := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(root, LocalDirName), 0o755))
+		nested := filepath.Join(root, "src", "pkg")
+		require.NoError(t, os.MkdirAll(nested, 0o755))
+		t.Chdir(nested)
+
+		dir, err := ResolveDataDir("")
+		require.NoError(t, err)
+		resolved, err := filepath.EvalSymlinks(filepath.Dir(dir))
+		require.NoError(t, err)
+		expected, err := filepath.EvalSymlinks(filepath.Join(root, LocalDirName))
+		require.NoError(t, err)
+		assert.Equal(t, expected, resolved)
+		assert.Equal(t, "projects", filepath.Base(dir))
+		assert.True(t, IsLocalDataDir(dir))
+	})
+
+	t.Run("falls back to ~/.local/share/phasionary", func(t *testing.T) {
+		t.Setenv(EnvDataPath, "")
+		t.Chdir(t.TempDir())
+		home, err := os.UserHomeDir()
+		require.NoError(t, err)
+
+		dir, err := ResolveDataDir("")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(home, ".local", "share", "phasionary", "projects"), dir)
+		assert.False(t, IsLocalDataDir(dir))
+	})
+}
+
+func TestCreateLocalDir(t *testing.T) {
+	root := t.TempDir()
+	dir, err := CreateLocalDir(root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, LocalDirName, "projects"), dir)
+
+	ignore, err := os.ReadFile(filepath.Join(root, LocalDirName, ".gitignore"))
+	require.NoError(t, err)
+	assert.Contains(t, string(ignore), "state.json")
+
+	// An existing .gitignore is left alone.
+	require.NoError(t, os.WriteFile(filepath.Join(root, LocalDirName, ".gitignore"), []byte("custom\n"), 0o644))
+	_, err = CreateLocalDir(root)
+	require.NoError(t, err)
+	ignore, err = os.ReadFile(filepath.Join(root, LocalDirName, ".gitignore"))
+	require.NoError(t, err)
+	assert.Equal(t, "custom\n", string(ignore))
+
+	found, ok := FindLocalDir(filepath.Join(root, LocalDirName, "projects"))
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(root, LocalDirName), found)
+}
+
 func TestResolveConfigPath(t *testing.T) {
 	t.Run("returns config.json in resolved directory", func(t *testing.T) {
 		path, err := ResolveConfigPath("/custom/dir")
@@ -64,10 +134,10 @@ func TestManager(t *testing.T) {
 		_, err = os.Stat(configPath)
 		require.NoError(t, err)
 
-		// Should contain default config with status_display
+		// Should contain default config with status_display, storage, trash and backup settings
 		data, err := os.ReadFile(configPath)
 		require.NoError(t, err)
-		assert.JSONEq(t, `{"status_display":"text"}`, string(data))
+		assert.JSONEq(t, `{"status_display":"text","storage":"json","trash_retention_days":30,"backup_every_saves":20,"backup_retention_days":30,"git_history":false}`, string(data))
 	})
 
 	t.Run("loads existing config", func(t *testing.T) {
@@ -129,5 +199,22 @@ func TestManager(t *testing.T) {
 
 func TestDefaultConfig(t *testing.T) {
 	cfg := DefaultConfig()
-	assert.Equal(t, Config{StatusDisplay: StatusDisplayText}, cfg)
+	assert.Equal(t, Config{
+		StatusDisplay:       StatusDisplayText,
+		Storage:             StorageJSON,
+		TrashRetentionDays:  DefaultTrashRetentionDays,
+		BackupEverySaves:    DefaultBackupEverySaves,
+		BackupRetentionDays: DefaultBackupRetentionDays,
+	}, cfg)
+}
+
+func TestTrashRetention(t *testing.T) {
+	assert.Equal(t, 30*24*time.Hour, Config{TrashRetentionDays: 30}.TrashRetention())
+	assert.Zero(t, Config{TrashRetentionDays: 0}.TrashRetention())
+	assert.Zero(t, Config{TrashRetentionDays: -1}.TrashRetention())
+}
+
+func TestBackupRetention(t *testing.T) {
+	assert.Equal(t, 7*24*time.Hour, Config{BackupRetentionDays: 7}.BackupRetention())
+	assert.Zero(t, Config{BackupRetentionDays: 0}.BackupRetention())
 }
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// Passphrase returns the passphrase for encrypted projects.
+// Priority: PHASIONARY_KEY > PHASIONARY_KEY_FILE > key_file. It returns nil
+// when none is set.
+func Passphrase(cfg Config) ([]byte, error) {
+	if key := os.Getenv(EnvKey); key != "" {
+		return []byte(key), nil
+	}
+	path := os.Getenv(EnvKeyFile)
+	if path == "" {
+		path = cfg.KeyFile
+	}
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key file: %w", err)
+	}
+	key := bytes.TrimRight(data, "\r\n")
+	if len(key) == 0 {
+		return nil, fmt.Errorf("key file %s is empty", path)
+	}
+	return key, nil
+}
+
+// CanPrompt reports whether a passphrase can be asked for on the terminal.
+func CanPrompt() bool {
+	return term.IsTerminal(os.Stdin.Fd())
+}
+
+// PromptPassphrase asks for a passphrase on the terminal without echoing it.
+func PromptPassphrase(prompt string) ([]byte, error) {
+	if !CanPrompt() {
+		return nil, errors.New("cannot ask for a passphrase: not a terminal")
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	return passphrase, nil
+}
@@ -7,6 +7,8 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+
+	"phasionary/internal/fsutil"
 )
 
 // Manager handles loading and saving configuration.
@@ -48,7 +50,7 @@ func (m *Manager) Save() error {
 	if err != nil {
 		return fmt.Errorf("marshaling config: %w", err)
 	}
-	if err := os.WriteFile(m.path, data, 0o644); err != nil {
+	if err := fsutil.WriteFileAtomic(m.path, data, 0o644); err != nil {
 		return fmt.Errorf("writing config %s: %w", m.path, err)
 	}
 	return nil
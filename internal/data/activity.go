@@ -0,0 +1,227 @@
+package data
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"phasionary/internal/domain"
+)
+
+// ActivityEvent is one line of the activity log: a change to a task, when it
+// happened and who made it.
+type ActivityEvent struct {
+	At        string `json:"at"`
+	ProjectID string `json:"project_id"`
+	TaskID    string `json:"task_id"`
+	Task      string `json:"task"`
+	Kind      string `json:"kind"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	Actor     string `json:"actor,omitempty"`
+	Via       string `json:"via,omitempty"`
+}
+
+// Summary describes the change in a few words, for timelines.
+func (e ActivityEvent) Summary() string {
+	from, to := e.From, e.To
+	if from == "" {
+		from = "none"
+	}
+	if to == "" {
+		to = "none"
+	}
+	switch e.Kind {
+	case domain.ActivityCreated:
+		return "created in " + e.To
+	case domain.ActivityDeleted:
+		return "deleted from " + e.From
+	case domain.ActivityArchived:
+		return "archived from " + e.From
+	case domain.ActivityRestored:
+		return "restored to " + e.To
+	case domain.ActivityRenamed:
+		return fmt.Sprintf("renamed from %q", e.From)
+	case domain.ActivityTimer:
+		return "timer " + e.To
+	case domain.ActivityDescription, domain.ActivityNotes, domain.ActivityDependencies:
+		return e.Kind + " edited"
+	}
+	return fmt.Sprintf("%s %s → %s", e.Kind, from, to)
+}
+
+// ActivityLog is an append-only record of task changes, kept as one JSON
+// Lines file per project.
+type ActivityLog struct {
+	Dir string
+}
+
+func NewActivityLog(dir string) *ActivityLog {
+	return &ActivityLog{Dir: dir}
+}
+
+// Append adds events to the end of their project's log.
+func (l *ActivityLog) Append(events []ActivityEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(l.Dir, 0o755); err != nil {
+		return err
+	}
+	byProject := make(map[string][]ActivityEvent)
+	for _, event := range events {
+		byProject[event.ProjectID] = append(byProject[event.ProjectID], event)
+	}
+	for projectID, projectEvents := range byProject {
+		if err := l.appendFile(l.logPath(projectID), projectEvents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *ActivityLog) appendFile(path string, events []ActivityEvent) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Project returns the events of one project, oldest first. Lines that do not
+// parse, such as one cut short by a crash, are skipped.
+func (l *ActivityLog) Project(projectID string) ([]ActivityEvent, error) {
+	f, err := os.Open(l.logPath(projectID))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []ActivityEvent{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	events := make([]ActivityEvent, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event ActivityEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	// Events carried over from another project are appended after the
+	// project's own, so order them by time.
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At < events[j].At
+	})
+	return events, scanner.Err()
+}
+
+// All returns the events of every project, oldest first.
+func (l *ActivityLog) All() ([]ActivityEvent, error) {
+	entries, err := os.ReadDir(l.Dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []ActivityEvent{}, nil
+		}
+		return nil, err
+	}
+	events := make([]ActivityEvent, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".jsonl" {
+			continue
+		}
+		projectEvents, err := l.Project(entry.Name()[:len(entry.Name())-len(".jsonl")])
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, projectEvents...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At < events[j].At
+	})
+	return events, nil
+}
+
+// Task returns the events of one task, oldest first.
+func (l *ActivityLog) Task(projectID, taskID string) ([]ActivityEvent, error) {
+	events, err := l.Project(projectID)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]ActivityEvent, 0)
+	for _, event := range events {
+		if event.TaskID == taskID {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered, nil
+}
+
+func (l *ActivityLog) logPath(projectID string) string {
+	return filepath.Join(l.Dir, projectID+".jsonl")
+}
+
+// ActivityLog returns the log kept alongside the projects directory.
+func (s *Store) ActivityLog() *ActivityLog {
+	return NewActivityLog(filepath.Join(s.Dir, "..", "activity"))
+}
+
+// recordActivity logs what changed between the version of a project on
+// disk and the one just saved. Like snapshots, it never fails a save.
+// Encrypted projects are not logged, since the log is plain text.
+func (s *Store) recordActivity(before, after domain.Project) {
+	if after.Encrypted {
+		return
+	}
+	activity := domain.ProjectActivity(before, after)
+	if len(activity) == 0 {
+		return
+	}
+	at := after.UpdatedAt
+	if at == "" {
+		at = time.Now().UTC().Format(time.RFC3339)
+	}
+	events := make([]ActivityEvent, 0, len(activity))
+	for _, a := range activity {
+		events = append(events, ActivityEvent{
+			At:        at,
+			ProjectID: after.ID,
+			TaskID:    a.TaskID,
+			Task:      a.Title,
+			Kind:      a.Kind,
+			From:      a.From,
+			To:        a.To,
+			Actor:     s.Actor,
+			Via:       s.Via,
+		})
+	}
+	_ = s.ActivityLog().Append(events)
+}
+
+// currentUser names the person running phasionary for the activity log.
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
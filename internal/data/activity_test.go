@@ -0,0 +1,38 @@
+package data
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"phasionary/internal/domain"
+)
+
+func TestSaveProject_RecordsActivity(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	store.Actor = "alice"
+	store.Via = "cli"
+	require.NoError(t, store.Ensure())
+
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	task := &project.Categories[0].Tasks[0]
+	require.NoError(t, task.SetStatus(domain.StatusCompleted))
+	require.NoError(t, store.SaveProject(&project))
+
+	events, err := store.ActivityLog().Task(project.ID, task.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, domain.ActivityCreated, events[0].Kind)
+	assert.Equal(t, domain.ActivityStatus, events[1].Kind)
+	assert.Equal(t, domain.StatusCompleted, events[1].To)
+	assert.Equal(t, "alice", events[1].Actor)
+	assert.Equal(t, "cli", events[1].Via)
+	assert.NotEmpty(t, events[1].At)
+
+	all, err := store.ActivityLog().All()
+	require.NoError(t, err)
+	assert.Greater(t, len(all), 2)
+}
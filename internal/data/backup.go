@@ -0,0 +1,223 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"phasionary/internal/domain"
+	"phasionary/internal/fsutil"
+)
+
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+const snapshotTimeLayout = "20060102T150405Z"
+
+// BackupPolicy controls the rolling snapshots a Store keeps of each project.
+// A snapshot is taken on a save when EverySaves saves happened since the
+// last one, or when the last one is older than Interval. A zero field
+// disables that trigger.
+type BackupPolicy struct {
+	EverySaves int
+	Interval   time.Duration
+	// Retention is how long snapshots are kept. The newest snapshot of a
+	// project is never pruned. Zero keeps every snapshot.
+	Retention time.Duration
+}
+
+func DefaultBackupPolicy() BackupPolicy {
+	return BackupPolicy{
+		EverySaves: 20,
+		Interval:   24 * time.Hour,
+		Retention:  30 * 24 * time.Hour,
+	}
+}
+
+func (p BackupPolicy) enabled() bool {
+	return p.EverySaves > 0 || p.Interval > 0
+}
+
+// Snapshot is a copy of a project file taken at one revision.
+type Snapshot struct {
+	ProjectID string
+	Revision  int
+	TakenAt   time.Time
+	Path      string
+}
+
+// BackupDir is where snapshots are kept, one subdirectory per project.
+func (s *Store) BackupDir() string {
+	return filepath.Join(s.Dir, "..", "backups")
+}
+
+// Snapshots lists the snapshots of a project, newest first.
+func (s *Store) Snapshots(projectID string) ([]Snapshot, error) {
+	dir := filepath.Join(s.BackupDir(), projectID)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []Snapshot{}, nil
+		}
+		return nil, err
+	}
+	snapshots := make([]Snapshot, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		snapshot, ok := parseSnapshotName(entry.Name())
+		if !ok {
+			continue
+		}
+		snapshot.ProjectID = projectID
+		snapshot.Path = filepath.Join(dir, entry.Name())
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		if !snapshots[i].TakenAt.Equal(snapshots[j].TakenAt) {
+			return snapshots[i].TakenAt.After(snapshots[j].TakenAt)
+		}
+		return snapshots[i].Revision > snapshots[j].Revision
+	})
+	return snapshots, nil
+}
+
+// SnapshotAt returns the newest snapshot taken at or before at.
+func (s *Store) SnapshotAt(projectID string, at time.Time) (Snapshot, error) {
+	snapshots, err := s.Snapshots(projectID)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	for _, snapshot := range snapshots {
+		if !snapshot.TakenAt.After(at) {
+			return snapshot, nil
+		}
+	}
+	return Snapshot{}, ErrSnapshotNotFound
+}
+
+func (s *Store) LoadSnapshot(snapshot Snapshot) (domain.Project, error) {
+	return readProjectFile(snapshot.Path, s.Keys)
+}
+
+// TakeSnapshot copies the project as it is now into the backups, whatever
+// the policy says.
+func (s *Store) TakeSnapshot(project domain.Project) (Snapshot, error) {
+	return s.writeSnapshot(project, time.Now())
+}
+
+// RestoreSnapshot makes a snapshot the current version of its project. The
+// version being replaced is snapshotted first so the restore can be undone.
+func (s *Store) RestoreSnapshot(snapshot Snapshot) (domain.Project, error) {
+	project, err := s.LoadSnapshot(snapshot)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	project.Revision = 0
+	current, err := s.backend.load(snapshot.ProjectID)
+	if err == nil {
+		if _, err := s.TakeSnapshot(current); err != nil {
+			return domain.Project{}, err
+		}
+		project.Revision = current.Revision
+	}
+	if err := s.SaveProject(&project); err != nil {
+		return domain.Project{}, err
+	}
+	return project, nil
+}
+
+// snapshotAfterSave applies the backup policy to a project that was just
+// saved. Backups are best effort: failing to write one never fails a save.
+func (s *Store) snapshotAfterSave(project domain.Project) {
+	if !s.Backups.enabled() {
+		return
+	}
+	snapshots, err := s.Snapshots(project.ID)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	if len(snapshots) > 0 {
+		latest := snapshots[0]
+		dueBySaves := s.Backups.EverySaves > 0 && project.Revision-latest.Revision >= s.Backups.EverySaves
+		dueByTime := s.Backups.Interval > 0 && now.Sub(latest.TakenAt) >= s.Backups.Interval
+		if !dueBySaves && !dueByTime {
+			return
+		}
+	}
+	if _, err := s.writeSnapshot(project, now); err != nil {
+		return
+	}
+	_ = s.pruneSnapshots(project.ID, now)
+}
+
+func (s *Store) writeSnapshot(project domain.Project, now time.Time) (Snapshot, error) {
+	dir := filepath.Join(s.BackupDir(), project.ID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Snapshot{}, err
+	}
+	data, err := s.Keys.encode(project)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snapshot := Snapshot{
+		ProjectID: project.ID,
+		Revision:  project.Revision,
+		TakenAt:   now.UTC().Truncate(time.Second),
+	}
+	snapshot.Path = filepath.Join(dir, snapshotName(snapshot))
+	if err := fsutil.WriteFileAtomic(snapshot.Path, data, 0o644); err != nil {
+		return Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+func (s *Store) pruneSnapshots(projectID string, now time.Time) error {
+	if s.Backups.Retention <= 0 {
+		return nil
+	}
+	snapshots, err := s.Snapshots(projectID)
+	if err != nil {
+		return err
+	}
+	for i, snapshot := range snapshots {
+		if i == 0 || now.Sub(snapshot.TakenAt) < s.Backups.Retention {
+			continue
+		}
+		if err := os.Remove(snapshot.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func snapshotName(snapshot Snapshot) string {
+	return fmt.Sprintf("%s-r%d.json", snapshot.TakenAt.UTC().Format(snapshotTimeLayout), snapshot.Revision)
+}
+
+func parseSnapshotName(name string) (Snapshot, bool) {
+	stem, ok := strings.CutSuffix(name, ".json")
+	if !ok {
+		return Snapshot{}, false
+	}
+	stamp, rev, ok := strings.Cut(stem, "-r")
+	if !ok {
+		return Snapshot{}, false
+	}
+	takenAt, err := time.Parse(snapshotTimeLayout, stamp)
+	if err != nil {
+		return Snapshot{}, false
+	}
+	revision, err := strconv.Atoi(rev)
+	if err != nil {
+		return Snapshot{}, false
+	}
+	return Snapshot{Revision: revision, TakenAt: takenAt}, true
+}
@@ -0,0 +1,88 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveProject_SnapshotsEveryNSaves(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	store.Backups = BackupPolicy{EverySaves: 3}
+	require.NoError(t, store.Ensure())
+
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	for i := 0; i < 6; i++ {
+		project.Name = "Work " + string(rune('A'+i))
+		require.NoError(t, store.SaveProject(&project))
+	}
+
+	snapshots, err := store.Snapshots(project.ID)
+	require.NoError(t, err)
+	revisions := make([]int, 0, len(snapshots))
+	for _, s := range snapshots {
+		revisions = append(revisions, s.Revision)
+	}
+	assert.Equal(t, []int{7, 4, 1}, revisions)
+
+	saved, err := store.LoadSnapshot(snapshots[1])
+	require.NoError(t, err)
+	assert.Equal(t, "Work C", saved.Name)
+}
+
+func TestSaveProject_PrunesOldSnapshots(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	store.Backups = BackupPolicy{EverySaves: 1, Retention: time.Hour}
+	require.NoError(t, store.Ensure())
+
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	old, err := store.writeSnapshot(project, time.Now().Add(-2*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, store.SaveProject(&project))
+
+	_, err = os.Stat(old.Path)
+	assert.True(t, os.IsNotExist(err), "expired snapshot should be pruned")
+	snapshots, err := store.Snapshots(project.ID)
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	store.Backups = BackupPolicy{}
+	require.NoError(t, store.Ensure())
+
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	before, err := store.writeSnapshot(project, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	project.Categories = project.Categories[:1]
+	require.NoError(t, store.SaveProject(&project))
+
+	found, err := store.SnapshotAt(project.ID, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, before.Path, found.Path)
+	_, err = store.SnapshotAt(project.ID, time.Now().Add(-2*time.Hour))
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+
+	restored, err := store.RestoreSnapshot(found)
+	require.NoError(t, err)
+	assert.Greater(t, restored.Revision, project.Revision)
+
+	current, err := store.LoadProject(project.ID)
+	require.NoError(t, err)
+	assert.Len(t, current.Categories, 5)
+
+	snapshots, err := store.Snapshots(project.ID)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2, "the replaced version is snapshotted before restoring")
+	replaced, err := store.LoadSnapshot(snapshots[0])
+	require.NoError(t, err)
+	assert.Len(t, replaced.Categories, 1)
+}
@@ -0,0 +1,272 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"phasionary/internal/domain"
+)
+
+// DoctorIssue is a problem found by Store.Doctor. Path is the project file
+// or state file it was found in.
+type DoctorIssue struct {
+	Path    string
+	Project string
+	Message string
+	Fixable bool
+	Fixed   bool
+}
+
+// doctorFile is a project being checked, with the changes to write back
+// when fixing. inDir is set for projects kept as JSON files, whose name
+// must match the project ID.
+type doctorFile struct {
+	path    string
+	inDir   bool
+	project domain.Project
+	changed bool
+}
+
+func (f *doctorFile) named() bool {
+	return f.project.ID != "" && (!f.inDir || filepath.Base(f.path) == f.project.ID+".json")
+}
+
+// Doctor checks every project file and the UI state against the domain
+// invariants. With fix set it also repairs the fixable issues: project files
+// are saved through SaveProject, so a snapshot and the activity log record
+// the change, and stale state entries are pruned.
+func (s *Store) Doctor(state *StateManager, fix bool) ([]DoctorIssue, error) {
+	issues := make([]DoctorIssue, 0)
+	// Projects that exist but cannot be read still count as known, so their
+	// state entries survive until the file is repaired by hand.
+	known := make(map[string]map[string]bool)
+	var files []*doctorFile
+	if _, ok := s.backend.(*jsonBackend); ok {
+		entries, err := os.ReadDir(s.Dir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+			path := filepath.Join(s.Dir, entry.Name())
+			project, err := readProjectFile(path, s.Keys)
+			if err != nil {
+				known[strings.TrimSuffix(entry.Name(), ".json")] = nil
+				issues = append(issues, DoctorIssue{Path: path, Message: fmt.Sprintf("cannot be read: %v", err)})
+				continue
+			}
+			files = append(files, &doctorFile{path: path, inDir: true, project: project})
+		}
+	} else {
+		projects, err := s.backend.list(func(f UnreadableFile) {
+			issues = append(issues, DoctorIssue{Path: f.Path, Message: fmt.Sprintf("cannot be read: %v", f.Err)})
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, project := range projects {
+			files = append(files, &doctorFile{path: s.Location(), project: project})
+		}
+	}
+
+	// Files already named after their project's ID keep it when another
+	// file claims the same ID.
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].named() && !files[j].named()
+	})
+
+	ids := make(map[string]bool)
+	names := make(map[string]string)
+	for _, file := range files {
+		p := &file.project
+		add := func(message string, fixable bool) {
+			issues = append(issues, DoctorIssue{Path: file.path, Project: p.Name, Message: message, Fixable: fixable, Fixed: fix && fixable})
+		}
+		switch {
+		case p.ID == "":
+			add("project has no ID, so it is not listed", true)
+		case ids[p.ID]:
+			add(fmt.Sprintf("project reuses ID %s", p.ID), true)
+		case !file.named():
+			add(fmt.Sprintf("file name does not match project ID %s", p.ID), true)
+		}
+		if p.ID == "" || ids[p.ID] {
+			if fix {
+				id, err := domain.NewID()
+				if err != nil {
+					return nil, err
+				}
+				p.ID = id
+				file.changed = true
+			}
+		}
+		ids[p.ID] = true
+
+		if p.Name != "" {
+			if first, ok := names[domain.NormalizeName(p.Name)]; ok {
+				add(fmt.Sprintf("project has the same name as %q", first), true)
+				if fix {
+					p.Name = uniqueProjectName(files, p.Name)
+					file.changed = true
+				}
+			}
+			names[domain.NormalizeName(p.Name)] = p.Name
+		}
+
+		projectIssues := p.Check()
+		if fix {
+			if _, err := p.Repair(); err != nil {
+				return nil, err
+			}
+		}
+		for _, issue := range projectIssues {
+			add(issue.Message, issue.Fixable)
+			file.changed = file.changed || (fix && issue.Fixable)
+		}
+
+		categories := make(map[string]bool, len(p.Categories))
+		for _, cat := range p.Categories {
+			categories[cat.ID] = true
+		}
+		known[p.ID] = categories
+	}
+
+	if fix {
+		for _, file := range files {
+			if err := s.writeRepaired(file); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if state != nil {
+		stateIssues, err := pruneState(state, known, fix)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, stateIssues...)
+	}
+	return issues, nil
+}
+
+// writeRepaired saves a repaired project, moving it to the file named after
+// its ID when it was stored elsewhere.
+func (s *Store) writeRepaired(file *doctorFile) error {
+	target := filepath.Join(s.Dir, file.project.ID+".json")
+	moved := file.inDir && file.path != target
+	if !file.changed && !moved {
+		return nil
+	}
+	if moved {
+		if _, err := os.Stat(target); err == nil {
+			return fmt.Errorf("cannot move %s: %s already exists", file.path, target)
+		}
+		// The copy at target is new, so SaveProject has no revision to
+		// compare against.
+		file.project.Revision = 0
+	}
+	if err := s.SaveProject(&file.project); err != nil {
+		return err
+	}
+	if moved {
+		return os.Remove(file.path)
+	}
+	return nil
+}
+
+// pruneState reports state entries that refer to projects or categories
+// that no longer exist, removing them when fixing.
+func pruneState(state *StateManager, known map[string]map[string]bool, fix bool) ([]DoctorIssue, error) {
+	var issues []DoctorIssue
+	add := func(message string) {
+		issues = append(issues, DoctorIssue{Path: state.path, Message: message, Fixable: true, Fixed: fix})
+	}
+	projectExists := func(id string) bool {
+		_, ok := known[id]
+		return ok
+	}
+
+	st := &state.state
+	order := make([]string, 0, len(st.ProjectOrder))
+	for _, id := range st.ProjectOrder {
+		if projectExists(id) {
+			order = append(order, id)
+			continue
+		}
+		add(fmt.Sprintf("project order lists missing project %s", id))
+	}
+
+	dirs := make([]string, 0, len(st.DirectoryProjects))
+	for dir := range st.DirectoryProjects {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	staleDirs := make([]string, 0)
+	for _, dir := range dirs {
+		if id := st.DirectoryProjects[dir]; !projectExists(id) {
+			label := dir
+			if label == "" {
+				label = "the default directory"
+			}
+			add(fmt.Sprintf("last project for %s is missing project %s", label, id))
+			staleDirs = append(staleDirs, dir)
+		}
+	}
+
+	projectIDs := make([]string, 0, len(st.FoldedCategories))
+	for id := range st.FoldedCategories {
+		projectIDs = append(projectIDs, id)
+	}
+	sort.Strings(projectIDs)
+	folded := make(map[string][]string, len(st.FoldedCategories))
+	for _, id := range projectIDs {
+		categories, ok := known[id]
+		if !ok {
+			add(fmt.Sprintf("fold state kept for missing project %s", id))
+			continue
+		}
+		var kept []string
+		for _, catID := range st.FoldedCategories[id] {
+			// nil means the project file could not be read.
+			if categories == nil || categories[catID] {
+				kept = append(kept, catID)
+				continue
+			}
+			add(fmt.Sprintf("fold state of project %s lists missing category %s", id, catID))
+		}
+		if len(kept) > 0 {
+			folded[id] = kept
+		}
+	}
+
+	if !fix || len(issues) == 0 {
+		return issues, nil
+	}
+	st.ProjectOrder = order
+	for _, dir := range staleDirs {
+		delete(st.DirectoryProjects, dir)
+	}
+	st.FoldedCategories = folded
+	return issues, state.Save()
+}
+
+// uniqueProjectName appends a number to name until no project being checked
+// uses it.
+func uniqueProjectName(files []*doctorFile, name string) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)", name, n)
+		if !slices.ContainsFunc(files, func(f *doctorFile) bool {
+			return domain.NormalizeName(f.project.Name) == domain.NormalizeName(candidate)
+		}) {
+			return candidate
+		}
+	}
+}
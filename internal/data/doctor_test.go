@@ -0,0 +1,73 @@
+package data
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoctor_FixesProjectsAndState(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	work, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	writeProjectFile(t, store, "stray", `{"schema_version": 1, "id": "", "name": "WORK", "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-01T00:00:00Z", "categories": []}`)
+
+	state := NewStateManager(store.Dir, "")
+	require.NoError(t, state.Load())
+	require.NoError(t, state.SetProjectOrder([]string{"gone", work.ID}))
+	require.NoError(t, state.SetFoldedCategories(work.ID, []string{work.Categories[0].ID, "missing"}))
+
+	issues, err := store.Doctor(state, false)
+	require.NoError(t, err)
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		assert.True(t, issue.Fixable, issue.Message)
+		assert.False(t, issue.Fixed, issue.Message)
+		messages = append(messages, issue.Message)
+	}
+	assert.Equal(t, []string{
+		"project has no ID, so it is not listed",
+		`project has the same name as "Work"`,
+		"project order lists missing project gone",
+		"fold state of project " + work.ID + " lists missing category missing",
+	}, messages)
+
+	issues, err = store.Doctor(state, true)
+	require.NoError(t, err)
+	for _, issue := range issues {
+		assert.True(t, issue.Fixed, issue.Message)
+	}
+
+	projects, err := store.ListProjects()
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+	assert.Equal(t, "Work", projects[0].Name)
+	assert.Equal(t, "WORK (2)", projects[1].Name)
+	assert.NoFileExists(t, filepath.Join(store.Dir, "stray.json"))
+	assert.Equal(t, []string{work.ID}, state.GetProjectOrder())
+	assert.Equal(t, []string{work.Categories[0].ID}, state.GetFoldedCategories(work.ID))
+
+	issues, err = store.Doctor(state, false)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestDoctor_ReportsUnreadableFiles(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	path := writeProjectFile(t, store, "broken", `{"id": `)
+	state := NewStateManager(store.Dir, "")
+	require.NoError(t, state.Load())
+	require.NoError(t, state.SetFoldedCategories("broken", []string{"c1"}))
+
+	issues, err := store.Doctor(state, true)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, path, issues[0].Path)
+	assert.False(t, issues[0].Fixed)
+
+	// The file is left for the user, and so is the state that refers to it.
+	assert.FileExists(t, path)
+	assert.Equal(t, []string{"c1"}, state.GetFoldedCategories("broken"))
+}
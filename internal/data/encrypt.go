@@ -0,0 +1,329 @@
+package data
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"phasionary/internal/domain"
+	"phasionary/internal/fsutil"
+)
+
+var (
+	// ErrLocked is returned when an encrypted project is read without a key.
+	ErrLocked = errors.New("project is encrypted and no key is set")
+	// ErrWrongKey is returned when the key does not open an encrypted file.
+	ErrWrongKey = errors.New("wrong key for encrypted project")
+
+	errEncryptionUnsupported = errors.New("encrypted projects need JSON storage")
+)
+
+// LockedName stands in for the name of an encrypted project listed without
+// a key.
+const LockedName = "(locked)"
+
+const (
+	encryptionScheme = "pbkdf2-sha256+aes-256-gcm"
+	kdfIterations    = 600_000
+	saltSize         = 16
+	keySize          = 32
+)
+
+// envelope is the layout of an encrypted file. The header, holding the
+// project name, is sealed apart from the data so projects can be listed
+// without decrypting their tasks. Both are bound to the ID.
+type envelope struct {
+	Encryption string `json:"encryption"`
+	ID         string `json:"id"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Header     []byte `json:"header,omitempty"`
+	Data       []byte `json:"data"`
+}
+
+type envelopeHeader struct {
+	Name     string `json:"name"`
+	Archived bool   `json:"archived,omitempty"`
+}
+
+// Keyring holds the passphrase used for encrypted projects, from an
+// environment variable, a key file or a prompt. Keys derived from it are
+// cached, and files it encrypts share one salt, so the slow derivation runs
+// once per salt rather than once per file.
+type Keyring struct {
+	// Prompt, when set, is asked for the passphrase the first time one is
+	// needed and none was set.
+	Prompt func() ([]byte, error)
+
+	mu         sync.Mutex
+	passphrase []byte
+	prompted   bool
+	keys       map[string][]byte
+	salt       []byte
+}
+
+func NewKeyring() *Keyring {
+	return &Keyring{keys: make(map[string][]byte)}
+}
+
+// SetPassphrase sets the passphrase, dropping keys derived from an earlier
+// one.
+func (k *Keyring) SetPassphrase(passphrase []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.passphrase = bytes.TrimRight(passphrase, "\r\n")
+	k.keys = make(map[string][]byte)
+	k.salt = nil
+}
+
+// HasPassphrase reports whether a passphrase was set.
+func (k *Keyring) HasPassphrase() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return len(k.passphrase) > 0
+}
+
+// unlock prompts for the passphrase if none was set and it has not asked
+// yet, and reports whether that gave it one.
+func (k *Keyring) unlock() (bool, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.unlockLocked()
+}
+
+func (k *Keyring) unlockLocked() (bool, error) {
+	if len(k.passphrase) > 0 || k.Prompt == nil || k.prompted {
+		return false, nil
+	}
+	k.prompted = true
+	passphrase, err := k.Prompt()
+	if err != nil {
+		return false, err
+	}
+	k.passphrase = bytes.TrimRight(passphrase, "\r\n")
+	return len(k.passphrase) > 0, nil
+}
+
+// key derives the key for salt. With prompt set it asks for a missing
+// passphrase first.
+func (k *Keyring) key(salt []byte, iterations int, prompt bool) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if prompt {
+		if _, err := k.unlockLocked(); err != nil {
+			return nil, err
+		}
+	}
+	if len(k.passphrase) == 0 {
+		return nil, ErrLocked
+	}
+	id := fmt.Sprintf("%d:%x", iterations, salt)
+	if key, ok := k.keys[id]; ok {
+		return key, nil
+	}
+	key, err := pbkdf2.Key(sha256.New, string(k.passphrase), salt, iterations, keySize)
+	if err != nil {
+		return nil, err
+	}
+	k.keys[id] = key
+	return key, nil
+}
+
+// sealingSalt returns the salt new files are encrypted with.
+func (k *Keyring) sealingSalt() ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.salt == nil {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		k.salt = salt
+	}
+	return k.salt, nil
+}
+
+// seal encrypts header and data into an envelope for the given ID.
+func (k *Keyring) seal(id string, header, data []byte) ([]byte, error) {
+	salt, err := k.sealingSalt()
+	if err != nil {
+		return nil, err
+	}
+	key, err := k.key(salt, kdfIterations, true)
+	if err != nil {
+		return nil, err
+	}
+	env := envelope{Encryption: encryptionScheme, ID: id, Iterations: kdfIterations, Salt: salt}
+	if header != nil {
+		if env.Header, err = sealPart(key, id, header); err != nil {
+			return nil, err
+		}
+	}
+	if env.Data, err = sealPart(key, id, data); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(env, "", "  ")
+}
+
+// open decrypts one part, the header or the data, of an envelope. With
+// prompt set it may ask for the passphrase.
+func (k *Keyring) open(env envelope, part []byte, prompt bool) ([]byte, error) {
+	if env.Encryption != encryptionScheme {
+		return nil, fmt.Errorf("unknown encryption %q", env.Encryption)
+	}
+	key, err := k.key(env.Salt, env.Iterations, prompt)
+	if err != nil {
+		return nil, err
+	}
+	return openPart(key, env.ID, part)
+}
+
+func sealPart(key []byte, id string, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(id)), nil
+}
+
+func openPart(key []byte, id string, sealed []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrWrongKey
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// readEnvelope returns the envelope of an encrypted file, and false for
+// anything else.
+func readEnvelope(data []byte) (envelope, bool) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Encryption == "" {
+		return envelope{}, false
+	}
+	return env, true
+}
+
+// decode parses a project file, decrypting it when it is encrypted.
+func (k *Keyring) decode(data []byte) (domain.Project, error) {
+	env, ok := readEnvelope(data)
+	if !ok {
+		return decodeProject(data)
+	}
+	plaintext, err := k.open(env, env.Data, true)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	project, err := decodeProject(plaintext)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	project.Encrypted = true
+	return project, nil
+}
+
+// decodeHeader parses a project file for listing. An encrypted project
+// comes back sealed, with only its ID and name, or LockedName when there is
+// no key to read the name with or the key is wrong. Listing never prompts
+// for the passphrase.
+func (k *Keyring) decodeHeader(data []byte) (domain.Project, error) {
+	env, ok := readEnvelope(data)
+	if !ok {
+		return decodeProject(data)
+	}
+	project := domain.Project{ID: env.ID, Name: LockedName, Encrypted: true, Sealed: true}
+	plaintext, err := k.open(env, env.Header, false)
+	switch {
+	case errors.Is(err, ErrLocked), errors.Is(err, ErrWrongKey):
+		return project, nil
+	case err != nil:
+		return domain.Project{}, err
+	}
+	var header envelopeHeader
+	if err := json.Unmarshal(plaintext, &header); err != nil {
+		return domain.Project{}, err
+	}
+	project.Name = header.Name
+	project.Archived = header.Archived
+	return project, nil
+}
+
+// encode serializes a project for a file, encrypting it when the project
+// is marked Encrypted.
+func (k *Keyring) encode(project domain.Project) ([]byte, error) {
+	data, err := json.MarshalIndent(project, "", "  ")
+	if err != nil || !project.Encrypted {
+		return data, err
+	}
+	header, err := json.Marshal(envelopeHeader{Name: project.Name, Archived: project.Archived})
+	if err != nil {
+		return nil, err
+	}
+	return k.seal(project.ID, header, data)
+}
+
+// SetEncrypted encrypts or decrypts a project, along with its snapshots.
+// Entries already in the activity log, the trash and the git history are
+// left as they were.
+func (s *Store) SetEncrypted(projectID string, encrypted bool) (domain.Project, error) {
+	if _, ok := s.backend.(*jsonBackend); !ok {
+		return domain.Project{}, errEncryptionUnsupported
+	}
+	project, err := s.LoadProject(projectID)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	if project.Encrypted == encrypted {
+		return project, nil
+	}
+	project.Encrypted = encrypted
+	if err := s.SaveProject(&project); err != nil {
+		return domain.Project{}, err
+	}
+	snapshots, err := s.Snapshots(project.ID)
+	if err != nil {
+		return project, err
+	}
+	for _, snapshot := range snapshots {
+		old, err := s.LoadSnapshot(snapshot)
+		if err != nil {
+			continue
+		}
+		old.Encrypted = encrypted
+		data, err := s.Keys.encode(old)
+		if err != nil {
+			return project, err
+		}
+		if err := fsutil.WriteFileAtomic(snapshot.Path, data, 0o644); err != nil {
+			return project, err
+		}
+	}
+	return project, nil
+}
@@ -0,0 +1,131 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetEncrypted_SealsProjectAndSnapshots(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "projects")
+	store := NewStore(dir)
+	store.Keys.SetPassphrase([]byte("correct horse"))
+	require.NoError(t, store.Ensure())
+
+	project, err := store.CreateProject("Client Audit")
+	require.NoError(t, err)
+	project, err = store.SetEncrypted(project.ID, true)
+	require.NoError(t, err)
+	assert.True(t, project.Encrypted)
+
+	raw, err := os.ReadFile(filepath.Join(dir, project.ID+".json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "Client Audit")
+	assert.NotContains(t, string(raw), "Build the main dashboard")
+
+	snapshots, err := store.Snapshots(project.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, snapshots)
+	for _, snapshot := range snapshots {
+		raw, err := os.ReadFile(snapshot.Path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), "Build the main dashboard")
+	}
+
+	// Listing decrypts the name only; loading returns everything.
+	projects, err := store.ListProjects()
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "Client Audit", projects[0].Name)
+	assert.True(t, projects[0].Sealed)
+	assert.Empty(t, projects[0].Categories)
+
+	loaded, err := store.LoadProject("client audit")
+	require.NoError(t, err)
+	assert.False(t, loaded.Sealed)
+	assert.True(t, loaded.Encrypted)
+	assert.NotEmpty(t, loaded.Categories)
+
+	// Saves keep it encrypted.
+	loaded.Name = "Client Audit 2"
+	require.NoError(t, store.SaveProject(&loaded))
+	raw, err = os.ReadFile(filepath.Join(dir, project.ID+".json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "Client Audit 2")
+
+	decrypted, err := store.SetEncrypted(project.ID, false)
+	require.NoError(t, err)
+	assert.False(t, decrypted.Encrypted)
+	raw, err = os.ReadFile(filepath.Join(dir, project.ID+".json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "Client Audit 2")
+}
+
+func TestEncryptedProject_WithoutKey(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "projects")
+	store := NewStore(dir)
+	store.Keys.SetPassphrase([]byte("correct horse"))
+	require.NoError(t, store.Ensure())
+	project, err := store.CreateProject("Secret")
+	require.NoError(t, err)
+	_, err = store.SetEncrypted(project.ID, true)
+	require.NoError(t, err)
+
+	locked := NewStore(dir)
+	projects, err := locked.ListProjects()
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, LockedName, projects[0].Name)
+	_, err = locked.LoadProject(project.ID)
+	assert.ErrorIs(t, err, ErrLocked)
+	_, err = locked.LoadProject("Secret")
+	assert.ErrorIs(t, err, ErrProjectNotFound)
+
+	// A sealed project cannot be saved over the encrypted file.
+	assert.Error(t, locked.SaveProject(&projects[0]))
+
+	// The name is found once the prompt unlocks the store.
+	prompted := NewStore(dir)
+	prompted.Keys.Prompt = func() ([]byte, error) { return []byte("correct horse"), nil }
+	loaded, err := prompted.LoadProject("Secret")
+	require.NoError(t, err)
+	assert.Equal(t, project.ID, loaded.ID)
+
+	wrong := NewStore(dir)
+	wrong.Keys.SetPassphrase([]byte("battery staple"))
+	_, err = wrong.LoadProject(project.ID)
+	assert.ErrorIs(t, err, ErrWrongKey)
+}
+
+func TestTrash_EncryptsItemsFromEncryptedProjects(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "projects")
+	store := NewStore(dir)
+	store.Keys.SetPassphrase([]byte("correct horse"))
+	require.NoError(t, store.Ensure())
+	project, err := store.CreateProject("Secret")
+	require.NoError(t, err)
+	project, err = store.SetEncrypted(project.ID, true)
+	require.NoError(t, err)
+
+	taskID := project.Categories[0].Tasks[0].ID
+	item, ok := TrashTask(project, taskID)
+	require.True(t, ok)
+	item, err = store.Trash().Add(item)
+	require.NoError(t, err)
+
+	raw, err := os.ReadFile(store.Trash().itemPath(item.ID))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), item.Task.Title)
+
+	items, err := store.Trash().List()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, item.Task.Title, items[0].Title())
+
+	items, err = NewStore(dir).Trash().List()
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
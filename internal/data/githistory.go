@@ -0,0 +1,391 @@
+package data
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"phasionary/internal/domain"
+)
+
+// ErrHistoryDisabled is returned by history operations on a store that does
+// not keep a git history.
+var ErrHistoryDisabled = errors.New("git history is not enabled")
+
+// historyIgnore keeps lock files, half-written saves and quarantined files
+// out of the repository.
+const historyIgnore = "*.lock\n.*.tmp\n*.corrupt-*\n"
+
+// GitHistory commits the projects directory to a local git repository after
+// each save, giving an audit trail of every change. Nothing is ever pushed.
+type GitHistory struct {
+	Dir string
+	// Delay batches saves made within this long of each other into one
+	// commit. Zero commits after every save.
+	Delay time.Duration
+
+	mu      sync.Mutex
+	pending []string
+	timer   *time.Timer
+	held    int
+	env     []string
+}
+
+func NewGitHistory(dir string) *GitHistory {
+	return &GitHistory{Dir: dir}
+}
+
+// GitCommit is one entry of the history.
+type GitCommit struct {
+	Hash    string
+	Short   string
+	At      time.Time
+	Author  string
+	Subject string
+}
+
+// Init makes Dir a git repository if it is not one yet and commits the
+// projects already in it.
+func (h *GitHistory) Init() error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return errors.New("git history needs git to be installed")
+	}
+	if err := os.MkdirAll(h.Dir, 0o755); err != nil {
+		return err
+	}
+	// Commits fall back to a local identity when the user has not set one.
+	if out, err := h.git("config", "user.email"); err != nil || strings.TrimSpace(out) == "" {
+		name := currentUser()
+		if name == "" {
+			name = "phasionary"
+		}
+		email := name + "@phasionary.local"
+		h.env = []string{
+			"GIT_AUTHOR_NAME=" + name, "GIT_AUTHOR_EMAIL=" + email,
+			"GIT_COMMITTER_NAME=" + name, "GIT_COMMITTER_EMAIL=" + email,
+		}
+	}
+	if _, err := os.Stat(filepath.Join(h.Dir, ".git")); err == nil {
+		return nil
+	}
+	if _, err := h.git("init", "-q"); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(h.Dir, ".gitignore"), []byte(historyIgnore), 0o644); err != nil {
+		return err
+	}
+	return h.commit("Start phasionary history", nil)
+}
+
+// Record notes a change made by a save. The working tree is committed right
+// away, or once saves have stopped for Delay.
+func (h *GitHistory) Record(message string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pending = append(h.pending, message)
+	if h.held > 0 {
+		return
+	}
+	if h.Delay <= 0 {
+		_ = h.flushLocked()
+		return
+	}
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+	h.timer = time.AfterFunc(h.Delay, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		_ = h.flushLocked()
+	})
+}
+
+// Hold stops Record from committing until the returned function is called,
+// which commits everything recorded meanwhile under subject.
+func (h *GitHistory) Hold() func(subject string) error {
+	h.mu.Lock()
+	h.held++
+	h.mu.Unlock()
+	return func(subject string) error {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.held--
+		body := h.pending
+		h.pending = nil
+		return h.commit(subject, body)
+	}
+}
+
+// Flush commits any batched changes now.
+func (h *GitHistory) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.flushLocked()
+}
+
+func (h *GitHistory) flushLocked() error {
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+	if len(h.pending) == 0 {
+		return nil
+	}
+	messages := h.pending
+	h.pending = nil
+	subject := messages[0]
+	if len(messages) > 1 {
+		subject = fmt.Sprintf("%s (+%d more)", subject, len(messages)-1)
+		return h.commit(subject, messages)
+	}
+	return h.commit(subject, nil)
+}
+
+// commit stages the whole directory and commits it if anything changed.
+// Changes a failed commit left behind are picked up by the next one.
+func (h *GitHistory) commit(subject string, body []string) error {
+	if _, err := h.git("add", "-A", "."); err != nil {
+		return err
+	}
+	if _, err := h.git("diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+	args := []string{"commit", "-q", "--no-verify", "-m", subject}
+	if len(body) > 0 {
+		args = append(args, "-m", strings.Join(body, "\n"))
+	}
+	_, err := h.git(args...)
+	return err
+}
+
+// Log returns the newest commits first, at most limit of them when limit is
+// positive. A non-empty file limits the log to commits touching it.
+func (h *GitHistory) Log(file string, limit int) ([]GitCommit, error) {
+	var args []string
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("-n%d", limit))
+	}
+	if file != "" {
+		args = append(args, "--", file)
+	}
+	return h.log(args...)
+}
+
+// Commit resolves rev to a commit.
+func (h *GitHistory) Commit(rev string) (GitCommit, error) {
+	if err := checkRevision(rev); err != nil {
+		return GitCommit{}, err
+	}
+	hash, err := h.git("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return GitCommit{}, fmt.Errorf("unknown revision %q", rev)
+	}
+	commits, err := h.log("-n1", strings.TrimSpace(hash))
+	if err != nil {
+		return GitCommit{}, err
+	}
+	if len(commits) == 0 {
+		return GitCommit{}, fmt.Errorf("unknown revision %q", rev)
+	}
+	return commits[0], nil
+}
+
+func (h *GitHistory) log(args ...string) ([]GitCommit, error) {
+	out, err := h.git(append([]string{"log", "--format=%H%x1f%h%x1f%aI%x1f%an%x1f%s%x1e"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]GitCommit, 0)
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.Split(strings.TrimSpace(record), "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+		at, _ := time.Parse(time.RFC3339, fields[2])
+		commits = append(commits, GitCommit{Hash: fields[0], Short: fields[1], At: at, Author: fields[3], Subject: fields[4]})
+	}
+	return commits, nil
+}
+
+// changedFiles lists the files a commit changed compared to its parent.
+func (h *GitHistory) changedFiles(hash string) ([]string, error) {
+	out, err := h.git("diff-tree", "--no-commit-id", "--name-only", "-r", hash+"^", hash)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// fileAt returns a file as it was at rev, and false if it did not exist.
+func (h *GitHistory) fileAt(rev, file string) ([]byte, bool, error) {
+	if err := checkRevision(rev); err != nil {
+		return nil, false, err
+	}
+	if _, err := h.git("cat-file", "-e", rev+":"+file); err != nil {
+		return nil, false, nil
+	}
+	out, err := h.git("show", rev+":"+file)
+	if err != nil {
+		return nil, false, err
+	}
+	return []byte(out), true, nil
+}
+
+// checkRevision rejects revisions git would read as an option.
+func checkRevision(rev string) error {
+	if rev == "" || strings.HasPrefix(rev, "-") {
+		return fmt.Errorf("invalid revision %q", rev)
+	}
+	return nil
+}
+
+func (h *GitHistory) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = h.Dir
+	cmd.Env = append(os.Environ(), h.env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// recordHistory describes a save for the git history.
+func (s *Store) recordHistory(before, after domain.Project) {
+	if s.History == nil {
+		return
+	}
+	s.History.Record(historyMessage(before, after))
+}
+
+// historyMessage summarizes a save in one line, such as
+// `Work: task "Write docs" -> completed`.
+func historyMessage(before, after domain.Project) string {
+	// The messages of encrypted projects would give away what the file
+	// hides.
+	if after.Encrypted {
+		return fmt.Sprintf("Encrypted project %s updated", after.ID)
+	}
+	if before.ID == "" {
+		return fmt.Sprintf("%s: project created", after.Name)
+	}
+	activity := domain.ProjectActivity(before, after)
+	if len(activity) == 0 {
+		return fmt.Sprintf("%s: project updated", after.Name)
+	}
+	a := activity[0]
+	line := fmt.Sprintf("%s: task %q", after.Name, a.Title)
+	if a.Kind == domain.ActivityStatus {
+		line += " -> " + a.To
+	} else {
+		line += " " + ActivityEvent{Kind: a.Kind, From: a.From, To: a.To}.Summary()
+	}
+	if len(activity) > 1 {
+		line += fmt.Sprintf(" and %d more changes", len(activity)-1)
+	}
+	return line
+}
+
+// RevertCommit undoes the changes a commit made to project files by merging
+// the version before the commit into the current one. Later changes to other
+// tasks are kept, but a task the commit touched goes back to its earlier
+// version as a whole. A project the commit created goes to the trash and one
+// it deleted is restored. It returns the names of the projects it changed.
+func (s *Store) RevertCommit(rev string) ([]string, error) {
+	if s.History == nil {
+		return nil, ErrHistoryDisabled
+	}
+	commit, err := s.History.Commit(rev)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.History.git("rev-parse", "--verify", "--quiet", commit.Hash+"^"); err != nil {
+		return nil, errors.New("cannot revert the first commit of the history")
+	}
+	files, err := s.History.changedFiles(commit.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	release := s.History.Hold()
+	reverted, err := s.revertFiles(commit.Hash, files)
+	if commitErr := release(fmt.Sprintf("Revert %s: %s", commit.Short, commit.Subject)); err == nil {
+		err = commitErr
+	}
+	return reverted, err
+}
+
+func (s *Store) revertFiles(hash string, files []string) ([]string, error) {
+	reverted := make([]string, 0)
+	for _, file := range files {
+		if filepath.Ext(file) != ".json" || strings.Contains(file, "/") {
+			continue
+		}
+		before, hadBefore, err := s.History.fileAt(hash+"^", file)
+		if err != nil {
+			return reverted, err
+		}
+		after, hadAfter, err := s.History.fileAt(hash, file)
+		if err != nil {
+			return reverted, err
+		}
+		current, err := readProjectFile(filepath.Join(s.Dir, file), s.Keys)
+		exists := err == nil
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return reverted, fmt.Errorf("%s: %w", file, err)
+		}
+
+		switch {
+		case !hadBefore:
+			if !exists {
+				continue
+			}
+			if err := s.DeleteProject(current.ID); err != nil {
+				return reverted, err
+			}
+			reverted = append(reverted, current.Name)
+		case !hadAfter:
+			if exists {
+				continue
+			}
+			project, err := s.Keys.decode(before)
+			if err != nil {
+				return reverted, fmt.Errorf("%s: %w", file, err)
+			}
+			project.Revision = 0
+			if err := s.SaveProject(&project); err != nil {
+				return reverted, err
+			}
+			reverted = append(reverted, project.Name)
+		default:
+			if !exists {
+				continue
+			}
+			old, err := s.Keys.decode(before)
+			if err != nil {
+				return reverted, fmt.Errorf("%s: %w", file, err)
+			}
+			committed, err := s.Keys.decode(after)
+			if err != nil {
+				return reverted, fmt.Errorf("%s: %w", file, err)
+			}
+			merged := domain.MergeProjects(committed, old, current)
+			if err := s.SaveProject(&merged); err != nil {
+				return reverted, err
+			}
+			reverted = append(reverted, merged.Name)
+		}
+	}
+	return reverted, nil
+}
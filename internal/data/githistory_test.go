@@ -0,0 +1,101 @@
+package data
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"phasionary/internal/domain"
+)
+
+func newHistoryTestStore(t *testing.T) *Store {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	store.History = NewGitHistory(store.Dir)
+	require.NoError(t, store.History.Init())
+	return store
+}
+
+func historySubjects(t *testing.T, store *Store) []string {
+	t.Helper()
+	commits, err := store.History.Log("", 0)
+	require.NoError(t, err)
+	subjects := make([]string, 0, len(commits))
+	for _, c := range commits {
+		subjects = append(subjects, c.Subject)
+	}
+	return subjects
+}
+
+func TestGitHistory_CommitsEverySave(t *testing.T) {
+	store := newHistoryTestStore(t)
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	task := &project.Categories[0].Tasks[0]
+	require.NoError(t, task.SetStatus(domain.StatusCompleted))
+	require.NoError(t, store.SaveProject(&project))
+
+	assert.Equal(t, []string{
+		`Work: task "` + task.Title + `" -> completed`,
+		"Work: project created",
+		"Start phasionary history",
+	}, historySubjects(t, store))
+}
+
+func TestGitHistory_BatchesSaves(t *testing.T) {
+	store := newHistoryTestStore(t)
+	store.History.Delay = time.Hour
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	project.Name = "Work renamed"
+	require.NoError(t, store.SaveProject(&project))
+	assert.Equal(t, []string{"Start phasionary history"}, historySubjects(t, store))
+
+	require.NoError(t, store.History.Flush())
+	assert.Equal(t, []string{
+		"Work: project created (+1 more)",
+		"Start phasionary history",
+	}, historySubjects(t, store))
+}
+
+func TestRevertCommit(t *testing.T) {
+	store := newHistoryTestStore(t)
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	first := project.Categories[0].Tasks[0]
+	require.NoError(t, project.Categories[0].Tasks[0].SetStatus(domain.StatusCompleted))
+	require.NoError(t, store.SaveProject(&project))
+	project.Categories[1].Tasks[0].Title = "Renamed later"
+	require.NoError(t, store.SaveProject(&project))
+
+	commits, err := store.History.Log("", 0)
+	require.NoError(t, err)
+	names, err := store.RevertCommit(commits[1].Short)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Work"}, names)
+
+	reverted, err := store.LoadProject(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.Status, reverted.Categories[0].Tasks[0].Status)
+	assert.Equal(t, "Renamed later", reverted.Categories[1].Tasks[0].Title)
+	assert.Equal(t, "Revert "+commits[1].Short+": "+commits[1].Subject, historySubjects(t, store)[0])
+
+	// Reverting the commit that created the project moves it to the trash.
+	_, err = store.RevertCommit(commits[2].Hash)
+	require.NoError(t, err)
+	_, err = store.LoadProject(project.ID)
+	assert.ErrorIs(t, err, ErrProjectNotFound)
+
+	_, err = store.RevertCommit(commits[3].Hash)
+	assert.Error(t, err)
+
+	_, err = store.RevertCommit("--output=" + filepath.Join(t.TempDir(), "out"))
+	assert.ErrorContains(t, err, "invalid revision")
+}
@@ -0,0 +1,209 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"phasionary/internal/domain"
+	"phasionary/internal/fsutil"
+)
+
+// IndexFile is the name of the project index kept next to the projects
+// directory.
+const IndexFile = "index.json"
+
+const indexVersion = 2
+
+// ProjectSummary is what listings show about a project. The JSON backend
+// keeps summaries in an index so listing does not parse every project file.
+// Counts are zero for encrypted projects.
+type ProjectSummary struct {
+	ID         string `json:"id"`
+	Name       string `json:"name,omitempty"`
+	CreatedAt  string `json:"created_at,omitempty"`
+	UpdatedAt  string `json:"updated_at,omitempty"`
+	Categories int    `json:"categories"`
+	Tasks      int    `json:"tasks"`
+	Completed  int    `json:"completed"`
+	Encrypted  bool   `json:"encrypted,omitempty"`
+	Archived   bool   `json:"archived,omitempty"`
+}
+
+func summarize(project domain.Project) ProjectSummary {
+	summary := ProjectSummary{
+		ID:         project.ID,
+		Name:       project.Name,
+		CreatedAt:  project.CreatedAt,
+		UpdatedAt:  project.UpdatedAt,
+		Categories: len(project.Categories),
+		Encrypted:  project.Encrypted,
+		Archived:   project.Archived,
+	}
+	for _, cat := range project.Categories {
+		domain.WalkTasks(cat.Tasks, func(task *domain.Task, _ int) {
+			summary.Tasks++
+			if task.Status == domain.StatusCompleted {
+				summary.Completed++
+			}
+		})
+	}
+	return summary
+}
+
+// summarizer is implemented by backends that can list summaries without
+// loading every project in full.
+type summarizer interface {
+	summaries(report func(UnreadableFile)) ([]ProjectSummary, error)
+}
+
+// ListSummaries returns a summary of every readable project, sorted by name
+// like ListProjects.
+func (s *Store) ListSummaries() ([]ProjectSummary, error) {
+	var summaries []ProjectSummary
+	if summarizer, ok := s.backend.(summarizer); ok {
+		var err error
+		if summaries, err = summarizer.summaries(s.reportUnreadable); err != nil {
+			return nil, err
+		}
+	} else {
+		projects, err := s.backend.list(s.reportUnreadable)
+		if err != nil {
+			return nil, err
+		}
+		summaries = make([]ProjectSummary, 0, len(projects))
+		for _, project := range projects {
+			summaries = append(summaries, summarize(project))
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return strings.ToLower(summaries[i].Name) < strings.ToLower(summaries[j].Name)
+	})
+	return summaries, nil
+}
+
+// projectIndex maps project file names to what was read from them. An entry
+// is used while the file keeps the size and modification time it had.
+type projectIndex struct {
+	Version int                   `json:"version"`
+	Files   map[string]indexEntry `json:"files"`
+}
+
+type indexEntry struct {
+	Size    int64          `json:"size"`
+	ModTime int64          `json:"mod_time"`
+	Summary ProjectSummary `json:"summary"`
+	// Sealed holds the encrypted header of an encrypted project, so its
+	// name is never stored in the clear.
+	Sealed *envelope `json:"sealed,omitempty"`
+}
+
+func (b *jsonBackend) indexPath() string {
+	return filepath.Join(b.dir, "..", IndexFile)
+}
+
+func (b *jsonBackend) summaries(report func(UnreadableFile)) ([]ProjectSummary, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []ProjectSummary{}, nil
+		}
+		return nil, err
+	}
+	index := b.readIndex()
+	fresh := make(map[string]indexEntry)
+	changed := false
+	summaries := make([]ProjectSummary, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		cached, ok := index.Files[entry.Name()]
+		if !ok || cached.Size != info.Size() || cached.ModTime != info.ModTime().UnixNano() {
+			path := filepath.Join(b.dir, entry.Name())
+			if cached, err = b.indexFile(path, info); err != nil {
+				report(quarantineProjectFile(path, err))
+				changed = true
+				continue
+			}
+			changed = true
+		}
+		fresh[entry.Name()] = cached
+		if cached.Summary.ID == "" {
+			continue
+		}
+		summaries = append(summaries, b.unseal(cached))
+	}
+	if changed || len(fresh) != len(index.Files) {
+		b.writeIndex(projectIndex{Version: indexVersion, Files: fresh})
+	}
+	return summaries, nil
+}
+
+// indexFile reads a project file into an index entry.
+func (b *jsonBackend) indexFile(path string, info fs.FileInfo) (indexEntry, error) {
+	data, err := readNonEmpty(path)
+	if err != nil {
+		return indexEntry{}, err
+	}
+	entry := indexEntry{Size: info.Size(), ModTime: info.ModTime().UnixNano()}
+	if env, ok := readEnvelope(data); ok {
+		env.Data = nil
+		entry.Summary = ProjectSummary{ID: env.ID, Encrypted: true}
+		entry.Sealed = &env
+		return entry, nil
+	}
+	project, err := decodeProject(data)
+	if err != nil {
+		return indexEntry{}, err
+	}
+	entry.Summary = summarize(project)
+	return entry, nil
+}
+
+// unseal fills in the name of an encrypted project from its header, or
+// LockedName when the key cannot open it.
+func (b *jsonBackend) unseal(entry indexEntry) ProjectSummary {
+	summary := entry.Summary
+	if entry.Sealed == nil {
+		return summary
+	}
+	summary.Name = LockedName
+	plaintext, err := b.keys.open(*entry.Sealed, entry.Sealed.Header, false)
+	if err != nil {
+		return summary
+	}
+	var header envelopeHeader
+	if json.Unmarshal(plaintext, &header) == nil {
+		summary.Name = header.Name
+		summary.Archived = header.Archived
+	}
+	return summary
+}
+
+func (b *jsonBackend) readIndex() projectIndex {
+	var index projectIndex
+	data, err := os.ReadFile(b.indexPath())
+	if err != nil || json.Unmarshal(data, &index) != nil || index.Version != indexVersion {
+		return projectIndex{}
+	}
+	return index
+}
+
+// writeIndex saves the index. Like snapshots it is best effort: the index
+// is rebuilt from the files whenever it is missing or stale.
+func (b *jsonBackend) writeIndex(index projectIndex) {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return
+	}
+	_ = fsutil.WriteFileAtomic(b.indexPath(), data, 0o644)
+}
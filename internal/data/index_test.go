@@ -0,0 +1,120 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"phasionary/internal/domain"
+)
+
+func TestListSummaries_UsesIndexUntilFileChanges(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "projects")
+	store := NewStore(dir)
+	require.NoError(t, store.Ensure())
+
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	summaries, err := store.ListSummaries()
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "Work", summaries[0].Name)
+	assert.Equal(t, 10, summaries[0].Tasks)
+	assert.Equal(t, 2, summaries[0].Completed)
+	assert.Equal(t, 5, summaries[0].Categories)
+	_, err = os.Stat(filepath.Join(dir, "..", IndexFile))
+	require.NoError(t, err)
+
+	// A save changes the file, so its entry is read again.
+	require.NoError(t, project.Categories[0].Tasks[0].SetStatus(domain.StatusCompleted))
+	project.Name = "Work 2"
+	require.NoError(t, store.SaveProject(&project))
+	summaries, err = store.ListSummaries()
+	require.NoError(t, err)
+	assert.Equal(t, "Work 2", summaries[0].Name)
+	assert.Equal(t, 3, summaries[0].Completed)
+
+	// Deleted projects leave the index.
+	require.NoError(t, store.DeleteProject(project.ID))
+	summaries, err = store.ListSummaries()
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+}
+
+func TestLoadProject_ByIDReadsOnlyItsFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "projects")
+	store := NewStore(dir)
+	require.NoError(t, store.Ensure())
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+
+	corrupt := filepath.Join(dir, "broken.json")
+	require.NoError(t, os.WriteFile(corrupt, []byte("{not json"), 0o644))
+
+	loaded, err := store.LoadProject(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, project.ID, loaded.ID)
+	_, err = os.Stat(corrupt)
+	assert.NoError(t, err, "loading by ID should not have read the other files")
+
+	loaded, err = store.LoadProject("work")
+	require.NoError(t, err)
+	assert.Equal(t, project.ID, loaded.ID)
+	_, err = os.Stat(corrupt)
+	assert.True(t, os.IsNotExist(err), "resolving a name lists the directory")
+}
+
+func TestListSummaries_KeepsEncryptedNamesOutOfIndex(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "projects")
+	store := NewStore(dir)
+	store.Keys.SetPassphrase([]byte("correct horse"))
+	require.NoError(t, store.Ensure())
+	project, err := store.CreateProject("Client Audit")
+	require.NoError(t, err)
+	_, err = store.SetEncrypted(project.ID, true)
+	require.NoError(t, err)
+
+	summaries, err := store.ListSummaries()
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "Client Audit", summaries[0].Name)
+	assert.True(t, summaries[0].Encrypted)
+
+	index, err := os.ReadFile(filepath.Join(dir, "..", IndexFile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(index), "Client Audit")
+
+	summaries, err = NewStore(dir).ListSummaries()
+	require.NoError(t, err)
+	assert.Equal(t, LockedName, summaries[0].Name)
+}
+
+func TestArchivedProjects(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "projects")
+	store := NewStore(dir)
+	require.NoError(t, store.Ensure())
+	alpha, err := store.CreateProject("Alpha")
+	require.NoError(t, err)
+	beta, err := store.CreateProject("Beta")
+	require.NoError(t, err)
+
+	alpha.Archived = true
+	require.NoError(t, store.SaveProject(&alpha))
+	summaries, err := store.ListSummaries()
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.True(t, summaries[0].Archived)
+	assert.False(t, summaries[1].Archived)
+
+	// Without a selector the first project that is not archived is used,
+	// while an archived one is still found by name.
+	loaded, err := store.LoadProject("")
+	require.NoError(t, err)
+	assert.Equal(t, beta.ID, loaded.ID)
+	loaded, err = store.LoadProject("alpha")
+	require.NoError(t, err)
+	assert.True(t, loaded.Archived)
+}
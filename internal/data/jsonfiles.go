@@ -0,0 +1,150 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"phasionary/internal/domain"
+	"phasionary/internal/fsutil"
+)
+
+// jsonBackend keeps each project in its own JSON file, {id}.json, guarded by
+// an advisory lock file, {id}.lock. Encrypted projects are sealed with keys.
+type jsonBackend struct {
+	dir  string
+	keys *Keyring
+}
+
+func (b *jsonBackend) list(report func(UnreadableFile)) ([]domain.Project, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []domain.Project{}, nil
+		}
+		return nil, err
+	}
+	projects := make([]domain.Project, 0)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(b.dir, entry.Name())
+		project, err := readProjectHeader(path, b.keys)
+		if err != nil {
+			report(quarantineProjectFile(path, err))
+			continue
+		}
+		if project.ID == "" {
+			continue
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+func (b *jsonBackend) load(id string) (domain.Project, error) {
+	return readProjectFile(b.projectPath(id), b.keys)
+}
+
+func (b *jsonBackend) update(id string, fn func(domain.Project, error) (domain.Project, error)) error {
+	if err := os.MkdirAll(b.dir, 0o755); err != nil {
+		return err
+	}
+	unlock, err := fsutil.Lock(b.lockPath(id))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path := b.projectPath(id)
+	project, err := fn(readProjectFile(path, b.keys))
+	if err != nil {
+		return err
+	}
+	data, err := b.keys.encode(project)
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, data, 0o644)
+}
+
+// remove deletes a project file under its lock, so it cannot interleave
+// with a save. The lock file is kept: deleting it would let a process still
+// waiting on the old file and one creating a new file both take the lock.
+func (b *jsonBackend) remove(id string) error {
+	unlock, err := fsutil.Lock(b.lockPath(id))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return os.Remove(b.projectPath(id))
+}
+
+func (b *jsonBackend) location() string {
+	return b.dir
+}
+
+func (b *jsonBackend) close() error {
+	return nil
+}
+
+func (b *jsonBackend) projectPath(id string) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%s.json", id))
+}
+
+func (b *jsonBackend) lockPath(id string) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%s.lock", id))
+}
+
+func readProjectFile(path string, keys *Keyring) (domain.Project, error) {
+	data, err := readNonEmpty(path)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	return keys.decode(data)
+}
+
+// readProjectHeader reads a project file for listing, leaving the tasks of
+// encrypted projects sealed.
+func readProjectHeader(path string, keys *Keyring) (domain.Project, error) {
+	data, err := readNonEmpty(path)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	return keys.decodeHeader(data)
+}
+
+func readNonEmpty(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errEmptyProjectFile
+	}
+	return data, nil
+}
+
+// quarantineProjectFile moves a project file that does not parse out of the
+// way. Files that fail for other reasons, such as a newer schema, are left
+// alone.
+func quarantineProjectFile(path string, err error) UnreadableFile {
+	file := UnreadableFile{Path: path, Err: err}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, errEmptyProjectFile) {
+		quarantine := fmt.Sprintf("%s.corrupt-%s", path, time.Now().UTC().Format("20060102T150405"))
+		if renameErr := os.Rename(path, quarantine); renameErr == nil {
+			file.QuarantinePath = quarantine
+		}
+	}
+	return file
+}
@@ -0,0 +1,207 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"phasionary/internal/domain"
+	"phasionary/internal/fsutil"
+)
+
+// CurrentSchemaVersion is the project file layout this build reads and
+// writes. Files written before versioning started count as version 0.
+const CurrentSchemaVersion = 1
+
+// ErrUnsupportedSchema is returned for project files written by a newer
+// phasionary than this one.
+var ErrUnsupportedSchema = errors.New("unsupported schema version")
+
+// migration upgrades a decoded project document from version-1 to version.
+// Steps work on the raw JSON so they can handle fields the current structs
+// no longer have.
+type migration struct {
+	version     int
+	description string
+	apply       func(doc map[string]any)
+}
+
+// migrations must stay ordered by version, one step per version.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "fill in empty category and task lists and missing task statuses",
+		apply:       migrateToV1,
+	},
+}
+
+func migrateToV1(doc map[string]any) {
+	categories, ok := doc["categories"].([]any)
+	if !ok {
+		doc["categories"] = []any{}
+		return
+	}
+	var fixTasks func(tasks []any)
+	fixTasks = func(tasks []any) {
+		for _, t := range tasks {
+			task, ok := t.(map[string]any)
+			if !ok {
+				continue
+			}
+			if status, _ := task["status"].(string); status == "" {
+				task["status"] = domain.StatusTodo
+			}
+			if subtasks, ok := task["subtasks"].([]any); ok {
+				fixTasks(subtasks)
+			} else {
+				delete(task, "subtasks")
+			}
+		}
+	}
+	for _, c := range categories {
+		category, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		tasks, ok := category["tasks"].([]any)
+		if !ok {
+			category["tasks"] = []any{}
+			continue
+		}
+		fixTasks(tasks)
+	}
+}
+
+// schemaVersionOf reads the schema version of an encoded project.
+func schemaVersionOf(data []byte) (int, error) {
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, err
+	}
+	if header.SchemaVersion > CurrentSchemaVersion {
+		return header.SchemaVersion, fmt.Errorf("%w: file uses schema %d but this phasionary supports up to %d; upgrade phasionary to open it",
+			ErrUnsupportedSchema, header.SchemaVersion, CurrentSchemaVersion)
+	}
+	return header.SchemaVersion, nil
+}
+
+// decodeProject parses a project file, upgrading it in memory when it was
+// written with an older schema.
+func decodeProject(data []byte) (domain.Project, error) {
+	version, err := schemaVersionOf(data)
+	if err != nil {
+		return domain.Project{}, err
+	}
+	if version < CurrentSchemaVersion {
+		if data, err = upgradeDocument(data, version); err != nil {
+			return domain.Project{}, err
+		}
+	}
+	var project domain.Project
+	if err := json.Unmarshal(data, &project); err != nil {
+		return domain.Project{}, err
+	}
+	return project, nil
+}
+
+func upgradeDocument(data []byte, from int) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	for _, step := range migrations {
+		if step.version <= from {
+			continue
+		}
+		step.apply(doc)
+		doc["schema_version"] = step.version
+	}
+	return json.Marshal(doc)
+}
+
+// MigrationPlan lists the steps needed to bring one project file up to
+// CurrentSchemaVersion.
+type MigrationPlan struct {
+	Path      string
+	ProjectID string
+	Name      string
+	From      int
+	To        int
+	Steps     []string
+}
+
+// PlanMigrations finds the project files written with an older schema.
+// Files that cannot be read are left to ListProjects to report.
+func (s *Store) PlanMigrations() ([]MigrationPlan, error) {
+	// Other backends always store the current schema.
+	if _, ok := s.backend.(*jsonBackend); !ok {
+		return []MigrationPlan{}, nil
+	}
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []MigrationPlan{}, nil
+		}
+		return nil, err
+	}
+	plans := make([]MigrationPlan, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(s.Dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		// Encryption came after every migration, so encrypted files are
+		// always current.
+		if _, encrypted := readEnvelope(data); encrypted {
+			continue
+		}
+		version, err := schemaVersionOf(data)
+		if err != nil || version >= CurrentSchemaVersion {
+			continue
+		}
+		project, err := decodeProject(data)
+		if err != nil {
+			continue
+		}
+		plan := MigrationPlan{Path: path, ProjectID: project.ID, Name: project.Name, From: version, To: CurrentSchemaVersion}
+		for _, step := range migrations {
+			if step.version > version {
+				plan.Steps = append(plan.Steps, step.description)
+			}
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// Migrate rewrites a project file with the current schema. The original
+// file is kept first as a snapshot of the project, so it is listed, restored
+// and pruned like any other.
+func (s *Store) Migrate(plan MigrationPlan) error {
+	original, err := os.ReadFile(plan.Path)
+	if err != nil {
+		return err
+	}
+	project, err := decodeProject(original)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(s.BackupDir(), plan.ProjectID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	backup := filepath.Join(dir, snapshotName(Snapshot{Revision: project.Revision, TakenAt: time.Now()}))
+	if err := fsutil.WriteFileAtomic(backup, original, 0o644); err != nil {
+		return err
+	}
+	return s.SaveProject(&project)
+}
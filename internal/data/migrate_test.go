@@ -0,0 +1,107 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"phasionary/internal/domain"
+)
+
+const unversionedProject = `{
+  "id": "p1",
+  "name": "Legacy",
+  "created_at": "2024-01-01T00:00:00Z",
+  "updated_at": "2024-01-01T00:00:00Z",
+  "categories": [
+    {"id": "c1", "name": "Feature", "tasks": [
+      {"id": "t1", "title": "Parent", "status": "", "subtasks": [
+        {"id": "t2", "title": "Child", "subtasks": null}
+      ]}
+    ]},
+    {"id": "c2", "name": "Empty", "tasks": null}
+  ]
+}`
+
+func writeProjectFile(t *testing.T, store *Store, id, content string) string {
+	t.Helper()
+	require.NoError(t, store.Ensure())
+	path := filepath.Join(store.Dir, id+".json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadProject_MigratesUnversionedFile(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	writeProjectFile(t, store, "p1", unversionedProject)
+
+	project, err := store.LoadProject("Legacy")
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, project.SchemaVersion)
+	parent := project.Categories[0].Tasks[0]
+	assert.Equal(t, domain.StatusTodo, parent.Status)
+	assert.Equal(t, domain.StatusTodo, parent.Subtasks[0].Status)
+	assert.NotNil(t, project.Categories[1].Tasks)
+}
+
+func TestLoadProject_RefusesNewerSchema(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	var reported []UnreadableFile
+	store.OnUnreadable = func(f UnreadableFile) { reported = append(reported, f) }
+	path := writeProjectFile(t, store, "p1", `{"schema_version": 99, "id": "p1", "name": "Future", "categories": []}`)
+
+	projects, err := store.ListProjects()
+	require.NoError(t, err)
+	assert.Empty(t, projects)
+	require.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0].Err, ErrUnsupportedSchema)
+	assert.Empty(t, reported[0].QuarantinePath)
+	assert.FileExists(t, path)
+
+	// An older build must not overwrite the file with its own layout.
+	err = store.SaveProject(&domain.Project{ID: "p1", Name: "Future"})
+	assert.ErrorIs(t, err, ErrUnsupportedSchema)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"schema_version": 99`)
+}
+
+func TestMigrate(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	path := writeProjectFile(t, store, "p1", unversionedProject)
+	current, err := store.CreateProject("Current")
+	require.NoError(t, err)
+
+	plans, err := store.PlanMigrations()
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	assert.Equal(t, "p1", plans[0].ProjectID)
+	assert.Equal(t, 0, plans[0].From)
+	assert.Equal(t, CurrentSchemaVersion, plans[0].To)
+	assert.Len(t, plans[0].Steps, CurrentSchemaVersion)
+
+	require.NoError(t, store.Migrate(plans[0]))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"schema_version": 1`)
+
+	snapshots, err := store.Snapshots("p1")
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	original, err := os.ReadFile(snapshots[0].Path)
+	require.NoError(t, err)
+	assert.Equal(t, unversionedProject, string(original))
+	restored, err := store.LoadSnapshot(snapshots[0])
+	require.NoError(t, err)
+	assert.Equal(t, "p1", restored.ID)
+
+	plans, err = store.PlanMigrations()
+	require.NoError(t, err)
+	assert.Empty(t, plans)
+	reloaded, err := store.LoadProject(current.ID)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, reloaded.SchemaVersion)
+}
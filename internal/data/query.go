@@ -0,0 +1,84 @@
+package data
+
+import (
+	"time"
+
+	"phasionary/internal/domain"
+)
+
+// TaskFilter narrows FindTasks. Empty fields match every task.
+type TaskFilter struct {
+	Status   string
+	Priority string
+	Category string
+	Section  string
+	Tag      string
+	// Overdue keeps open tasks whose deadline was before the day of Now.
+	Overdue bool
+	// Ready keeps todo tasks that are not blocked.
+	Ready bool
+	Now   time.Time
+}
+
+// TaskMatch is a task found by FindTasks. Task has no subtasks; they are
+// matches of their own, listed after their parent with a greater Depth.
+// Section is inherited from the top-level task.
+type TaskMatch struct {
+	Task     domain.Task
+	Category string
+	ParentID string
+	Section  string
+	Depth    int
+	Blocked  bool
+}
+
+// taskFinder is implemented by backends that can filter tasks without
+// loading the whole project.
+type taskFinder interface {
+	findTasks(projectID string, filter TaskFilter) ([]TaskMatch, error)
+}
+
+// FindTasks returns the tasks of a project that match filter, in the order
+// they appear in the project.
+func (s *Store) FindTasks(projectID string, filter TaskFilter) ([]TaskMatch, error) {
+	if finder, ok := s.backend.(taskFinder); ok {
+		return finder.findTasks(projectID, filter)
+	}
+	project, err := s.backend.load(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return matchTasks(project, filter), nil
+}
+
+func matchTasks(project domain.Project, filter TaskFilter) []TaskMatch {
+	matches := make([]TaskMatch, 0)
+	var collect func(tasks []domain.Task, cat, parentID, section string, depth int)
+	collect = func(tasks []domain.Task, cat, parentID, section string, depth int) {
+		for _, task := range tasks {
+			if depth == 0 {
+				section = task.SectionName()
+			}
+			blocked := project.IsBlocked(&task)
+			ok := (filter.Status == "" || task.Status == filter.Status) &&
+				(filter.Priority == "" || task.Priority == filter.Priority) &&
+				(filter.Section == "" || section == filter.Section) &&
+				(!filter.Overdue || task.IsOverdue(filter.Now)) &&
+				(filter.Tag == "" || task.HasTag(filter.Tag)) &&
+				(!filter.Ready || (task.Status == domain.StatusTodo && !blocked))
+			if ok {
+				match := TaskMatch{Task: task, Category: cat, ParentID: parentID, Section: section, Depth: depth, Blocked: blocked}
+				match.Task.Subtasks = nil
+				matches = append(matches, match)
+			}
+			collect(task.Subtasks, cat, task.ID, section, depth+1)
+		}
+	}
+	for _, cat := range project.Categories {
+		if filter.Category != "" && domain.NormalizeName(cat.Name) != domain.NormalizeName(filter.Category) {
+			continue
+		}
+		collect(cat.Tasks, cat.Name, "", "", 0)
+	}
+	return matches
+}
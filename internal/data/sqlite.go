@@ -0,0 +1,341 @@
+package data
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite"
+
+	"phasionary/internal/domain"
+)
+
+// SQLiteFile is the name of the database kept next to the projects directory.
+const SQLiteFile = "phasionary.db"
+
+// sqliteSchema stores each project as a JSON document, like the file
+// backend, plus one row per task so tasks can be filtered without decoding
+// every project.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS projects (
+	id       TEXT PRIMARY KEY,
+	name     TEXT NOT NULL,
+	name_key TEXT NOT NULL,
+	revision INTEGER NOT NULL,
+	data     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS projects_name_key ON projects (name_key);
+CREATE TABLE IF NOT EXISTS tasks (
+	project_id   TEXT NOT NULL,
+	position     INTEGER NOT NULL,
+	id           TEXT NOT NULL,
+	parent_id    TEXT NOT NULL,
+	depth        INTEGER NOT NULL,
+	category     TEXT NOT NULL,
+	category_key TEXT NOT NULL,
+	section      TEXT NOT NULL,
+	status       TEXT NOT NULL,
+	priority     TEXT NOT NULL,
+	deadline     TEXT NOT NULL,
+	tags         TEXT NOT NULL,
+	data         TEXT NOT NULL,
+	PRIMARY KEY (project_id, position)
+);
+CREATE INDEX IF NOT EXISTS tasks_status ON tasks (project_id, status);
+CREATE TABLE IF NOT EXISTS task_blockers (
+	project_id TEXT NOT NULL,
+	task_id    TEXT NOT NULL,
+	blocker_id TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS task_blockers_task ON task_blockers (project_id, task_id);
+`
+
+// sqliteBackend keeps projects in a SQLite database. Saves run in immediate
+// transactions, which SQLite serializes across processes.
+type sqliteBackend struct {
+	path string
+	db   *sql.DB
+}
+
+// OpenSQLiteStore opens, creating it if needed, the SQLite database next to
+// the projects directory dir. Trash, snapshots and the activity log stay in
+// their usual directories.
+func OpenSQLiteStore(dir string) (*Store, error) {
+	path := filepath.Join(dir, "..", SQLiteFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_txlock=immediate&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	store := NewStore(dir)
+	store.backend = &sqliteBackend{path: filepath.Clean(path), db: db}
+	return store, nil
+}
+
+func (b *sqliteBackend) list(report func(UnreadableFile)) ([]domain.Project, error) {
+	rows, err := b.db.Query(`SELECT id, data FROM projects`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	projects := make([]domain.Project, 0)
+	for rows.Next() {
+		var id, data string
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, err
+		}
+		project, err := decodeProject([]byte(data))
+		if err != nil {
+			report(UnreadableFile{Path: b.path + "#" + id, Err: err})
+			continue
+		}
+		projects = append(projects, project)
+	}
+	return projects, rows.Err()
+}
+
+func (b *sqliteBackend) load(id string) (domain.Project, error) {
+	return b.scanProject(b.db.QueryRow(`SELECT data FROM projects WHERE id = ?`, id))
+}
+
+func (b *sqliteBackend) summaries(report func(UnreadableFile)) ([]ProjectSummary, error) {
+	rows, err := b.db.Query(`SELECT p.id, p.name,
+		coalesce(json_extract(p.data, '$.created_at'), ''), coalesce(json_extract(p.data, '$.updated_at'), ''),
+		coalesce(json_array_length(p.data, '$.categories'), 0), coalesce(json_extract(p.data, '$.archived'), 0),
+		(SELECT count(*) FROM tasks t WHERE t.project_id = p.id),
+		(SELECT count(*) FROM tasks t WHERE t.project_id = p.id AND t.status = ?)
+		FROM projects p`, domain.StatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	summaries := make([]ProjectSummary, 0)
+	for rows.Next() {
+		var summary ProjectSummary
+		if err := rows.Scan(&summary.ID, &summary.Name, &summary.CreatedAt, &summary.UpdatedAt,
+			&summary.Categories, &summary.Archived, &summary.Tasks, &summary.Completed); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+func (b *sqliteBackend) find(selector string) (domain.Project, error) {
+	var row *sql.Row
+	if strings.TrimSpace(selector) == "" {
+		row = b.db.QueryRow(`SELECT data FROM projects ORDER BY coalesce(json_extract(data, '$.archived'), 0), name_key, id LIMIT 1`)
+	} else {
+		row = b.db.QueryRow(`SELECT data FROM projects WHERE lower(id) = lower(?) OR name_key = ? ORDER BY name_key, id LIMIT 1`,
+			selector, domain.NormalizeName(selector))
+	}
+	project, err := b.scanProject(row)
+	if errors.Is(err, fs.ErrNotExist) {
+		return domain.Project{}, ErrProjectNotFound
+	}
+	return project, err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (b *sqliteBackend) scanProject(row rowScanner) (domain.Project, error) {
+	var data string
+	if err := row.Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Project{}, fs.ErrNotExist
+		}
+		return domain.Project{}, err
+	}
+	return decodeProject([]byte(data))
+}
+
+func (b *sqliteBackend) update(id string, fn func(domain.Project, error) (domain.Project, error)) error {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	project, err := fn(b.scanProject(tx.QueryRow(`SELECT data FROM projects WHERE id = ?`, id)))
+	if err != nil {
+		return err
+	}
+	if project.Encrypted {
+		return errEncryptionUnsupported
+	}
+	data, err := json.Marshal(project)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO projects (id, name, name_key, revision, data) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, name_key = excluded.name_key, revision = excluded.revision, data = excluded.data`,
+		project.ID, project.Name, domain.NormalizeName(project.Name), project.Revision, string(data)); err != nil {
+		return err
+	}
+	if err := writeTaskRows(tx, project); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// writeTaskRows rewrites the task rows of a project.
+func writeTaskRows(tx *sql.Tx, project domain.Project) error {
+	if err := deleteTaskRows(tx, project.ID); err != nil {
+		return err
+	}
+	insertTask, err := tx.Prepare(`INSERT INTO tasks
+		(project_id, position, id, parent_id, depth, category, category_key, section, status, priority, deadline, tags, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insertTask.Close()
+	insertBlocker, err := tx.Prepare(`INSERT INTO task_blockers (project_id, task_id, blocker_id) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insertBlocker.Close()
+
+	position := 0
+	var insert func(tasks []domain.Task, cat, parentID, section string, depth int) error
+	insert = func(tasks []domain.Task, cat, parentID, section string, depth int) error {
+		for _, task := range tasks {
+			if depth == 0 {
+				section = task.SectionName()
+			}
+			row := task
+			row.Subtasks = nil
+			data, err := json.Marshal(row)
+			if err != nil {
+				return err
+			}
+			// Tags are stored space-separated with spaces around, so one tag
+			// matches with LIKE '% tag %'.
+			tags := " " + strings.Join(task.Tags, " ") + " "
+			if _, err := insertTask.Exec(project.ID, position, task.ID, parentID, depth, cat, domain.NormalizeName(cat),
+				section, task.Status, task.Priority, task.Deadline, tags, string(data)); err != nil {
+				return err
+			}
+			position++
+			for _, blocker := range task.BlockedBy {
+				if _, err := insertBlocker.Exec(project.ID, task.ID, blocker); err != nil {
+					return err
+				}
+			}
+			if err := insert(task.Subtasks, cat, task.ID, section, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, cat := range project.Categories {
+		if err := insert(cat.Tasks, cat.Name, "", "", 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteTaskRows(tx *sql.Tx, projectID string) error {
+	if _, err := tx.Exec(`DELETE FROM tasks WHERE project_id = ?`, projectID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DELETE FROM task_blockers WHERE project_id = ?`, projectID)
+	return err
+}
+
+func (b *sqliteBackend) findTasks(projectID string, filter TaskFilter) ([]TaskMatch, error) {
+	// A task is blocked while one of its blockers is neither completed nor
+	// cancelled.
+	query := `SELECT t.data, t.category, t.parent_id, t.section, t.depth,
+		EXISTS (SELECT 1 FROM task_blockers b JOIN tasks o ON o.project_id = b.project_id AND o.id = b.blocker_id
+			WHERE b.project_id = t.project_id AND b.task_id = t.id AND o.status NOT IN (?, ?)) AS blocked
+		FROM tasks t WHERE t.project_id = ?`
+	args := []any{domain.StatusCompleted, domain.StatusCancelled, projectID}
+	where := func(clause string, values ...any) {
+		query += " AND " + clause
+		args = append(args, values...)
+	}
+	if filter.Status != "" {
+		where("t.status = ?", filter.Status)
+	}
+	if filter.Priority != "" {
+		where("t.priority = ?", filter.Priority)
+	}
+	if filter.Category != "" {
+		where("t.category_key = ?", domain.NormalizeName(filter.Category))
+	}
+	if filter.Section != "" {
+		where("t.section = ?", filter.Section)
+	}
+	if filter.Tag != "" {
+		where("t.tags LIKE ? ESCAPE '\\'", "% "+escapeLike(domain.NormalizeTag(filter.Tag))+" %")
+	}
+	if filter.Overdue {
+		today := domain.StartOfDay(filter.Now).Format(domain.DateLayout)
+		where("t.deadline != '' AND t.deadline < ? AND t.status NOT IN (?, ?)", today, domain.StatusCompleted, domain.StatusCancelled)
+	}
+	if filter.Ready {
+		where("t.status = ? AND NOT blocked", domain.StatusTodo)
+	}
+	query += " ORDER BY t.position"
+
+	rows, err := b.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	matches := make([]TaskMatch, 0)
+	for rows.Next() {
+		var data string
+		var match TaskMatch
+		if err := rows.Scan(&data, &match.Category, &match.ParentID, &match.Section, &match.Depth, &match.Blocked); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(data), &match.Task); err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func (b *sqliteBackend) remove(id string) error {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM projects WHERE id = ?`, id); err != nil {
+		return err
+	}
+	if err := deleteTaskRows(tx, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (b *sqliteBackend) location() string {
+	return b.path
+}
+
+func (b *sqliteBackend) close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,143 @@
+package data
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"phasionary/internal/domain"
+)
+
+func newSQLiteTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := OpenSQLiteStore(filepath.Join(t.TempDir(), "projects"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestSQLiteStore_SaveLoadDelete(t *testing.T) {
+	store := newSQLiteTestStore(t)
+	assert.Equal(t, SQLiteFile, filepath.Base(store.Location()))
+
+	work, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	_, err = store.CreateProject("Home")
+	require.NoError(t, err)
+	_, err = store.CreateProject("work")
+	assert.Error(t, err)
+
+	projects, err := store.ListProjects()
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+	assert.Equal(t, "Home", projects[0].Name)
+
+	summaries, err := store.ListSummaries()
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, summarize(projects[1]), summaries[1])
+
+	loaded, err := store.LoadProject(" WORK ")
+	require.NoError(t, err)
+	assert.Equal(t, work.ID, loaded.ID)
+	assert.Equal(t, work.Categories, loaded.Categories)
+	first, err := store.LoadProject("")
+	require.NoError(t, err)
+	assert.Equal(t, "Home", first.Name)
+	_, err = store.LoadProject("missing")
+	assert.ErrorIs(t, err, ErrProjectNotFound)
+
+	// An archived project is skipped when no project is named.
+	first.Archived = true
+	require.NoError(t, store.SaveProject(&first))
+	summaries, err = store.ListSummaries()
+	require.NoError(t, err)
+	assert.True(t, summaries[0].Archived)
+	first, err = store.LoadProject("")
+	require.NoError(t, err)
+	assert.Equal(t, "Work", first.Name)
+
+	stale := loaded
+	loaded.Name = "Work renamed"
+	require.NoError(t, store.SaveProject(&loaded))
+	assert.Equal(t, 2, loaded.Revision)
+	assert.ErrorIs(t, store.SaveProject(&stale), ErrConflict)
+
+	require.NoError(t, store.DeleteProject(work.ID))
+	_, err = store.LoadProject(work.ID)
+	assert.ErrorIs(t, err, ErrProjectNotFound)
+	items, err := store.Trash().List()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "Work renamed", items[0].ProjectName)
+}
+
+func TestSQLiteStore_FindTasksMatchesFiles(t *testing.T) {
+	store := newSQLiteTestStore(t)
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	tasks := &project.Categories[0].Tasks
+	(*tasks)[0].Tags = []string{"ui", "web_app"}
+	(*tasks)[0].Deadline = "2024-01-01"
+	(*tasks)[0].Subtasks = []domain.Task{{ID: "sub", Title: "Sub", Status: domain.StatusTodo}}
+	require.NoError(t, project.AddDependency((*tasks)[1].ID, (*tasks)[0].ID))
+	require.NoError(t, store.SaveProject(&project))
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	filters := []TaskFilter{
+		{},
+		{Status: domain.StatusTodo},
+		{Priority: domain.PriorityHigh},
+		{Category: "fix"},
+		{Section: domain.SectionCurrent},
+		{Tag: "#UI"},
+		{Tag: "web%"},
+		{Overdue: true, Now: now},
+		{Ready: true},
+	}
+	for _, filter := range filters {
+		got, err := store.FindTasks(project.ID, filter)
+		require.NoError(t, err)
+		assert.Equal(t, matchTasks(project, filter), got, "%+v", filter)
+	}
+}
+
+func TestCopyProjects(t *testing.T) {
+	files := NewStore(filepath.Join(t.TempDir(), "projects"))
+	project, err := files.CreateProject("Work")
+	require.NoError(t, err)
+	project.Name = "Work v2"
+	require.NoError(t, files.SaveProject(&project))
+	home, err := files.CreateProject("Home")
+	require.NoError(t, err)
+
+	db := newSQLiteTestStore(t)
+	copied, removed, err := files.CopyProjects(db)
+	require.NoError(t, err)
+	assert.Equal(t, 2, copied)
+	assert.Zero(t, removed)
+	got, err := db.LoadProject(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, project, got)
+
+	back := NewStore(filepath.Join(t.TempDir(), "projects"))
+	_, _, err = db.CopyProjects(back)
+	require.NoError(t, err)
+	roundTrip, err := back.LoadProject(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, project, roundTrip)
+
+	// Deleting a project and switching again must not bring it back.
+	require.NoError(t, back.DeleteProject(home.ID))
+	copied, removed, err = back.CopyProjects(db)
+	require.NoError(t, err)
+	assert.Equal(t, 1, copied)
+	assert.Equal(t, 1, removed)
+	_, err = db.LoadProject(home.ID)
+	assert.ErrorIs(t, err, ErrProjectNotFound)
+	_, err = db.LoadProject(project.ID)
+	assert.NoError(t, err)
+}
@@ -6,6 +6,8 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+
+	"phasionary/internal/fsutil"
 )
 
 type State struct {
@@ -74,7 +76,7 @@ func (m *StateManager) Save() error {
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return err
 	}
-	return os.WriteFile(m.path, data, 0o644)
+	return fsutil.WriteFileAtomic(m.path, data, 0o644)
 }
 
 func (m *StateManager) GetLastProjectID() string {
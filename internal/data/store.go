@@ -1,7 +1,6 @@
 package data
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -15,23 +14,95 @@ import (
 
 var ErrProjectNotFound = errors.New("project not found")
 
+// ErrConflict is returned by SaveProject when the project on disk has been
+// saved by someone else since it was loaded.
+var ErrConflict = errors.New("project was changed by another process")
+
+var errEmptyProjectFile = errors.New("project file is empty")
+
 type ProjectRepository interface {
 	ListProjects() ([]domain.Project, error)
+	ListSummaries() ([]ProjectSummary, error)
 	LoadProject(selector string) (domain.Project, error)
-	SaveProject(project domain.Project) error
+	SaveProject(project *domain.Project) error
 	CreateProject(name string) (domain.Project, error)
 	DeleteProject(id string) error
 }
 
+// UnreadableFile describes a project file that ListProjects skipped.
+// Files that exist but do not parse are moved to QuarantinePath so they stop
+// getting in the way; QuarantinePath is empty when the file was left alone.
+type UnreadableFile struct {
+	Path           string
+	QuarantinePath string
+	Err            error
+}
+
 // Store manages JSON persistence in a directory.
 type Store struct {
 	Dir string
+
+	// OnUnreadable, when set, is told about every project file that
+	// ListProjects had to skip.
+	OnUnreadable func(UnreadableFile)
+
+	// Backups decides when SaveProject snapshots a project.
+	Backups BackupPolicy
+
+	// Actor and Via are recorded with every activity event: who saved and
+	// through which interface, such as "cli" or "tui".
+	Actor string
+	Via   string
+
+	// History, when set, commits the projects directory to git after
+	// every save.
+	History *GitHistory
+
+	// Keys opens and seals encrypted projects.
+	Keys *Keyring
+
+	backend backend
 }
 
 var _ ProjectRepository = (*Store)(nil)
 
+// backend is where a Store keeps its projects: JSON files in Dir by default,
+// or a SQLite database. Trash, snapshots and the activity log live next to
+// it either way.
+type backend interface {
+	// list returns every readable project, passing the others to report.
+	list(report func(UnreadableFile)) ([]domain.Project, error)
+	// load returns one project, or an error matching fs.ErrNotExist.
+	load(id string) (domain.Project, error)
+	// update calls fn with the stored version of a project, or the error
+	// loading it, and stores the project fn returns. No other writer, in
+	// this process or another, can save the project in between.
+	update(id string, fn func(current domain.Project, err error) (domain.Project, error)) error
+	remove(id string) error
+	// location names where the projects are kept, for messages.
+	location() string
+	close() error
+}
+
+// projectFinder is implemented by backends that can look a project up
+// without loading every other one.
+type projectFinder interface {
+	find(selector string) (domain.Project, error)
+}
+
 func NewStore(dir string) *Store {
-	return &Store{Dir: dir}
+	keys := NewKeyring()
+	return &Store{Dir: dir, Backups: DefaultBackupPolicy(), Actor: currentUser(), Keys: keys, backend: &jsonBackend{dir: dir, keys: keys}}
+}
+
+// Location is the directory or database file holding the projects.
+func (s *Store) Location() string {
+	return s.backend.location()
+}
+
+// Close releases the storage backend.
+func (s *Store) Close() error {
+	return s.backend.close()
 }
 
 func (s *Store) Ensure() error {
@@ -39,31 +110,10 @@ func (s *Store) Ensure() error {
 }
 
 func (s *Store) ListProjects() ([]domain.Project, error) {
-	entries, err := os.ReadDir(s.Dir)
+	projects, err := s.backend.list(s.reportUnreadable)
 	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			return []domain.Project{}, nil
-		}
 		return nil, err
 	}
-	projects := make([]domain.Project, 0)
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		if filepath.Ext(entry.Name()) != ".json" {
-			continue
-		}
-		path := filepath.Join(s.Dir, entry.Name())
-		project, err := s.loadProjectFile(path)
-		if err != nil {
-			return nil, err
-		}
-		if project.ID == "" {
-			continue
-		}
-		projects = append(projects, project)
-	}
 	sort.Slice(projects, func(i, j int) bool {
 		return strings.ToLower(projects[i].Name) < strings.ToLower(projects[j].Name)
 	})
@@ -71,45 +121,135 @@ func (s *Store) ListProjects() ([]domain.Project, error) {
 }
 
 func (s *Store) LoadProject(selector string) (domain.Project, error) {
-	projects, err := s.ListProjects()
+	if finder, ok := s.backend.(projectFinder); ok {
+		return finder.find(selector)
+	}
+	// An ID reads only its own file.
+	if selector != "" && !strings.ContainsAny(selector, `/\`) {
+		project, err := s.backend.load(selector)
+		switch {
+		case err == nil && project.ID == selector:
+			return project, nil
+		case errors.Is(err, ErrUnsupportedSchema), errors.Is(err, ErrLocked), errors.Is(err, ErrWrongKey):
+			return domain.Project{}, err
+		}
+	}
+	summaries, err := s.ListSummaries()
 	if err != nil {
 		return domain.Project{}, err
 	}
-	if len(projects) == 0 {
+	if len(summaries) == 0 {
 		return domain.Project{}, ErrProjectNotFound
 	}
 	if strings.TrimSpace(selector) == "" {
-		return projects[0], nil
+		// Without a selector, the first project that is not archived.
+		for _, summary := range summaries {
+			if !summary.Archived {
+				return s.loadSummarized(summary)
+			}
+		}
+		return s.loadSummarized(summaries[0])
 	}
 	needle := domain.NormalizeName(selector)
-	for _, project := range projects {
-		if strings.EqualFold(project.ID, selector) || domain.NormalizeName(project.Name) == needle {
-			return project, nil
+	locked := false
+	for _, summary := range summaries {
+		if strings.EqualFold(summary.ID, selector) || domain.NormalizeName(summary.Name) == needle {
+			return s.loadSummarized(summary)
 		}
+		locked = locked || (summary.Encrypted && summary.Name == LockedName)
+	}
+	// The project may be one whose name is still encrypted.
+	if locked {
+		if unlocked, err := s.Keys.unlock(); err != nil {
+			return domain.Project{}, err
+		} else if unlocked {
+			return s.LoadProject(selector)
+		}
+		return domain.Project{}, fmt.Errorf("%w (encrypted projects need the passphrase to be found by name)", ErrProjectNotFound)
 	}
 	return domain.Project{}, ErrProjectNotFound
 }
 
-func (s *Store) SaveProject(project domain.Project) error {
-	project.UpdatedAt = domain.NowTimestamp()
-	path := s.projectPath(project.ID)
-	data, err := json.MarshalIndent(project, "", "  ")
+// loadSummarized loads the project a summary was listed for.
+func (s *Store) loadSummarized(summary ProjectSummary) (domain.Project, error) {
+	project, err := s.backend.load(summary.ID)
+	if errors.Is(err, fs.ErrNotExist) {
+		return domain.Project{}, ErrProjectNotFound
+	}
+	return project, err
+}
+
+// LoadProjects returns every project in full, like ListProjects but with
+// encrypted projects decrypted. Those that cannot be decrypted are left out.
+func LoadProjects(repo ProjectRepository) ([]domain.Project, error) {
+	projects, err := repo.ListProjects()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+	loaded := make([]domain.Project, 0, len(projects))
+	for _, project := range projects {
+		if project.Sealed {
+			if project, err = repo.LoadProject(project.ID); err != nil {
+				continue
+			}
+		}
+		loaded = append(loaded, project)
+	}
+	return loaded, nil
+}
+
+// SaveProject writes the project if the stored copy still has the same
+// revision, bumping project.Revision and UpdatedAt on success. The check and
+// the write happen under a lock shared by every phasionary process, so
+// concurrent saves cannot overwrite each other unnoticed.
+func (s *Store) SaveProject(project *domain.Project) error {
+	if project.Sealed {
+		return errors.New("cannot save an encrypted project that was listed without its contents")
+	}
+	var current, saved domain.Project
+	err := s.backend.update(project.ID, func(stored domain.Project, err error) (domain.Project, error) {
+		// A missing or unparsable file has nothing worth protecting, but one
+		// from a newer phasionary must not be overwritten with an older
+		// layout. A project that was saved before and is now missing was
+		// deleted, and saving it would bring it back; new and restored
+		// projects come with revision 0.
+		switch {
+		case errors.Is(err, ErrUnsupportedSchema), errors.Is(err, ErrLocked), errors.Is(err, ErrWrongKey):
+			return domain.Project{}, err
+		case errors.Is(err, fs.ErrNotExist) && project.Revision > 0:
+			return domain.Project{}, ErrProjectNotFound
+		case err != nil:
+			current = domain.Project{}
+		case stored.Revision != project.Revision:
+			return domain.Project{}, ErrConflict
+		default:
+			current = stored
+		}
+		saved = *project
+		saved.SchemaVersion = CurrentSchemaVersion
+		saved.Revision++
+		saved.UpdatedAt = domain.NowTimestamp()
+		return saved, nil
+	})
+	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0o644)
+	project.SchemaVersion = saved.SchemaVersion
+	project.Revision = saved.Revision
+	project.UpdatedAt = saved.UpdatedAt
+	s.recordActivity(current, saved)
+	s.recordHistory(current, saved)
+	s.snapshotAfterSave(saved)
+	return nil
 }
 
 func (s *Store) CreateProject(name string) (domain.Project, error) {
-	projects, err := s.ListProjects()
+	summaries, err := s.ListSummaries()
 	if err != nil {
 		return domain.Project{}, err
 	}
 	needle := domain.NormalizeName(name)
-	for _, project := range projects {
+	for _, project := range summaries {
 		if domain.NormalizeName(project.Name) == needle {
 			return domain.Project{}, fmt.Errorf("project %q already exists", name)
 		}
@@ -123,7 +263,7 @@ func (s *Store) CreateProject(name string) (domain.Project, error) {
 		return domain.Project{}, err
 	}
 	project.Categories = populateSampleTasks(project.Categories)
-	if err := s.SaveProject(project); err != nil {
+	if err := s.SaveProject(&project); err != nil {
 		return domain.Project{}, err
 	}
 	return project, nil
@@ -133,12 +273,12 @@ func (s *Store) InitDefault() (domain.Project, error) {
 	if err := s.Ensure(); err != nil {
 		return domain.Project{}, err
 	}
-	projects, err := s.ListProjects()
+	summaries, err := s.ListSummaries()
 	if err != nil {
 		return domain.Project{}, err
 	}
-	if len(projects) > 0 {
-		return projects[0], nil
+	if len(summaries) > 0 {
+		return s.loadSummarized(summaries[0])
 	}
 	return s.CreateProject("Default")
 }
@@ -155,28 +295,92 @@ func (s *Store) defaultCategories() ([]domain.Category, error) {
 	return categories, nil
 }
 
-func (s *Store) loadProjectFile(path string) (domain.Project, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return domain.Project{}, err
-	}
-	var project domain.Project
-	if err := json.Unmarshal(data, &project); err != nil {
-		return domain.Project{}, err
+func (s *Store) reportUnreadable(file UnreadableFile) {
+	if s.OnUnreadable != nil {
+		s.OnUnreadable(file)
 	}
-	return project, nil
 }
 
-func (s *Store) projectPath(id string) string {
-	return filepath.Join(s.Dir, fmt.Sprintf("%s.json", id))
+// CopyProjects makes dst hold the projects of s as they are, keeping
+// revisions and timestamps, for switching storage backends. Projects in dst
+// that s does not have, left over from an earlier switch, are removed so
+// deleted projects do not come back. It refuses to copy when a project of s
+// cannot be read, since the copy in dst may then be the only good one. It
+// returns how many projects were copied and removed.
+func (s *Store) CopyProjects(dst *Store) (copied, removed int, err error) {
+	var unreadable []UnreadableFile
+	projects, err := s.backend.list(func(f UnreadableFile) {
+		unreadable = append(unreadable, f)
+		s.reportUnreadable(f)
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(unreadable) > 0 {
+		return 0, 0, fmt.Errorf("cannot read %s: %w", unreadable[0].Path, unreadable[0].Err)
+	}
+	existing, err := dst.backend.list(dst.reportUnreadable)
+	if err != nil {
+		return 0, 0, err
+	}
+	kept := make(map[string]bool, len(projects))
+	for _, project := range projects {
+		kept[project.ID] = true
+		if project.Sealed {
+			if project, err = s.backend.load(project.ID); err != nil {
+				return copied, removed, fmt.Errorf("copy %s: %w", project.ID, err)
+			}
+		}
+		project.SchemaVersion = CurrentSchemaVersion
+		if err := dst.backend.update(project.ID, func(domain.Project, error) (domain.Project, error) {
+			return project, nil
+		}); err != nil {
+			return copied, removed, fmt.Errorf("copy %s: %w", project.Name, err)
+		}
+		copied++
+	}
+	for _, stale := range existing {
+		if kept[stale.ID] {
+			continue
+		}
+		if err := dst.backend.remove(stale.ID); err != nil {
+			return copied, removed, fmt.Errorf("remove %s: %w", stale.ID, err)
+		}
+		removed++
+	}
+	return copied, removed, nil
 }
 
+// DeleteProject moves the project to the trash.
 func (s *Store) DeleteProject(id string) error {
-	path := s.projectPath(id)
-	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
-		return ErrProjectNotFound
+	project, err := s.backend.load(id)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ErrProjectNotFound
+		}
+		return err
 	}
-	return os.Remove(path)
+	if _, err := s.Trash().Add(TrashProject(project)); err != nil {
+		return err
+	}
+	if err := s.backend.remove(id); err != nil {
+		return err
+	}
+	if s.History != nil {
+		name := project.Name
+		if project.Encrypted {
+			name = "Encrypted project " + project.ID
+		}
+		s.History.Record(fmt.Sprintf("%s: project moved to trash", name))
+	}
+	return nil
+}
+
+// Trash returns the trash kept alongside the projects directory.
+func (s *Store) Trash() *Trash {
+	trash := NewTrash(filepath.Join(s.Dir, "..", "trash"))
+	trash.Keys = s.Keys
+	return trash
 }
 
 type sampleTask struct {
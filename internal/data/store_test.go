@@ -32,6 +32,30 @@ func TestDeleteProject(t *testing.T) {
 	assert.Empty(t, projects)
 }
 
+func TestDeleteProject_StaysDeleted(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+	require.NoError(t, store.Ensure())
+
+	project, err := store.CreateProject("Test Project")
+	require.NoError(t, err)
+	stale := project
+	require.NoError(t, store.DeleteProject(project.ID))
+
+	_, err = os.Stat(filepath.Join(tmpDir, project.ID+".lock"))
+	assert.NoError(t, err, "the lock file is kept")
+
+	// A save from a process that still had the project open must not bring
+	// it back.
+	assert.ErrorIs(t, store.SaveProject(&stale), ErrProjectNotFound)
+	_, err = store.LoadProject(project.ID)
+	assert.ErrorIs(t, err, ErrProjectNotFound)
+
+	restored := project.Clone()
+	restored.Revision = 0
+	require.NoError(t, store.SaveProject(&restored))
+}
+
 func TestDeleteProject_NotFound(t *testing.T) {
 	tmpDir := t.TempDir()
 	store := NewStore(tmpDir)
@@ -40,3 +64,64 @@ func TestDeleteProject_NotFound(t *testing.T) {
 	err := store.DeleteProject("nonexistent-id")
 	assert.ErrorIs(t, err, ErrProjectNotFound)
 }
+
+func TestListProjects_QuarantinesCorruptFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+	require.NoError(t, store.Ensure())
+
+	project, err := store.CreateProject("Healthy")
+	require.NoError(t, err)
+	corruptPath := filepath.Join(tmpDir, "broken.json")
+	require.NoError(t, os.WriteFile(corruptPath, []byte(`{"id": "broken", "name": "Bro`), 0o644))
+	emptyPath := filepath.Join(tmpDir, "empty.json")
+	require.NoError(t, os.WriteFile(emptyPath, nil, 0o644))
+
+	var reported []UnreadableFile
+	store.OnUnreadable = func(f UnreadableFile) { reported = append(reported, f) }
+
+	projects, err := store.ListProjects()
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, project.ID, projects[0].ID)
+
+	require.Len(t, reported, 2)
+	for _, f := range reported {
+		assert.Error(t, f.Err)
+		assert.NotEmpty(t, f.QuarantinePath)
+		_, err := os.Stat(f.Path)
+		assert.True(t, os.IsNotExist(err), "corrupt file should be moved aside")
+		_, err = os.Stat(f.QuarantinePath)
+		assert.NoError(t, err)
+	}
+
+	reported = nil
+	_, err = store.ListProjects()
+	require.NoError(t, err)
+	assert.Empty(t, reported, "quarantined files are not reported again")
+}
+
+func TestSaveProject_RejectsStaleWrites(t *testing.T) {
+	store := NewStore(t.TempDir())
+	require.NoError(t, store.Ensure())
+
+	project, err := store.CreateProject("Shared")
+	require.NoError(t, err)
+	assert.Equal(t, 1, project.Revision)
+
+	tui, err := store.LoadProject(project.ID)
+	require.NoError(t, err)
+	cli, err := store.LoadProject(project.ID)
+	require.NoError(t, err)
+
+	cli.Name = "Renamed by CLI"
+	require.NoError(t, store.SaveProject(&cli))
+	assert.Equal(t, 2, cli.Revision)
+
+	tui.Name = "Renamed by TUI"
+	assert.ErrorIs(t, store.SaveProject(&tui), ErrConflict)
+
+	onDisk, err := store.LoadProject(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed by CLI", onDisk.Name)
+}
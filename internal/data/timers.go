@@ -0,0 +1,58 @@
+package data
+
+import (
+	"time"
+
+	"phasionary/internal/domain"
+)
+
+// RunningTimer is a timer running on a task of some project.
+type RunningTimer struct {
+	ProjectID   string
+	ProjectName string
+	Task        domain.Task
+}
+
+// StopTimersExcept stops running timers in every project other than
+// keepProjectID and saves the projects it changed, so that starting a timer
+// leaves only one running across all projects.
+func StopTimersExcept(repo ProjectRepository, keepProjectID string, now time.Time) (int, error) {
+	projects, err := LoadProjects(repo)
+	if err != nil {
+		return 0, err
+	}
+	stopped := 0
+	for _, project := range projects {
+		if project.ID == keepProjectID {
+			continue
+		}
+		n := project.StopTimers(now)
+		if n == 0 {
+			continue
+		}
+		if err := repo.SaveProject(&project); err != nil {
+			return stopped, err
+		}
+		stopped += n
+	}
+	return stopped, nil
+}
+
+// FindRunningTimer returns the timer running in a project other than
+// skipProjectID, if there is one. Encrypted projects that cannot be opened
+// are left out.
+func FindRunningTimer(repo ProjectRepository, skipProjectID string) (RunningTimer, bool, error) {
+	projects, err := LoadProjects(repo)
+	if err != nil {
+		return RunningTimer{}, false, err
+	}
+	for _, project := range projects {
+		if project.ID == skipProjectID {
+			continue
+		}
+		if task := project.RunningTimer(); task != nil {
+			return RunningTimer{ProjectID: project.ID, ProjectName: project.Name, Task: *task}, true, nil
+		}
+	}
+	return RunningTimer{}, false, nil
+}
@@ -0,0 +1,65 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopTimersExcept(t *testing.T) {
+	store := NewStore(t.TempDir())
+	require.NoError(t, store.Ensure())
+	now := time.Now()
+
+	first, err := store.CreateProject("First")
+	require.NoError(t, err)
+	second, err := store.CreateProject("Second")
+	require.NoError(t, err)
+	require.NoError(t, first.Categories[0].Tasks[0].StartTimer(now))
+	require.NoError(t, store.SaveProject(&first))
+	require.NoError(t, second.Categories[0].Tasks[0].StartTimer(now))
+	require.NoError(t, store.SaveProject(&second))
+
+	stopped, err := StopTimersExcept(store, second.ID, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, stopped)
+
+	first, err = store.LoadProject(first.ID)
+	require.NoError(t, err)
+	assert.Nil(t, first.RunningTimer())
+	second, err = store.LoadProject(second.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, second.RunningTimer())
+}
+
+func TestFindRunningTimer(t *testing.T) {
+	store := NewStore(t.TempDir())
+	require.NoError(t, store.Ensure())
+	now := time.Now()
+
+	first, err := store.CreateProject("First")
+	require.NoError(t, err)
+	second, err := store.CreateProject("Second")
+	require.NoError(t, err)
+
+	_, ok, err := FindRunningTimer(store, first.ID)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	task := &second.Categories[0].Tasks[0]
+	require.NoError(t, task.StartTimer(now))
+	require.NoError(t, store.SaveProject(&second))
+
+	running, ok, err := FindRunningTimer(store, first.ID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, second.ID, running.ProjectID)
+	assert.Equal(t, "Second", running.ProjectName)
+	assert.Equal(t, task.ID, running.Task.ID)
+
+	_, ok, err = FindRunningTimer(store, second.ID)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
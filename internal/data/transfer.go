@@ -0,0 +1,231 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+
+	"phasionary/internal/domain"
+)
+
+// ErrSameProject is returned when a task or category is moved to the
+// project it is already in.
+var ErrSameProject = errors.New("already in this project")
+
+// MoveTask moves a task, with its subtasks, from one project to another.
+// place puts the task into the target project. The task keeps its IDs, and
+// its activity history follows it, unless the target already uses one of
+// them. Links to tasks left behind are dropped on both sides.
+//
+// The target is saved before the source, so a failure in between leaves the
+// task in both projects rather than in neither; the error then says so.
+// from and to are only updated once saved, and the returned task is only
+// set when it was added to the target.
+func MoveTask(repo ProjectRepository, from, to *domain.Project, taskID string, place func(*domain.Project, domain.Task) error) (domain.Task, error) {
+	if from.ID == to.ID {
+		return domain.Task{}, ErrSameProject
+	}
+	source, target := from.Clone(), to.Clone()
+	task, ok := source.RemoveTaskByID(taskID)
+	if !ok {
+		return domain.Task{}, fmt.Errorf("task %s not found in %s", taskID, from.Name)
+	}
+	moved := []domain.Task{task}
+	if usesAnyID(target, moved) {
+		if err := task.ReassignIDs(); err != nil {
+			return domain.Task{}, err
+		}
+		moved = nil
+	}
+	task.UpdatedAt = domain.NowTimestamp()
+	if err := place(&target, task); err != nil {
+		return domain.Task{}, err
+	}
+	added, err := saveTransfer(repo, from, to, source, target, moved)
+	if !added {
+		return domain.Task{}, err
+	}
+	return task, err
+}
+
+// MoveCategory moves a category and its tasks from one project to the end
+// of another, like MoveTask. The target must not already have a category
+// with the same name.
+func MoveCategory(repo ProjectRepository, from, to *domain.Project, categoryID string) (domain.Category, error) {
+	if from.ID == to.ID {
+		return domain.Category{}, ErrSameProject
+	}
+	source, target := from.Clone(), to.Clone()
+	index := categoryIndex(source, categoryID)
+	if index < 0 {
+		return domain.Category{}, fmt.Errorf("category %s not found in %s", categoryID, from.Name)
+	}
+	cat := source.Categories[index]
+	if err := checkCategoryName(target, cat.Name); err != nil {
+		return domain.Category{}, err
+	}
+	if err := source.RemoveCategory(index); err != nil {
+		return domain.Category{}, err
+	}
+	moved := cat.Tasks
+	if categoryIndex(target, cat.ID) >= 0 || usesAnyID(target, cat.Tasks) {
+		if err := reassignCategoryIDs(&cat); err != nil {
+			return domain.Category{}, err
+		}
+		moved = nil
+	}
+	cat.UpdatedAt = domain.NowTimestamp()
+	target.AddCategory(cat)
+	added, err := saveTransfer(repo, from, to, source, target, moved)
+	if !added {
+		return domain.Category{}, err
+	}
+	return cat, err
+}
+
+// CopyTask copies a task, with its subtasks, into a project, which may be
+// the one it came from. The copy gets fresh IDs and no tracked time, and
+// links between the copied tasks point at the copies.
+func CopyTask(repo ProjectRepository, to *domain.Project, task domain.Task, place func(*domain.Project, domain.Task) error) (domain.Task, error) {
+	target := to.Clone()
+	copied := task.Clone()
+	if err := copied.ReassignIDs(); err != nil {
+		return domain.Task{}, err
+	}
+	domain.RetargetCopies([]domain.Task{task}, []domain.Task{copied})
+	copied.ClearTimeEntries()
+	copied.UpdatedAt = domain.NowTimestamp()
+	if err := place(&target, copied); err != nil {
+		return domain.Task{}, err
+	}
+	target.PruneDependencies()
+	if err := repo.SaveProject(&target); err != nil {
+		return domain.Task{}, err
+	}
+	*to = target
+	return copied, nil
+}
+
+// CopyCategory copies a category and its tasks to the end of a project,
+// like CopyTask.
+func CopyCategory(repo ProjectRepository, to *domain.Project, cat domain.Category) (domain.Category, error) {
+	if err := checkCategoryName(*to, cat.Name); err != nil {
+		return domain.Category{}, err
+	}
+	target := to.Clone()
+	scratch := domain.Project{Categories: []domain.Category{cat}}
+	copied := scratch.Clone().Categories[0]
+	if err := reassignCategoryIDs(&copied); err != nil {
+		return domain.Category{}, err
+	}
+	domain.RetargetCopies(cat.Tasks, copied.Tasks)
+	for i := range copied.Tasks {
+		copied.Tasks[i].ClearTimeEntries()
+	}
+	copied.CreatedAt = domain.NowTimestamp()
+	copied.UpdatedAt = copied.CreatedAt
+	target.AddCategory(copied)
+	target.PruneDependencies()
+	if err := repo.SaveProject(&target); err != nil {
+		return domain.Category{}, err
+	}
+	*to = target
+	return copied, nil
+}
+
+// saveTransfer saves both sides of a move, then carries the activity of
+// the moved tasks over to the target. It reports whether the target was
+// saved.
+func saveTransfer(repo ProjectRepository, from, to *domain.Project, source, target domain.Project, moved []domain.Task) (bool, error) {
+	source.PruneDependencies()
+	target.PruneDependencies()
+	if err := repo.SaveProject(&target); err != nil {
+		return false, err
+	}
+	*to = target
+	copyActivity(repo, source, target, moved)
+	if err := repo.SaveProject(&source); err != nil {
+		return true, fmt.Errorf("added to %s but not removed from %s: %w", target.Name, source.Name, err)
+	}
+	*from = source
+	return true, nil
+}
+
+func categoryIndex(project domain.Project, id string) int {
+	for i, cat := range project.Categories {
+		if cat.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func checkCategoryName(project domain.Project, name string) error {
+	for _, cat := range project.Categories {
+		if domain.NormalizeName(cat.Name) == domain.NormalizeName(name) {
+			return fmt.Errorf("%s already has a category named %q", project.Name, name)
+		}
+	}
+	return nil
+}
+
+// usesAnyID reports whether the project already holds a task with the ID
+// of one of tasks or their subtasks.
+func usesAnyID(project domain.Project, tasks []domain.Task) bool {
+	found := false
+	domain.WalkTasks(tasks, func(task *domain.Task, _ int) {
+		if _, _, ok := project.FindTask(task.ID); ok {
+			found = true
+		}
+	})
+	return found
+}
+
+func reassignCategoryIDs(cat *domain.Category) error {
+	id, err := domain.NewID()
+	if err != nil {
+		return err
+	}
+	cat.ID = id
+	for i := range cat.Tasks {
+		if err := cat.Tasks[i].ReassignIDs(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyActivity adds the logged events of moved tasks to the target
+// project's log, skipping any it already has, as when a task moves back.
+// Encrypted projects are not logged, and it never fails a move.
+func copyActivity(repo ProjectRepository, source, target domain.Project, tasks []domain.Task) {
+	store, ok := repo.(*Store)
+	if !ok || source.Encrypted || target.Encrypted {
+		return
+	}
+	ids := make(map[string]bool)
+	domain.WalkTasks(tasks, func(task *domain.Task, _ int) { ids[task.ID] = true })
+	log := store.ActivityLog()
+	events, err := log.Project(source.ID)
+	if err != nil {
+		return
+	}
+	existing, err := log.Project(target.ID)
+	if err != nil {
+		return
+	}
+	seen := make(map[ActivityEvent]bool, len(existing))
+	for _, event := range existing {
+		seen[event] = true
+	}
+	var copied []ActivityEvent
+	for _, event := range events {
+		if !ids[event.TaskID] {
+			continue
+		}
+		event.ProjectID = target.ID
+		if !seen[event] {
+			copied = append(copied, event)
+		}
+	}
+	_ = log.Append(copied)
+}
@@ -0,0 +1,122 @@
+package data
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"phasionary/internal/domain"
+)
+
+func appendTo(categoryIndex int) func(*domain.Project, domain.Task) error {
+	return func(project *domain.Project, task domain.Task) error {
+		project.Categories[categoryIndex].AddTask(task)
+		return nil
+	}
+}
+
+func TestMoveTask_KeepsIDsAndHistory(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	require.NoError(t, store.Ensure())
+	work, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	home, err := store.CreateProject("Home")
+	require.NoError(t, err)
+
+	tasks := work.Categories[0].Tasks
+	require.GreaterOrEqual(t, len(tasks), 2)
+	moving, staying := tasks[0].ID, tasks[1].ID
+	require.NoError(t, work.AddDependency(staying, moving))
+	work.Categories[0].Tasks[0].Title = "Renamed before the move"
+	require.NoError(t, store.SaveProject(&work))
+
+	moved, err := MoveTask(store, &work, &home, moving, appendTo(1))
+	require.NoError(t, err)
+	assert.Equal(t, moving, moved.ID)
+
+	_, _, ok := work.FindTask(moving)
+	assert.False(t, ok)
+	blocked, _, ok := work.FindTask(staying)
+	require.True(t, ok)
+	assert.Empty(t, blocked.BlockedBy)
+	_, catIdx, ok := home.FindTask(moving)
+	require.True(t, ok)
+	assert.Equal(t, 1, catIdx)
+
+	// Both sides are on disk, and the history came along.
+	reloaded, err := store.LoadProject(work.ID)
+	require.NoError(t, err)
+	assert.Equal(t, work.Revision, reloaded.Revision)
+	reloaded, err = store.LoadProject(home.ID)
+	require.NoError(t, err)
+	_, _, ok = reloaded.FindTask(moving)
+	assert.True(t, ok)
+	events, err := store.ActivityLog().Task(home.ID, moving)
+	require.NoError(t, err)
+	var kinds []string
+	for _, event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+	assert.Contains(t, kinds, domain.ActivityRenamed)
+
+	_, err = MoveTask(store, &home, &home, moving, appendTo(0))
+	assert.ErrorIs(t, err, ErrSameProject)
+}
+
+func TestMoveTask_StaleTargetLeavesSourceAlone(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	require.NoError(t, store.Ensure())
+	work, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	home, err := store.CreateProject("Home")
+	require.NoError(t, err)
+	stale := home
+	require.NoError(t, store.SaveProject(&home))
+
+	taskID := work.Categories[0].Tasks[0].ID
+	moved, err := MoveTask(store, &work, &stale, taskID, appendTo(0))
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Empty(t, moved.ID)
+	_, _, ok := work.FindTask(taskID)
+	assert.True(t, ok)
+	reloaded, err := store.LoadProject(work.ID)
+	require.NoError(t, err)
+	_, _, ok = reloaded.FindTask(taskID)
+	assert.True(t, ok)
+}
+
+func TestMoveAndCopyCategory(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	require.NoError(t, store.Ensure())
+	work, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	home, err := store.CreateProject("Home")
+	require.NoError(t, err)
+
+	// Both projects start with the default categories.
+	_, err = MoveCategory(store, &work, &home, work.Categories[0].ID)
+	assert.ErrorContains(t, err, "already has a category")
+
+	require.NoError(t, home.RemoveCategory(0))
+	require.NoError(t, store.SaveProject(&home))
+	cat := work.Categories[0]
+	moved, err := MoveCategory(store, &work, &home, cat.ID)
+	require.NoError(t, err)
+	assert.Equal(t, cat.ID, moved.ID)
+	assert.Equal(t, cat.ID, home.Categories[len(home.Categories)-1].ID)
+	assert.NotEqual(t, cat.ID, work.Categories[0].ID)
+
+	copied, err := CopyCategory(store, &work, moved)
+	require.NoError(t, err)
+	assert.NotEqual(t, moved.ID, copied.ID)
+	assert.Equal(t, moved.Name, copied.Name)
+	assert.NotEqual(t, moved.Tasks[0].ID, copied.Tasks[0].ID)
+
+	task, err := CopyTask(store, &work, home.Categories[0].Tasks[0], appendTo(0))
+	require.NoError(t, err)
+	assert.NotEqual(t, home.Categories[0].Tasks[0].ID, task.ID)
+	_, _, ok := work.FindTask(task.ID)
+	assert.True(t, ok)
+}
@@ -0,0 +1,386 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"phasionary/internal/domain"
+	"phasionary/internal/fsutil"
+)
+
+const (
+	TrashKindTask     = "task"
+	TrashKindCategory = "category"
+	TrashKindProject  = "project"
+)
+
+var (
+	ErrTrashItemNotFound = errors.New("trash item not found")
+	// ErrAlreadyRestored is returned when the deleted item is present again,
+	// for instance because the deletion was undone.
+	ErrAlreadyRestored = errors.New("item already exists")
+)
+
+// TrashItem is a deleted task, category or project together with where it
+// was, so it can be put back.
+type TrashItem struct {
+	ID           string           `json:"id"`
+	Kind         string           `json:"kind"`
+	DeletedAt    string           `json:"deleted_at"`
+	ProjectID    string           `json:"project_id"`
+	ProjectName  string           `json:"project_name"`
+	CategoryID   string           `json:"category_id,omitempty"`
+	CategoryName string           `json:"category_name,omitempty"`
+	ParentID     string           `json:"parent_id,omitempty"`
+	Position     int              `json:"position"`
+	Task         *domain.Task     `json:"task,omitempty"`
+	Category     *domain.Category `json:"category,omitempty"`
+	Project      *domain.Project  `json:"project,omitempty"`
+
+	// Encrypted items come from an encrypted project and are stored
+	// encrypted like it.
+	Encrypted bool `json:"-"`
+}
+
+// Title names the deleted item for display.
+func (i TrashItem) Title() string {
+	switch {
+	case i.Task != nil:
+		return i.Task.Title
+	case i.Category != nil:
+		return i.Category.Name
+	case i.Project != nil:
+		return i.Project.Name
+	}
+	return ""
+}
+
+// Location describes where the item was deleted from.
+func (i TrashItem) Location() string {
+	switch i.Kind {
+	case TrashKindTask:
+		return i.ProjectName + " / " + i.CategoryName
+	case TrashKindCategory:
+		return i.ProjectName
+	}
+	return ""
+}
+
+// Trash keeps deleted items as JSON files in a directory next to the
+// projects.
+type Trash struct {
+	Dir string
+	// Keys seals items from encrypted projects.
+	Keys *Keyring
+}
+
+func NewTrash(dir string) *Trash {
+	return &Trash{Dir: dir}
+}
+
+// TrashTask builds the trash entry for a task about to be removed from
+// project.
+func TrashTask(project domain.Project, taskID string) (TrashItem, bool) {
+	siblings, index, catIdx, ok := project.LocateTask(taskID)
+	if !ok {
+		return TrashItem{}, false
+	}
+	task := (*siblings)[index].Clone()
+	item := TrashItem{
+		Kind:         TrashKindTask,
+		ProjectID:    project.ID,
+		ProjectName:  project.Name,
+		CategoryID:   project.Categories[catIdx].ID,
+		CategoryName: project.Categories[catIdx].Name,
+		Position:     index,
+		Task:         &task,
+		Encrypted:    project.Encrypted,
+	}
+	if siblings != &project.Categories[catIdx].Tasks {
+		domain.WalkTasks(project.Categories[catIdx].Tasks, func(t *domain.Task, _ int) {
+			if &t.Subtasks == siblings {
+				item.ParentID = t.ID
+			}
+		})
+	}
+	return item, true
+}
+
+// TrashCategory builds the trash entry for the category at index.
+func TrashCategory(project domain.Project, index int) TrashItem {
+	cat := project.Categories[index]
+	clone := cat
+	clone.Tasks = make([]domain.Task, len(cat.Tasks))
+	for i := range cat.Tasks {
+		clone.Tasks[i] = cat.Tasks[i].Clone()
+	}
+	return TrashItem{
+		Kind:         TrashKindCategory,
+		ProjectID:    project.ID,
+		ProjectName:  project.Name,
+		CategoryID:   cat.ID,
+		CategoryName: cat.Name,
+		Position:     index,
+		Category:     &clone,
+		Encrypted:    project.Encrypted,
+	}
+}
+
+// TrashProject builds the trash entry for a whole project.
+func TrashProject(project domain.Project) TrashItem {
+	clone := project.Clone()
+	return TrashItem{
+		Kind:        TrashKindProject,
+		ProjectID:   project.ID,
+		ProjectName: project.Name,
+		Project:     &clone,
+		Encrypted:   project.Encrypted,
+	}
+}
+
+// Add stores an item, filling in its ID and deletion time.
+func (t *Trash) Add(item TrashItem) (TrashItem, error) {
+	id, err := domain.NewID()
+	if err != nil {
+		return TrashItem{}, err
+	}
+	item.ID = id
+	item.DeletedAt = domain.NowTimestamp()
+	data, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return TrashItem{}, err
+	}
+	if item.Encrypted {
+		if t.Keys == nil {
+			return TrashItem{}, ErrLocked
+		}
+		if data, err = t.Keys.seal(item.ID, nil, data); err != nil {
+			return TrashItem{}, err
+		}
+	}
+	if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+		return TrashItem{}, err
+	}
+	if err := fsutil.WriteFileAtomic(t.itemPath(id), data, 0o644); err != nil {
+		return TrashItem{}, err
+	}
+	return item, nil
+}
+
+// List returns the items in the trash, most recently deleted first.
+// Unreadable entries, including encrypted ones without a key, are skipped.
+func (t *Trash) List() ([]TrashItem, error) {
+	entries, err := os.ReadDir(t.Dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []TrashItem{}, nil
+		}
+		return nil, err
+	}
+	items := make([]TrashItem, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		item, err := t.load(filepath.Join(t.Dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt > items[j].DeletedAt
+	})
+	return items, nil
+}
+
+// Get finds an item by ID or by a unique ID prefix of at least four
+// characters.
+func (t *Trash) Get(selector string) (TrashItem, error) {
+	selector = strings.ToLower(strings.TrimSpace(selector))
+	items, err := t.List()
+	if err != nil {
+		return TrashItem{}, err
+	}
+	var match *TrashItem
+	for i := range items {
+		id := strings.ToLower(items[i].ID)
+		if id == selector {
+			return items[i], nil
+		}
+		if len(selector) >= 4 && strings.HasPrefix(id, selector) {
+			if match != nil {
+				return TrashItem{}, fmt.Errorf("trash item %q is ambiguous", selector)
+			}
+			match = &items[i]
+		}
+	}
+	if match == nil {
+		return TrashItem{}, ErrTrashItemNotFound
+	}
+	return *match, nil
+}
+
+func (t *Trash) Remove(id string) error {
+	if err := os.Remove(t.itemPath(id)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ErrTrashItemNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Purge deletes items older than maxAge for good and returns how many were
+// removed. A maxAge of zero or less empties the whole trash.
+func (t *Trash) Purge(maxAge time.Duration, now time.Time) (int, error) {
+	items, err := t.List()
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, item := range items {
+		if maxAge > 0 {
+			deletedAt, err := time.Parse(time.RFC3339, item.DeletedAt)
+			if err == nil && now.Sub(deletedAt) < maxAge {
+				continue
+			}
+		}
+		if err := t.Remove(item.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// PurgeExpired deletes items kept longer than retention, as done whenever
+// the trash is opened. A retention of zero keeps everything until the
+// trash is purged by hand.
+func (t *Trash) PurgeExpired(retention time.Duration, now time.Time) (int, error) {
+	if retention <= 0 {
+		return 0, nil
+	}
+	return t.Purge(retention, now)
+}
+
+// Restore puts an item back where it was deleted from and removes it from
+// the trash. An item that is already back, for instance because the
+// deletion was undone, is dropped from the trash with ErrAlreadyRestored.
+func (t *Trash) Restore(repo ProjectRepository, item TrashItem) error {
+	err := t.restore(repo, item)
+	if err != nil && !errors.Is(err, ErrAlreadyRestored) {
+		return err
+	}
+	if removeErr := t.Remove(item.ID); removeErr != nil {
+		return removeErr
+	}
+	return err
+}
+
+func (t *Trash) restore(repo ProjectRepository, item TrashItem) error {
+	if item.Kind == TrashKindProject {
+		if item.Project == nil {
+			return ErrTrashItemNotFound
+		}
+		if existing, err := repo.LoadProject(item.ProjectID); err == nil && existing.ID == item.ProjectID {
+			return ErrAlreadyRestored
+		}
+		project := item.Project.Clone()
+		project.Revision = 0
+		return repo.SaveProject(&project)
+	}
+	project, err := repo.LoadProject(item.ProjectID)
+	if err != nil {
+		return err
+	}
+	if project.ID != item.ProjectID {
+		return ErrProjectNotFound
+	}
+	if err := RestoreInto(&project, item); err != nil {
+		return err
+	}
+	return repo.SaveProject(&project)
+}
+
+// RestoreInto puts a deleted task or category back into project. Tasks
+// return to their parent or category when those still exist, and to the
+// first category otherwise.
+func RestoreInto(project *domain.Project, item TrashItem) error {
+	switch item.Kind {
+	case TrashKindCategory:
+		if item.Category == nil {
+			return ErrTrashItemNotFound
+		}
+		for _, cat := range project.Categories {
+			if cat.ID == item.Category.ID {
+				return ErrAlreadyRestored
+			}
+		}
+		project.InsertCategory(item.Position, *item.Category)
+		return nil
+	case TrashKindTask:
+		if item.Task == nil {
+			return ErrTrashItemNotFound
+		}
+		if _, _, exists := project.FindTask(item.Task.ID); exists {
+			return ErrAlreadyRestored
+		}
+		if item.ParentID != "" {
+			if parent, _, ok := project.FindTask(item.ParentID); ok {
+				parent.InsertSubtask(item.Position, *item.Task)
+				return nil
+			}
+		}
+		for cIdx := range project.Categories {
+			if project.Categories[cIdx].ID == item.CategoryID {
+				project.Categories[cIdx].InsertTask(item.Position, *item.Task)
+				return nil
+			}
+		}
+		if len(project.Categories) == 0 {
+			return errors.New("project has no category to restore into")
+		}
+		project.Categories[0].InsertTask(0, *item.Task)
+		return nil
+	}
+	return fmt.Errorf("unknown trash item kind %q", item.Kind)
+}
+
+func (t *Trash) load(path string) (TrashItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TrashItem{}, err
+	}
+	env, encrypted := readEnvelope(data)
+	if encrypted {
+		if t.Keys == nil {
+			return TrashItem{}, ErrLocked
+		}
+		if data, err = t.Keys.open(env, env.Data, false); err != nil {
+			return TrashItem{}, err
+		}
+	}
+	var item TrashItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		return TrashItem{}, err
+	}
+	if encrypted {
+		item.Encrypted = true
+		if item.Project != nil {
+			item.Project.Encrypted = true
+		}
+	}
+	return item, nil
+}
+
+func (t *Trash) itemPath(id string) string {
+	return filepath.Join(t.Dir, id+".json")
+}
@@ -0,0 +1,157 @@
+package data
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"phasionary/internal/domain"
+)
+
+func newTrashTestStore(t *testing.T) *Store {
+	t.Helper()
+	store := NewStore(filepath.Join(t.TempDir(), "projects"))
+	require.NoError(t, store.Ensure())
+	return store
+}
+
+func TestTrash_RestoreTaskToPosition(t *testing.T) {
+	store := newTrashTestStore(t)
+	trash := store.Trash()
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(project.Categories[0].Tasks), 2)
+	deleted := project.Categories[0].Tasks[1]
+
+	item, ok := TrashTask(project, deleted.ID)
+	require.True(t, ok)
+	_, err = trash.Add(item)
+	require.NoError(t, err)
+	project.RemoveTaskByID(deleted.ID)
+	require.NoError(t, store.SaveProject(&project))
+
+	items, err := trash.List()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, deleted.Title, items[0].Title())
+	assert.Equal(t, 1, items[0].Position)
+	assert.NotEmpty(t, items[0].DeletedAt)
+
+	require.NoError(t, trash.Restore(store, items[0]))
+	restored, err := store.LoadProject(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, deleted.ID, restored.Categories[0].Tasks[1].ID)
+
+	items, err = trash.List()
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestTrash_RestoreSubtaskUnderParent(t *testing.T) {
+	store := newTrashTestStore(t)
+	trash := store.Trash()
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	sub, err := domain.NewTask("Subtask")
+	require.NoError(t, err)
+	parent := &project.Categories[0].Tasks[0]
+	parent.InsertSubtask(0, sub)
+	require.NoError(t, store.SaveProject(&project))
+
+	item, ok := TrashTask(project, sub.ID)
+	require.True(t, ok)
+	assert.Equal(t, parent.ID, item.ParentID)
+	item, err = trash.Add(item)
+	require.NoError(t, err)
+	project.RemoveTaskByID(sub.ID)
+	require.NoError(t, store.SaveProject(&project))
+
+	require.NoError(t, trash.Restore(store, item))
+	restored, err := store.LoadProject(project.ID)
+	require.NoError(t, err)
+	require.Len(t, restored.Categories[0].Tasks[0].Subtasks, 1)
+	assert.Equal(t, sub.ID, restored.Categories[0].Tasks[0].Subtasks[0].ID)
+}
+
+func TestTrash_RestoreCategory(t *testing.T) {
+	store := newTrashTestStore(t)
+	trash := store.Trash()
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	deleted := project.Categories[1]
+
+	item, err := trash.Add(TrashCategory(project, 1))
+	require.NoError(t, err)
+	require.NoError(t, project.RemoveCategory(1))
+	require.NoError(t, store.SaveProject(&project))
+
+	require.NoError(t, trash.Restore(store, item))
+	restored, err := store.LoadProject(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, deleted.ID, restored.Categories[1].ID)
+	assert.Len(t, restored.Categories[1].Tasks, len(deleted.Tasks))
+
+	_, err = trash.Add(item)
+	require.NoError(t, err)
+	items, err := trash.List()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.ErrorIs(t, trash.Restore(store, items[0]), ErrAlreadyRestored)
+	items, err = trash.List()
+	require.NoError(t, err)
+	assert.Empty(t, items, "an item that is already back leaves the trash")
+}
+
+func TestTrash_DeleteAndRestoreProject(t *testing.T) {
+	store := newTrashTestStore(t)
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+
+	require.NoError(t, store.DeleteProject(project.ID))
+	_, err = store.LoadProject(project.ID)
+	assert.ErrorIs(t, err, ErrProjectNotFound)
+
+	items, err := store.Trash().List()
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, TrashKindProject, items[0].Kind)
+
+	item, err := store.Trash().Get(items[0].ID[:8])
+	require.NoError(t, err)
+	require.NoError(t, store.Trash().Restore(store, item))
+	restored, err := store.LoadProject(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Work", restored.Name)
+}
+
+func TestTrash_Purge(t *testing.T) {
+	store := newTrashTestStore(t)
+	trash := store.Trash()
+	project, err := store.CreateProject("Work")
+	require.NoError(t, err)
+	_, err = trash.Add(TrashCategory(project, 0))
+	require.NoError(t, err)
+
+	purged, err := trash.Purge(24*time.Hour, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	// A retention of zero keeps items however old they are.
+	purged, err = trash.PurgeExpired(0, time.Now().Add(365*24*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	items, err := trash.List()
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	purged, err = trash.PurgeExpired(24*time.Hour, time.Now().Add(48*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	items, err = trash.List()
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
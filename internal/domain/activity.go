@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	ActivityCreated      = "created"
+	ActivityDeleted      = "deleted"
+	ActivityRenamed      = "renamed"
+	ActivityStatus       = "status"
+	ActivityPriority     = "priority"
+	ActivityMoved        = "moved"
+	ActivityEstimate     = "estimate"
+	ActivityDeadline     = "deadline"
+	ActivitySection      = "section"
+	ActivityTags         = "tags"
+	ActivityDescription  = "description"
+	ActivityNotes        = "notes"
+	ActivityDependencies = "dependencies"
+	ActivityRecurrence   = "recurrence"
+	ActivityTimer        = "timer"
+	ActivityArchived     = "archived"
+	ActivityRestored     = "restored"
+)
+
+// Activity is one change to a task between two versions of a project. From
+// and To hold the old and new value when there is a short one to show: the
+// status, priority, title or location. Title is the task's title after the
+// change, or before it for deleted tasks.
+type Activity struct {
+	Kind   string
+	TaskID string
+	Title  string
+	From   string
+	To     string
+}
+
+// ProjectActivity turns the differences between two versions of a project
+// into task activity, one entry per changed field.
+func ProjectActivity(before, after Project) []Activity {
+	oldIndex := indexTasks(&before)
+	newIndex := indexTasks(&after)
+	wasArchived, isArchived := before.archivedIDs(), after.archivedIDs()
+	var activity []Activity
+
+	for _, change := range DiffProjects(before, after) {
+		switch {
+		case change.Kind == ChangeAdded && wasArchived[change.TaskID]:
+			activity = append(activity, Activity{
+				Kind:   ActivityRestored,
+				TaskID: change.TaskID,
+				Title:  change.Title,
+				To:     taskLocation(&after, newIndex, change.TaskID),
+			})
+		case change.Kind == ChangeRemoved && isArchived[change.TaskID]:
+			activity = append(activity, Activity{
+				Kind:   ActivityArchived,
+				TaskID: change.TaskID,
+				Title:  change.Title,
+				From:   taskLocation(&before, oldIndex, change.TaskID),
+			})
+		case change.Kind == ChangeAdded:
+			activity = append(activity, Activity{
+				Kind:   ActivityCreated,
+				TaskID: change.TaskID,
+				Title:  change.Title,
+				To:     taskLocation(&after, newIndex, change.TaskID),
+			})
+		case change.Kind == ChangeRemoved:
+			activity = append(activity, Activity{
+				Kind:   ActivityDeleted,
+				TaskID: change.TaskID,
+				Title:  change.Title,
+				From:   taskLocation(&before, oldIndex, change.TaskID),
+			})
+		case change.Kind == ChangeChanged:
+			old, cur := oldIndex[change.TaskID].task, newIndex[change.TaskID].task
+			for _, field := range change.Fields {
+				entry, ok := fieldActivity(field, old, cur)
+				if !ok {
+					continue
+				}
+				if field == "category" || field == "parent" {
+					entry.From = taskLocation(&before, oldIndex, change.TaskID)
+					entry.To = taskLocation(&after, newIndex, change.TaskID)
+				}
+				entry.TaskID = change.TaskID
+				entry.Title = change.Title
+				activity = append(activity, entry)
+			}
+		}
+	}
+	return activity
+}
+
+func fieldActivity(field string, old, cur *Task) (Activity, bool) {
+	switch field {
+	case "title":
+		return Activity{Kind: ActivityRenamed, From: old.Title, To: cur.Title}, true
+	case "status":
+		return Activity{Kind: ActivityStatus, From: old.Status, To: cur.Status}, true
+	case "priority":
+		return Activity{Kind: ActivityPriority, From: old.Priority, To: cur.Priority}, true
+	case "estimate":
+		return Activity{Kind: ActivityEstimate, From: formatEstimate(old.EstimateMinutes), To: formatEstimate(cur.EstimateMinutes)}, true
+	case "deadline":
+		return Activity{Kind: ActivityDeadline, From: old.Deadline, To: cur.Deadline}, true
+	case "section":
+		return Activity{Kind: ActivitySection, From: old.SectionName(), To: cur.SectionName()}, true
+	case "tags":
+		return Activity{Kind: ActivityTags, From: strings.Join(old.Tags, " "), To: strings.Join(cur.Tags, " ")}, true
+	case "description":
+		return Activity{Kind: ActivityDescription}, true
+	case "notes":
+		return Activity{Kind: ActivityNotes}, true
+	case "blocked_by":
+		return Activity{Kind: ActivityDependencies}, true
+	case "recurrence":
+		return Activity{Kind: ActivityRecurrence, From: formatRecurrence(old.Recurrence), To: formatRecurrence(cur.Recurrence)}, true
+	case "category", "parent":
+		return Activity{Kind: ActivityMoved}, true
+	case "time":
+		// Only starting and stopping the timer is worth a line; edits to
+		// past entries are not.
+		was, is := old.IsTimerRunning(), cur.IsTimerRunning()
+		if was == is {
+			return Activity{}, false
+		}
+		if is {
+			return Activity{Kind: ActivityTimer, To: "started"}, true
+		}
+		return Activity{Kind: ActivityTimer, To: "stopped"}, true
+	}
+	return Activity{}, false
+}
+
+// taskLocation names where a task sits: its category, followed by its
+// parent for subtasks.
+func taskLocation(p *Project, index map[string]taskPlacement, id string) string {
+	placement, ok := index[id]
+	if !ok {
+		return ""
+	}
+	location := ""
+	if cat, _ := p.categoryByID(placement.categoryID); cat != nil {
+		location = cat.Name
+	}
+	if parent, ok := index[placement.parentID]; ok && placement.parentID != "" {
+		location += " / " + parent.task.Title
+	}
+	return location
+}
+
+func formatEstimate(minutes int) string {
+	if minutes == 0 {
+		return ""
+	}
+	return FormatDuration(time.Duration(minutes) * time.Minute)
+}
+
+func formatRecurrence(r *Recurrence) string {
+	if r == nil {
+		return ""
+	}
+	return r.String()
+}
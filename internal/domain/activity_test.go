@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectActivity(t *testing.T) {
+	before := mergeBase()
+	after := before.Clone()
+	task := &after.Categories[0].Tasks[0]
+	require.NoError(t, task.SetStatus(StatusInProgress))
+	task.Priority = PriorityHigh
+	task.EstimateMinutes = 90
+	moved, _ := after.RemoveTaskByID("b1")
+	after.Categories[1].Tasks = append(after.Categories[1].Tasks, moved)
+	after.RemoveTaskByID("c")
+	after.Categories[1].Tasks[0].Title = "D renamed"
+
+	activity := ProjectActivity(before, after)
+	assert.Equal(t, []Activity{
+		{Kind: ActivityStatus, TaskID: "a", Title: "A", From: "", To: StatusInProgress},
+		{Kind: ActivityPriority, TaskID: "a", Title: "A", From: "", To: PriorityHigh},
+		{Kind: ActivityEstimate, TaskID: "a", Title: "A", From: "", To: "1h30m"},
+		{Kind: ActivityRenamed, TaskID: "d", Title: "D renamed", From: "D", To: "D renamed"},
+		{Kind: ActivityMoved, TaskID: "b1", Title: "B1", From: "Feature / B", To: "Fix"},
+		{Kind: ActivityDeleted, TaskID: "c", Title: "C", From: "Feature"},
+	}, activity)
+}
+
+func TestProjectActivity_CreatedAndTimer(t *testing.T) {
+	before := mergeBase()
+	after := before.Clone()
+	after.Categories[1].Tasks = append(after.Categories[1].Tasks, Task{ID: "new", Title: "New"})
+	require.NoError(t, after.Categories[0].Tasks[0].StartTimer(time.Now()))
+
+	activity := ProjectActivity(before, after)
+	assert.Equal(t, []Activity{
+		{Kind: ActivityTimer, TaskID: "a", Title: "A", To: "started"},
+		{Kind: ActivityCreated, TaskID: "new", Title: "New", To: "Fix"},
+	}, activity)
+}
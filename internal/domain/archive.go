@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrArchivedTaskNotFound = errors.New("archived task not found")
+
+// ArchivedTask is a finished task taken off the board, with the category it
+// came from so it can be put back.
+type ArchivedTask struct {
+	Task         Task   `json:"task"`
+	CategoryID   string `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	ArchivedAt   string `json:"archived_at"`
+}
+
+// FinishedAt is when a done task was completed, or last changed for
+// cancelled tasks and those without a completion date.
+func (t *Task) FinishedAt() string {
+	if t.CompletionDate != "" {
+		return t.CompletionDate
+	}
+	return t.UpdatedAt
+}
+
+// ArchiveFinished moves completed and cancelled top-level tasks, with their
+// subtasks, into the project's archive. Only tasks finished before cutoff
+// are moved, or all of them when cutoff is zero. Links to archived tasks
+// are dropped, since done tasks no longer block anything.
+func (p *Project) ArchiveFinished(cutoff time.Time) []ArchivedTask {
+	now := NowTimestamp()
+	var archived []ArchivedTask
+	for cIdx := range p.Categories {
+		cat := &p.Categories[cIdx]
+		kept := make([]Task, 0, len(cat.Tasks))
+		for _, task := range cat.Tasks {
+			if !task.IsDone() || !finishedBefore(task, cutoff) {
+				kept = append(kept, task)
+				continue
+			}
+			archived = append(archived, ArchivedTask{
+				Task:         task,
+				CategoryID:   cat.ID,
+				CategoryName: cat.Name,
+				ArchivedAt:   now,
+			})
+		}
+		if len(kept) != len(cat.Tasks) {
+			cat.Tasks = kept
+			cat.UpdatedAt = now
+		}
+	}
+	if len(archived) > 0 {
+		p.Archive = append(p.Archive, archived...)
+		p.PruneDependencies()
+	}
+	return archived
+}
+
+func finishedBefore(task Task, cutoff time.Time) bool {
+	if cutoff.IsZero() {
+		return true
+	}
+	finished, err := time.Parse(time.RFC3339, task.FinishedAt())
+	return err == nil && finished.Before(cutoff)
+}
+
+// RestoreArchived puts an archived task back at the end of its category,
+// found by ID or else by name, and recreates the category when it is gone.
+func (p *Project) RestoreArchived(taskID string) (Task, error) {
+	idx := -1
+	for i := range p.Archive {
+		if p.Archive[i].Task.ID == taskID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return Task{}, ErrArchivedTaskNotFound
+	}
+	entry := p.Archive[idx]
+
+	cat, _ := p.categoryByID(entry.CategoryID)
+	if cat == nil {
+		for i := range p.Categories {
+			if NormalizeName(p.Categories[i].Name) == NormalizeName(entry.CategoryName) {
+				cat = &p.Categories[i]
+				break
+			}
+		}
+	}
+	if cat == nil {
+		restored, err := NewCategory(entry.CategoryName)
+		if err != nil {
+			return Task{}, err
+		}
+		p.AddCategory(restored)
+		cat = &p.Categories[len(p.Categories)-1]
+	}
+
+	p.Archive = append(p.Archive[:idx], p.Archive[idx+1:]...)
+	if len(p.Archive) == 0 {
+		p.Archive = nil
+	}
+	cat.AddTask(entry.Task)
+	p.PruneDependencies()
+	return entry.Task, nil
+}
+
+// archivedIDs returns the IDs of every archived task and subtask.
+func (p *Project) archivedIDs() map[string]bool {
+	ids := make(map[string]bool)
+	for i := range p.Archive {
+		WalkTasks([]Task{p.Archive[i].Task}, func(task *Task, _ int) {
+			ids[task.ID] = true
+		})
+	}
+	return ids
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveFinished(t *testing.T) {
+	project := mergeBase()
+	now := time.Now()
+	old := now.AddDate(0, 0, -40).UTC().Format(time.RFC3339)
+	project.Categories[0].Tasks[0].Status = StatusCompleted
+	project.Categories[0].Tasks[0].CompletionDate = old
+	project.Categories[0].Tasks[1].Status = StatusCancelled
+	project.Categories[0].Tasks[1].UpdatedAt = now.UTC().Format(time.RFC3339)
+	project.Categories[0].Tasks[2].BlockedBy = []string{"a"}
+
+	// Only tasks finished before the cutoff go.
+	archived := project.ArchiveFinished(now.AddDate(0, 0, -30))
+	require.Len(t, archived, 1)
+	assert.Equal(t, "a", archived[0].Task.ID)
+	assert.Equal(t, "c1", archived[0].CategoryID)
+	assert.Equal(t, []string{"b", "c"}, taskIDs(project.Categories[0].Tasks))
+	assert.Empty(t, project.Categories[0].Tasks[1].BlockedBy)
+
+	// Without a cutoff every finished task goes, subtasks included.
+	archived = project.ArchiveFinished(time.Time{})
+	require.Len(t, archived, 1)
+	assert.Equal(t, "b", archived[0].Task.ID)
+	assert.Len(t, archived[0].Task.Subtasks, 1)
+	assert.Len(t, project.Archive, 2)
+	_, _, ok := project.FindTask("b1")
+	assert.False(t, ok)
+
+	clone := project.Clone()
+	clone.Archive[0].Task.Title = "changed"
+	assert.Equal(t, "A", project.Archive[0].Task.Title)
+}
+
+func TestRestoreArchived(t *testing.T) {
+	project := mergeBase()
+	project.Categories[0].Tasks[0].Status = StatusCompleted
+	project.Categories[1].Tasks[0].Status = StatusCompleted
+	project.ArchiveFinished(time.Time{})
+	require.Len(t, project.Archive, 2)
+
+	// A renamed category is still found by ID.
+	project.Categories[0].Name = "Features"
+	task, err := project.RestoreArchived("a")
+	require.NoError(t, err)
+	assert.Equal(t, "a", task.ID)
+	assert.Equal(t, []string{"b", "c", "a"}, taskIDs(project.Categories[0].Tasks))
+
+	// A deleted category is recreated.
+	require.NoError(t, project.RemoveCategory(1))
+	_, err = project.RestoreArchived("d")
+	require.NoError(t, err)
+	require.Len(t, project.Categories, 2)
+	assert.Equal(t, "Fix", project.Categories[1].Name)
+	assert.Equal(t, []string{"d"}, taskIDs(project.Categories[1].Tasks))
+	assert.Nil(t, project.Archive)
+
+	_, err = project.RestoreArchived("d")
+	assert.ErrorIs(t, err, ErrArchivedTaskNotFound)
+}
+
+func TestArchive_ActivityAndMerge(t *testing.T) {
+	base := mergeBase()
+	base.Categories[0].Tasks[0].Status = StatusCompleted
+
+	ours := base.Clone()
+	ours.ArchiveFinished(time.Time{})
+	assert.Equal(t, []Activity{
+		{Kind: ActivityArchived, TaskID: "a", Title: "A", From: "Feature"},
+	}, ProjectActivity(base, ours))
+
+	theirs := base.Clone()
+	theirs.Revision = 4
+	theirs.Categories[1].Tasks[0].Title = "D edited"
+	merged := MergeProjects(base, ours, theirs)
+	assert.Equal(t, []string{"b", "c"}, taskIDs(merged.Categories[0].Tasks))
+	require.Len(t, merged.Archive, 1)
+	assert.Equal(t, "D edited", merged.Categories[1].Tasks[0].Title)
+
+	restored := merged.Clone()
+	_, err := restored.RestoreArchived("a")
+	require.NoError(t, err)
+	assert.Equal(t, []Activity{
+		{Kind: ActivityRestored, TaskID: "a", Title: "A", To: "Feature"},
+	}, ProjectActivity(merged, restored))
+	merged = MergeProjects(merged, restored, merged)
+	assert.Empty(t, merged.Archive)
+	_, _, ok := merged.FindTask("a")
+	assert.True(t, ok)
+}
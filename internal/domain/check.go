@@ -0,0 +1,239 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Issue is a broken invariant found in a project. Fixable issues can be
+// repaired without guessing at what the user meant.
+type Issue struct {
+	Message string
+	Fixable bool
+}
+
+// Check reports the invariants the project breaks: unknown statuses,
+// priorities or sections, missing or duplicate IDs, category names that
+// differ only by case, malformed timestamps and dangling dependencies.
+func (p *Project) Check() []Issue {
+	issues, _ := p.inspect(false)
+	return issues
+}
+
+// Repair fixes the fixable issues Check would report and returns them:
+// unknown statuses, priorities and sections fall back to their defaults,
+// malformed timestamps are reset, clashing category names get a number and
+// dangling dependencies are dropped. Duplicate IDs are replaced on the later
+// occurrence, so dependencies keep pointing at the first one.
+func (p *Project) Repair() ([]Issue, error) {
+	issues, err := p.inspect(true)
+	if err != nil {
+		return nil, err
+	}
+	fixed := make([]Issue, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Fixable {
+			fixed = append(fixed, issue)
+		}
+	}
+	return fixed, nil
+}
+
+func (p *Project) inspect(fix bool) ([]Issue, error) {
+	var issues []Issue
+	report := func(fixable bool, format string, args ...any) {
+		issues = append(issues, Issue{Message: fmt.Sprintf(format, args...), Fixable: fixable})
+	}
+	now := NowTimestamp()
+
+	if p.Name == "" {
+		report(false, "project has no name")
+	}
+	if !validTimestamp(p.CreatedAt) {
+		report(true, "project created_at %q is not an RFC 3339 timestamp", p.CreatedAt)
+		if fix {
+			p.CreatedAt = now
+		}
+	}
+	if !validTimestamp(p.UpdatedAt) {
+		report(true, "project updated_at %q is not an RFC 3339 timestamp", p.UpdatedAt)
+		if fix {
+			p.UpdatedAt = now
+		}
+	}
+
+	categoryIDs := make(map[string]bool)
+	categoryNames := make(map[string]string)
+	for cIdx := range p.Categories {
+		cat := &p.Categories[cIdx]
+		label := fmt.Sprintf("category %q", cat.Name)
+		if cat.Name == "" {
+			report(false, "category %s has no name", cat.ID)
+		} else if first, ok := categoryNames[NormalizeName(cat.Name)]; ok {
+			report(true, "%s has the same name as %q", label, first)
+			if fix {
+				cat.Name = uniqueCategoryName(p, cat.Name)
+			}
+		}
+		categoryNames[NormalizeName(cat.Name)] = cat.Name
+		if err := fixID(&cat.ID, categoryIDs, fix, func(problem string) {
+			report(true, "%s %s", label, problem)
+		}); err != nil {
+			return nil, err
+		}
+		if !validTimestamp(cat.CreatedAt) {
+			report(true, "%s created_at %q is not an RFC 3339 timestamp", label, cat.CreatedAt)
+			if fix {
+				cat.CreatedAt = now
+			}
+		}
+		if cat.UpdatedAt != "" && !validTimestamp(cat.UpdatedAt) {
+			report(true, "%s updated_at %q is not an RFC 3339 timestamp", label, cat.UpdatedAt)
+			if fix {
+				cat.UpdatedAt = now
+			}
+		}
+	}
+
+	taskIDs := make(map[string]bool)
+	var walkErr error
+	for cIdx := range p.Categories {
+		WalkTasks(p.Categories[cIdx].Tasks, func(task *Task, _ int) {
+			if walkErr != nil {
+				return
+			}
+			label := fmt.Sprintf("task %q", task.Title)
+			if task.Title == "" {
+				report(false, "task %s has no title", task.ID)
+			}
+			walkErr = fixID(&task.ID, taskIDs, fix, func(problem string) {
+				report(true, "%s %s", label, problem)
+			})
+			inspectTask(task, label, now, fix, report)
+		})
+		if walkErr != nil {
+			return nil, walkErr
+		}
+	}
+
+	for cIdx := range p.Categories {
+		WalkTasks(p.Categories[cIdx].Tasks, func(task *Task, _ int) {
+			var kept []string
+			for _, id := range task.BlockedBy {
+				switch {
+				case id == task.ID:
+					report(true, "task %q is blocked by itself", task.Title)
+				case !taskIDs[id]:
+					report(true, "task %q is blocked by missing task %s", task.Title, id)
+				default:
+					kept = append(kept, id)
+				}
+			}
+			if fix && len(kept) != len(task.BlockedBy) {
+				task.BlockedBy = kept
+			}
+		})
+	}
+	return issues, nil
+}
+
+func inspectTask(task *Task, label, now string, fix bool, report func(bool, string, ...any)) {
+	if err := ValidateStatus(task.Status); err != nil {
+		report(true, "%s has unknown status %q", label, task.Status)
+		if fix {
+			task.Status = StatusTodo
+			task.CompletionDate = ""
+		}
+	}
+	if err := ValidatePriority(task.Priority); err != nil {
+		report(true, "%s has unknown priority %q", label, task.Priority)
+		if fix {
+			task.Priority = ""
+		}
+	}
+	if err := ValidateSection(task.Section); err != nil {
+		report(true, "%s has unknown section %q", label, task.Section)
+		if fix {
+			task.Section = SectionCurrent
+		}
+	}
+	if err := ValidateDeadline(task.Deadline); err != nil {
+		report(false, "%s has deadline %q, expected YYYY-MM-DD", label, task.Deadline)
+	}
+	if task.Recurrence != nil {
+		if err := ValidateRecurrence(*task.Recurrence); err != nil {
+			report(false, "%s has an invalid recurrence: %v", label, err)
+		}
+	}
+	if !validTimestamp(task.CreatedAt) {
+		report(true, "%s created_at %q is not an RFC 3339 timestamp", label, task.CreatedAt)
+		if fix {
+			task.CreatedAt = now
+		}
+	}
+	if !validTimestamp(task.UpdatedAt) {
+		report(true, "%s updated_at %q is not an RFC 3339 timestamp", label, task.UpdatedAt)
+		if fix {
+			task.UpdatedAt = now
+		}
+	}
+	if task.CompletionDate != "" && !validTimestamp(task.CompletionDate) {
+		report(true, "%s completion_date %q is not an RFC 3339 timestamp", label, task.CompletionDate)
+		if fix {
+			task.CompletionDate = task.UpdatedAt
+		}
+	}
+	for _, entry := range task.TimeEntries {
+		if !validTimestamp(entry.Start) || (entry.End != "" && !validTimestamp(entry.End)) {
+			report(false, "%s has a time entry with a malformed timestamp", label)
+			break
+		}
+	}
+}
+
+// fixID reports an empty or already used ID and, when fixing, replaces it
+// with a fresh one, which is then marked as seen.
+func fixID(id *string, seen map[string]bool, fix bool, report func(problem string)) error {
+	switch {
+	case *id == "":
+		report("has no ID")
+	case seen[*id]:
+		report(fmt.Sprintf("reuses ID %s", *id))
+	default:
+		seen[*id] = true
+		return nil
+	}
+	if !fix {
+		return nil
+	}
+	newID, err := NewID()
+	if err != nil {
+		return err
+	}
+	*id = newID
+	seen[newID] = true
+	return nil
+}
+
+// uniqueCategoryName appends a number to name until no category of the
+// project uses it.
+func uniqueCategoryName(p *Project, name string) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)", name, n)
+		taken := false
+		for _, cat := range p.Categories {
+			if NormalizeName(cat.Name) == NormalizeName(candidate) {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			return candidate
+		}
+	}
+}
+
+func validTimestamp(value string) bool {
+	_, err := time.Parse(time.RFC3339, value)
+	return err == nil
+}
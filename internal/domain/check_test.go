@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkedProject is a valid project that Check reports nothing for.
+func checkedProject() Project {
+	const ts = "2024-01-01T00:00:00Z"
+	task := func(id, title string) Task {
+		return Task{ID: id, Title: title, Status: StatusTodo, CreatedAt: ts, UpdatedAt: ts}
+	}
+	return Project{
+		ID:        "p",
+		Name:      "Project",
+		CreatedAt: ts,
+		UpdatedAt: ts,
+		Categories: []Category{
+			{ID: "c1", Name: "Feature", CreatedAt: ts, Tasks: []Task{task("a", "A"), task("b", "B")}},
+			{ID: "c2", Name: "Fix", CreatedAt: ts, Tasks: []Task{task("c", "C")}},
+		},
+	}
+}
+
+func TestCheck_ValidProject(t *testing.T) {
+	p := checkedProject()
+	assert.Empty(t, p.Check())
+}
+
+func TestCheck_ReportsBrokenInvariants(t *testing.T) {
+	p := checkedProject()
+	p.Categories[1].Name = " feature"
+	p.Categories[0].Tasks[0].Status = "done"
+	p.Categories[0].Tasks[0].Priority = "urgent"
+	p.Categories[0].Tasks[1].ID = "a"
+	p.Categories[0].Tasks[1].BlockedBy = []string{"gone"}
+	p.Categories[1].Tasks[0].Deadline = "tomorrow"
+	p.Categories[1].Tasks[0].UpdatedAt = "yesterday"
+
+	assert.Equal(t, []Issue{
+		{Message: `category " feature" has the same name as "Feature"`, Fixable: true},
+		{Message: `task "A" has unknown status "done"`, Fixable: true},
+		{Message: `task "A" has unknown priority "urgent"`, Fixable: true},
+		{Message: `task "B" reuses ID a`, Fixable: true},
+		{Message: `task "C" has deadline "tomorrow", expected YYYY-MM-DD`},
+		{Message: `task "C" updated_at "yesterday" is not an RFC 3339 timestamp`, Fixable: true},
+		{Message: `task "B" is blocked by missing task gone`, Fixable: true},
+	}, p.Check())
+}
+
+func TestRepair(t *testing.T) {
+	p := checkedProject()
+	p.Categories[1].Name = "FEATURE"
+	p.Categories[0].Tasks[0].Status = ""
+	p.Categories[0].Tasks[0].BlockedBy = []string{"a", "c"}
+	p.Categories[0].Tasks[1].ID = "a"
+	p.Categories[1].Tasks[0].Deadline = "tomorrow"
+
+	fixed, err := p.Repair()
+	require.NoError(t, err)
+	assert.Len(t, fixed, 4)
+
+	assert.Equal(t, "FEATURE (2)", p.Categories[1].Name)
+	first := p.Categories[0].Tasks[0]
+	assert.Equal(t, StatusTodo, first.Status)
+	assert.Equal(t, []string{"c"}, first.BlockedBy)
+	assert.Equal(t, "a", first.ID)
+	assert.NotEqual(t, "a", p.Categories[0].Tasks[1].ID)
+	assert.NotEmpty(t, p.Categories[0].Tasks[1].ID)
+
+	// Only the deadline, which cannot be guessed, is left.
+	assert.Equal(t, []Issue{{Message: `task "C" has deadline "tomorrow", expected YYYY-MM-DD`}}, p.Check())
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is the storage format for task deadlines.
+const DateLayout = "2006-01-02"
+
+// DueSoonDays is how many days ahead of a deadline a task counts as due soon.
+const DueSoonDays = 2
+
+var relativeDateRe = regexp.MustCompile(`^\+?(\d+)([dwm])$`)
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+func ValidateDeadline(deadline string) error {
+	if deadline == "" {
+		return nil
+	}
+	if _, err := time.Parse(DateLayout, deadline); err != nil {
+		return errors.New("invalid deadline")
+	}
+	return nil
+}
+
+// ParseDeadline turns user input into a deadline date relative to now.
+// Accepted forms: YYYY-MM-DD, today, tomorrow, weekday names ("fri",
+// "monday"), and offsets such as "+3d", "2w" or "+1m". "none" or an empty
+// string clears the deadline.
+func ParseDeadline(input string, now time.Time) (string, error) {
+	input = strings.TrimSpace(strings.ToLower(input))
+	today := StartOfDay(now)
+	switch input {
+	case "", "none", "clear":
+		return "", nil
+	case "today", "tod":
+		return today.Format(DateLayout), nil
+	case "tomorrow", "tom":
+		return today.AddDate(0, 0, 1).Format(DateLayout), nil
+	}
+	if weekday, ok := weekdayNames[input]; ok {
+		return NextWeekday(today, weekday).Format(DateLayout), nil
+	}
+	if m := relativeDateRe.FindStringSubmatch(input); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return "", err
+		}
+		switch m[2] {
+		case "d":
+			return today.AddDate(0, 0, n).Format(DateLayout), nil
+		case "w":
+			return today.AddDate(0, 0, 7*n).Format(DateLayout), nil
+		case "m":
+			return today.AddDate(0, n, 0).Format(DateLayout), nil
+		}
+	}
+	if t, err := time.ParseInLocation(DateLayout, input, now.Location()); err == nil {
+		return t.Format(DateLayout), nil
+	}
+	return "", fmt.Errorf("invalid date: %s (use YYYY-MM-DD, today, tomorrow, fri, +3d, 2w)", input)
+}
+
+// StartOfDay returns midnight of t's day in t's location.
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// NextWeekday returns the first day on or after from that falls on weekday.
+func NextWeekday(from time.Time, weekday time.Weekday) time.Time {
+	offset := (int(weekday) - int(from.Weekday()) + 7) % 7
+	return from.AddDate(0, 0, offset)
+}
+
+func (t *Task) SetDeadline(deadline string) error {
+	if err := ValidateDeadline(deadline); err != nil {
+		return err
+	}
+	t.Deadline = deadline
+	t.UpdatedAt = NowTimestamp()
+	return nil
+}
+
+// DeadlineIn returns the deadline as midnight in loc.
+func (t *Task) DeadlineIn(loc *time.Location) (time.Time, bool) {
+	if t.Deadline == "" {
+		return time.Time{}, false
+	}
+	d, err := time.ParseInLocation(DateLayout, t.Deadline, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return d, true
+}
+
+// DaysUntilDeadline returns the number of days between today and the
+// deadline; negative values mean the deadline has passed.
+func (t *Task) DaysUntilDeadline(now time.Time) (int, bool) {
+	d, ok := t.DeadlineIn(now.Location())
+	if !ok {
+		return 0, false
+	}
+	// Compare calendar dates, since a day is not 24 hours across a DST change.
+	return int(calendarDate(d).Sub(calendarDate(now)) / (24 * time.Hour)), true
+}
+
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (t *Task) IsOverdue(now time.Time) bool {
+	if t.IsDone() {
+		return false
+	}
+	days, ok := t.DaysUntilDeadline(now)
+	return ok && days < 0
+}
+
+func (t *Task) IsDueSoon(now time.Time) bool {
+	if t.IsDone() {
+		return false
+	}
+	days, ok := t.DaysUntilDeadline(now)
+	return ok && days >= 0 && days <= DueSoonDays
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Wednesday.
+var deadlineNow = time.Date(2026, time.January, 21, 15, 30, 0, 0, time.UTC)
+
+func TestParseDeadline(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"none", ""},
+		{"today", "2026-01-21"},
+		{"tomorrow", "2026-01-22"},
+		{"fri", "2026-01-23"},
+		{"Friday", "2026-01-23"},
+		{"wed", "2026-01-21"},
+		{"mon", "2026-01-26"},
+		{"+3d", "2026-01-24"},
+		{"3d", "2026-01-24"},
+		{"+2w", "2026-02-04"},
+		{"+1m", "2026-02-21"},
+		{"2026-03-01", "2026-03-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDeadline(tt.input, deadlineNow)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	t.Run("rejects unknown input", func(t *testing.T) {
+		_, err := ParseDeadline("someday", deadlineNow)
+		assert.Error(t, err)
+	})
+}
+
+func TestTask_SetDeadline(t *testing.T) {
+	t.Run("sets valid deadline", func(t *testing.T) {
+		task := Task{}
+		require.NoError(t, task.SetDeadline("2026-02-01"))
+		assert.Equal(t, "2026-02-01", task.Deadline)
+		assert.NotEmpty(t, task.UpdatedAt)
+	})
+
+	t.Run("clears deadline", func(t *testing.T) {
+		task := Task{Deadline: "2026-02-01"}
+		require.NoError(t, task.SetDeadline(""))
+		assert.Empty(t, task.Deadline)
+	})
+
+	t.Run("rejects invalid deadline", func(t *testing.T) {
+		task := Task{}
+		assert.Error(t, task.SetDeadline("02/01/2026"))
+	})
+}
+
+func TestTask_IsOverdue(t *testing.T) {
+	t.Run("past deadline is overdue", func(t *testing.T) {
+		task := Task{Status: StatusTodo, Deadline: "2026-01-20"}
+		assert.True(t, task.IsOverdue(deadlineNow))
+	})
+
+	t.Run("deadline today is not overdue", func(t *testing.T) {
+		task := Task{Status: StatusTodo, Deadline: "2026-01-21"}
+		assert.False(t, task.IsOverdue(deadlineNow))
+	})
+
+	t.Run("finished tasks are never overdue", func(t *testing.T) {
+		task := Task{Status: StatusCompleted, Deadline: "2026-01-01"}
+		assert.False(t, task.IsOverdue(deadlineNow))
+	})
+
+	t.Run("no deadline is not overdue", func(t *testing.T) {
+		task := Task{Status: StatusTodo}
+		assert.False(t, task.IsOverdue(deadlineNow))
+	})
+}
+
+func TestTask_IsDueSoon(t *testing.T) {
+	t.Run("deadline today is due soon", func(t *testing.T) {
+		task := Task{Status: StatusTodo, Deadline: "2026-01-21"}
+		assert.True(t, task.IsDueSoon(deadlineNow))
+	})
+
+	t.Run("deadline within window is due soon", func(t *testing.T) {
+		task := Task{Status: StatusInProgress, Deadline: "2026-01-23"}
+		assert.True(t, task.IsDueSoon(deadlineNow))
+	})
+
+	t.Run("deadline beyond window is not due soon", func(t *testing.T) {
+		task := Task{Status: StatusTodo, Deadline: "2026-01-30"}
+		assert.False(t, task.IsDueSoon(deadlineNow))
+	})
+
+	t.Run("overdue is not due soon", func(t *testing.T) {
+		task := Task{Status: StatusTodo, Deadline: "2026-01-19"}
+		assert.False(t, task.IsDueSoon(deadlineNow))
+	})
+}
+
+func TestDaysUntilDeadline_AcrossDST(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	// Clocks go forward on 2026-03-29, so that day is 23 hours long.
+	now := time.Date(2026, time.March, 29, 10, 0, 0, 0, paris)
+	for deadline, want := range map[string]int{
+		"2026-03-28": -1,
+		"2026-03-29": 0,
+		"2026-03-30": 1,
+		"2026-04-05": 7,
+	} {
+		task := Task{Deadline: deadline}
+		days, ok := task.DaysUntilDeadline(now)
+		require.True(t, ok)
+		assert.Equal(t, want, days, deadline)
+	}
+	task := Task{Deadline: "2026-03-28", Status: StatusTodo}
+	assert.True(t, task.IsOverdue(now))
+
+	// And back on 2026-10-25, a 25 hour day.
+	now = time.Date(2026, time.October, 24, 23, 0, 0, 0, paris)
+	task = Task{Deadline: "2026-10-26"}
+	days, _ := task.DaysUntilDeadline(now)
+	assert.Equal(t, 2, days)
+}
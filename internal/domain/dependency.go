@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+)
+
+var (
+	ErrSelfDependency  = errors.New("a task cannot block itself")
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+)
+
+// AddDependency records that the task taskID is blocked by blockerID. Both
+// tasks may live in any category of the project.
+func (p *Project) AddDependency(taskID, blockerID string) error {
+	if taskID == blockerID {
+		return ErrSelfDependency
+	}
+	task, _, ok := p.FindTask(taskID)
+	if !ok {
+		return errors.New("task not found")
+	}
+	if _, _, ok := p.FindTask(blockerID); !ok {
+		return errors.New("blocking task not found")
+	}
+	if slices.Contains(task.BlockedBy, blockerID) {
+		return nil
+	}
+	if p.dependsOn(blockerID, taskID) {
+		return ErrDependencyCycle
+	}
+	task.BlockedBy = append(task.BlockedBy, blockerID)
+	task.UpdatedAt = NowTimestamp()
+	return nil
+}
+
+// RemoveDependency drops the link between taskID and blockerID. It reports
+// whether a link was removed.
+func (p *Project) RemoveDependency(taskID, blockerID string) bool {
+	task, _, ok := p.FindTask(taskID)
+	if !ok {
+		return false
+	}
+	idx := slices.Index(task.BlockedBy, blockerID)
+	if idx < 0 {
+		return false
+	}
+	task.BlockedBy = slices.Delete(task.BlockedBy, idx, idx+1)
+	if len(task.BlockedBy) == 0 {
+		task.BlockedBy = nil
+	}
+	task.UpdatedAt = NowTimestamp()
+	return true
+}
+
+// dependsOn reports whether fromID is blocked by targetID directly or
+// through a chain of other tasks.
+func (p *Project) dependsOn(fromID, targetID string) bool {
+	visited := make(map[string]bool)
+	stack := []string{fromID}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == targetID {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		if task, _, ok := p.FindTask(id); ok {
+			stack = append(stack, task.BlockedBy...)
+		}
+	}
+	return false
+}
+
+// OpenBlockers returns the tasks blocking t that are neither completed nor
+// cancelled.
+func (p *Project) OpenBlockers(t *Task) []*Task {
+	var blockers []*Task
+	for _, id := range t.BlockedBy {
+		if blocker, _, ok := p.FindTask(id); ok && !blocker.IsDone() {
+			blockers = append(blockers, blocker)
+		}
+	}
+	return blockers
+}
+
+// IsBlocked reports whether t still waits on an unfinished task.
+func (p *Project) IsBlocked(t *Task) bool {
+	for _, id := range t.BlockedBy {
+		if blocker, _, ok := p.FindTask(id); ok && !blocker.IsDone() {
+			return true
+		}
+	}
+	return false
+}
+
+// PruneDependencies removes links to tasks that no longer exist in the
+// project, as left behind by deletions.
+func (p *Project) PruneDependencies() {
+	ids := make(map[string]bool)
+	for cIdx := range p.Categories {
+		WalkTasks(p.Categories[cIdx].Tasks, func(task *Task, _ int) {
+			ids[task.ID] = true
+		})
+	}
+	for cIdx := range p.Categories {
+		WalkTasks(p.Categories[cIdx].Tasks, func(task *Task, _ int) {
+			if len(task.BlockedBy) == 0 {
+				return
+			}
+			kept := task.BlockedBy[:0]
+			for _, id := range task.BlockedBy {
+				if ids[id] {
+					kept = append(kept, id)
+				}
+			}
+			if len(kept) == 0 {
+				kept = nil
+			}
+			task.BlockedBy = kept
+		})
+	}
+}
+
+// RetargetCopies points links between the original tasks, and their
+// subtasks, at the matching copies. The copies must have the same shape as
+// the originals, as produced by Clone followed by ReassignIDs.
+func RetargetCopies(originals, copies []Task) {
+	scratch := Project{Categories: []Category{{Tasks: copies}}}
+	for i := range min(len(originals), len(copies)) {
+		scratch.RetargetDependencies(originals[i], copies[i])
+	}
+}
+
+// RetargetDependencies points links at from, and at each of its subtasks, to
+// the matching task in to. Both trees must have the same shape, as produced
+// by Clone followed by ReassignIDs.
+func (p *Project) RetargetDependencies(from, to Task) {
+	renamed := make(map[string]string)
+	var oldIDs, newIDs []string
+	WalkTasks([]Task{from}, func(task *Task, _ int) { oldIDs = append(oldIDs, task.ID) })
+	WalkTasks([]Task{to}, func(task *Task, _ int) { newIDs = append(newIDs, task.ID) })
+	for i := range min(len(oldIDs), len(newIDs)) {
+		renamed[oldIDs[i]] = newIDs[i]
+	}
+	for cIdx := range p.Categories {
+		WalkTasks(p.Categories[cIdx].Tasks, func(task *Task, _ int) {
+			for i, id := range task.BlockedBy {
+				if newID, ok := renamed[id]; ok {
+					task.BlockedBy[i] = newID
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dependencyProject() Project {
+	return Project{Categories: []Category{
+		{Tasks: []Task{
+			{ID: "a", Status: StatusTodo},
+			{ID: "b", Status: StatusTodo, Subtasks: []Task{
+				{ID: "b1", Status: StatusTodo},
+			}},
+		}},
+		{Tasks: []Task{
+			{ID: "c", Status: StatusTodo},
+		}},
+	}}
+}
+
+func TestProject_AddDependency(t *testing.T) {
+	t.Run("links tasks across categories", func(t *testing.T) {
+		p := dependencyProject()
+		require.NoError(t, p.AddDependency("c", "a"))
+		task, _, _ := p.FindTask("c")
+		assert.Equal(t, []string{"a"}, task.BlockedBy)
+
+		require.NoError(t, p.AddDependency("c", "a"))
+		assert.Len(t, task.BlockedBy, 1, "duplicate links are ignored")
+	})
+
+	t.Run("rejects self links", func(t *testing.T) {
+		p := dependencyProject()
+		assert.ErrorIs(t, p.AddDependency("a", "a"), ErrSelfDependency)
+	})
+
+	t.Run("rejects unknown tasks", func(t *testing.T) {
+		p := dependencyProject()
+		assert.Error(t, p.AddDependency("a", "missing"))
+		assert.Error(t, p.AddDependency("missing", "a"))
+	})
+
+	t.Run("detects direct and transitive cycles", func(t *testing.T) {
+		p := dependencyProject()
+		require.NoError(t, p.AddDependency("b", "a"))
+		assert.ErrorIs(t, p.AddDependency("a", "b"), ErrDependencyCycle)
+
+		require.NoError(t, p.AddDependency("b1", "b"))
+		require.NoError(t, p.AddDependency("c", "b1"))
+		assert.ErrorIs(t, p.AddDependency("a", "c"), ErrDependencyCycle)
+	})
+}
+
+func TestProject_RemoveDependency(t *testing.T) {
+	p := dependencyProject()
+	require.NoError(t, p.AddDependency("c", "a"))
+	assert.True(t, p.RemoveDependency("c", "a"))
+	assert.False(t, p.RemoveDependency("c", "a"))
+	task, _, _ := p.FindTask("c")
+	assert.Nil(t, task.BlockedBy)
+}
+
+func TestProject_IsBlocked(t *testing.T) {
+	p := dependencyProject()
+	require.NoError(t, p.AddDependency("c", "a"))
+	require.NoError(t, p.AddDependency("c", "b1"))
+	task, _, _ := p.FindTask("c")
+	assert.True(t, p.IsBlocked(task))
+	assert.Len(t, p.OpenBlockers(task), 2)
+
+	a, _, _ := p.FindTask("a")
+	a.Status = StatusCompleted
+	b1, _, _ := p.FindTask("b1")
+	b1.Status = StatusCancelled
+	assert.False(t, p.IsBlocked(task))
+	assert.Empty(t, p.OpenBlockers(task))
+}
+
+func TestProject_PruneDependencies(t *testing.T) {
+	p := dependencyProject()
+	require.NoError(t, p.AddDependency("c", "a"))
+	require.NoError(t, p.AddDependency("c", "b1"))
+	require.NoError(t, p.AddDependency("a", "b1"))
+
+	p.RemoveTaskByID("b")
+	p.PruneDependencies()
+
+	c, _, _ := p.FindTask("c")
+	assert.Equal(t, []string{"a"}, c.BlockedBy)
+	a, _, _ := p.FindTask("a")
+	assert.Nil(t, a.BlockedBy)
+}
+
+func TestProject_RetargetDependencies(t *testing.T) {
+	p := dependencyProject()
+	require.NoError(t, p.AddDependency("c", "b1"))
+	require.NoError(t, p.AddDependency("a", "b"))
+
+	source, _, _ := p.FindTask("b")
+	moved := source.Clone()
+	require.NoError(t, moved.ReassignIDs())
+	p.RetargetDependencies(*source, moved)
+
+	c, _, _ := p.FindTask("c")
+	assert.Equal(t, []string{moved.Subtasks[0].ID}, c.BlockedBy)
+	a, _, _ := p.FindTask("a")
+	assert.Equal(t, []string{moved.ID}, a.BlockedBy)
+}
+
+func TestRetargetCopies(t *testing.T) {
+	p := dependencyProject()
+	require.NoError(t, p.AddDependency("b1", "a"))
+	source, _, _ := p.FindTask("b")
+	source.BlockedBy = []string{"b1"}
+
+	copied := source.Clone()
+	require.NoError(t, copied.ReassignIDs())
+	RetargetCopies([]Task{*source}, []Task{copied})
+
+	assert.Equal(t, []string{copied.Subtasks[0].ID}, copied.BlockedBy, "links inside the copy follow it")
+	assert.Equal(t, []string{"a"}, copied.Subtasks[0].BlockedBy, "links outside the copy stay")
+	assert.Equal(t, []string{"b1"}, source.BlockedBy)
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"reflect"
+	"slices"
+)
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// TaskChange describes how one task differs between two versions of a
+// project. Fields lists what changed for ChangeChanged entries; moving the
+// task to another category or parent shows up as "category" or "parent".
+type TaskChange struct {
+	Kind     string
+	TaskID   string
+	Title    string
+	Category string
+	Fields   []string
+}
+
+// DiffProjects lists the tasks added, removed or changed going from before
+// to after, in the order they appear in after followed by removed tasks in
+// the order they appeared in before.
+func DiffProjects(before, after Project) []TaskChange {
+	oldIndex := indexTasks(&before)
+	newIndex := indexTasks(&after)
+	changes := make([]TaskChange, 0)
+
+	for _, cat := range after.Categories {
+		WalkTasks(cat.Tasks, func(task *Task, _ int) {
+			old, existed := oldIndex[task.ID]
+			if !existed {
+				changes = append(changes, TaskChange{Kind: ChangeAdded, TaskID: task.ID, Title: task.Title, Category: cat.Name})
+				return
+			}
+			fields := changedTaskFields(old.task, task)
+			if old.categoryID != cat.ID {
+				fields = append(fields, "category")
+			} else if old.parentID != newIndex[task.ID].parentID {
+				fields = append(fields, "parent")
+			}
+			if len(fields) > 0 {
+				changes = append(changes, TaskChange{Kind: ChangeChanged, TaskID: task.ID, Title: task.Title, Category: cat.Name, Fields: fields})
+			}
+		})
+	}
+	for _, cat := range before.Categories {
+		WalkTasks(cat.Tasks, func(task *Task, _ int) {
+			if _, kept := newIndex[task.ID]; !kept {
+				changes = append(changes, TaskChange{Kind: ChangeRemoved, TaskID: task.ID, Title: task.Title, Category: cat.Name})
+			}
+		})
+	}
+	return changes
+}
+
+// changedTaskFields names the fields that differ between two copies of a
+// task, ignoring subtasks and timestamps that every edit touches.
+func changedTaskFields(a, b *Task) []string {
+	var fields []string
+	add := func(name string, changed bool) {
+		if changed {
+			fields = append(fields, name)
+		}
+	}
+	add("title", a.Title != b.Title)
+	add("status", a.Status != b.Status)
+	add("priority", a.Priority != b.Priority)
+	add("estimate", a.EstimateMinutes != b.EstimateMinutes)
+	add("description", a.Description != b.Description)
+	add("notes", a.Notes != b.Notes)
+	add("deadline", a.Deadline != b.Deadline)
+	add("section", a.SectionName() != b.SectionName())
+	add("blocked_by", !slices.Equal(a.BlockedBy, b.BlockedBy))
+	add("tags", !slices.Equal(a.Tags, b.Tags))
+	add("recurrence", !reflect.DeepEqual(a.Recurrence, b.Recurrence))
+	add("time", !slices.Equal(a.TimeEntries, b.TimeEntries))
+	return fields
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffProjects(t *testing.T) {
+	before := mergeBase()
+	after := before.Clone()
+	after.Categories[0].Tasks[0].Status = StatusCompleted
+	after.Categories[0].Tasks[0].Title = "A done"
+	moved, _ := after.RemoveTaskByID("c")
+	after.Categories[1].Tasks = append(after.Categories[1].Tasks, moved)
+	after.RemoveTaskByID("b1")
+	after.Categories[0].Tasks = append(after.Categories[0].Tasks, Task{ID: "e", Title: "E"})
+
+	changes := DiffProjects(before, after)
+	require.Len(t, changes, 4)
+
+	assert.Equal(t, TaskChange{Kind: ChangeChanged, TaskID: "a", Title: "A done", Category: "Feature", Fields: []string{"title", "status"}}, changes[0])
+	assert.Equal(t, TaskChange{Kind: ChangeAdded, TaskID: "e", Title: "E", Category: "Feature"}, changes[1])
+	assert.Equal(t, TaskChange{Kind: ChangeChanged, TaskID: "c", Title: "C", Category: "Fix", Fields: []string{"category"}}, changes[2])
+	assert.Equal(t, TaskChange{Kind: ChangeRemoved, TaskID: "b1", Title: "B1", Category: "Feature"}, changes[3])
+}
+
+func TestDiffProjects_Unchanged(t *testing.T) {
+	base := mergeBase()
+	assert.Empty(t, DiffProjects(base, base.Clone()))
+}
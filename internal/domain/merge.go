@@ -0,0 +1,266 @@
+package domain
+
+import (
+	"reflect"
+	"slices"
+)
+
+// taskPlacement records where a task sits in a project.
+type taskPlacement struct {
+	task       *Task
+	categoryID string
+	parentID   string // empty for top-level tasks
+	prevID     string // sibling just before the task, empty when first
+}
+
+func indexTasks(p *Project) map[string]taskPlacement {
+	index := make(map[string]taskPlacement)
+	var walk func(tasks []Task, categoryID, parentID string)
+	walk = func(tasks []Task, categoryID, parentID string) {
+		for i := range tasks {
+			placement := taskPlacement{task: &tasks[i], categoryID: categoryID, parentID: parentID}
+			if i > 0 {
+				placement.prevID = tasks[i-1].ID
+			}
+			index[tasks[i].ID] = placement
+			walk(tasks[i].Subtasks, categoryID, tasks[i].ID)
+		}
+	}
+	for cIdx := range p.Categories {
+		walk(p.Categories[cIdx].Tasks, p.Categories[cIdx].ID, "")
+	}
+	return index
+}
+
+// sameTaskFields compares two tasks while ignoring their subtasks.
+func sameTaskFields(a, b *Task) bool {
+	x, y := *a, *b
+	x.Subtasks, y.Subtasks = nil, nil
+	return reflect.DeepEqual(x, y)
+}
+
+func (p *Project) categoryByID(id string) (*Category, int) {
+	for i := range p.Categories {
+		if p.Categories[i].ID == id {
+			return &p.Categories[i], i
+		}
+	}
+	return nil, -1
+}
+
+// MergeProjects combines a local edit of a project with a version someone
+// else saved in the meantime. base is the version both started from, ours
+// the local edit and theirs the version now on disk. Changes from both
+// sides are kept; when both touched the same task or category, ours wins.
+// The result carries theirs' revision so it can be saved over it.
+func MergeProjects(base, ours, theirs Project) Project {
+	result := theirs.Clone()
+	if ours.Name != base.Name {
+		result.Name = ours.Name
+	}
+	if ours.Archived != base.Archived {
+		result.Archived = ours.Archived
+	}
+	mergeCategories(&base, &ours, &result)
+	mergeArchive(&base, &ours, &result)
+
+	baseTasks := indexTasks(&base)
+	ourTasks := indexTasks(&ours)
+
+	for id := range baseTasks {
+		if _, kept := ourTasks[id]; !kept {
+			result.RemoveTaskByID(id)
+		}
+	}
+
+	for cIdx := range ours.Categories {
+		WalkTasks(ours.Categories[cIdx].Tasks, func(task *Task, _ int) {
+			mergeTask(&result, baseTasks, ourTasks[task.ID])
+		})
+	}
+
+	for cIdx := range ours.Categories {
+		cat := &ours.Categories[cIdx]
+		if target, _ := result.categoryByID(cat.ID); target != nil {
+			baseCat, _ := base.categoryByID(cat.ID)
+			var baseOrder []Task
+			if baseCat != nil {
+				baseOrder = baseCat.Tasks
+			}
+			mergeOrder(&target.Tasks, baseOrder, cat.Tasks)
+		}
+		WalkTasks(cat.Tasks, func(task *Task, _ int) {
+			if len(task.Subtasks) == 0 {
+				return
+			}
+			target, _, ok := result.FindTask(task.ID)
+			if !ok {
+				return
+			}
+			var baseOrder []Task
+			if placement, ok := baseTasks[task.ID]; ok {
+				baseOrder = placement.task.Subtasks
+			}
+			mergeOrder(&target.Subtasks, baseOrder, task.Subtasks)
+		})
+	}
+	return result
+}
+
+// mergeArchive applies the tasks we archived or restored to the result's
+// archive. The tasks themselves leave or return to the board like any other
+// removal or addition.
+func mergeArchive(base, ours, result *Project) {
+	inBase, inOurs := archiveEntries(base), archiveEntries(ours)
+	var archive []ArchivedTask
+	present := make(map[string]bool)
+	for _, entry := range result.Archive {
+		if inBase[entry.Task.ID] && !inOurs[entry.Task.ID] {
+			continue
+		}
+		archive = append(archive, entry)
+		present[entry.Task.ID] = true
+	}
+	for _, entry := range ours.Archive {
+		if !inBase[entry.Task.ID] && !present[entry.Task.ID] {
+			entry.Task = entry.Task.Clone()
+			archive = append(archive, entry)
+		}
+	}
+	result.Archive = archive
+}
+
+func archiveEntries(p *Project) map[string]bool {
+	ids := make(map[string]bool, len(p.Archive))
+	for _, entry := range p.Archive {
+		ids[entry.Task.ID] = true
+	}
+	return ids
+}
+
+func mergeCategories(base, ours, result *Project) {
+	for _, cat := range base.Categories {
+		if mine, _ := ours.categoryByID(cat.ID); mine == nil {
+			if _, idx := result.categoryByID(cat.ID); idx >= 0 {
+				result.Categories = append(result.Categories[:idx], result.Categories[idx+1:]...)
+			}
+		}
+	}
+	for i, cat := range ours.Categories {
+		original, _ := base.categoryByID(cat.ID)
+		target, _ := result.categoryByID(cat.ID)
+		switch {
+		case original == nil && target == nil:
+			// New on our side; its tasks are added with the task pass.
+			added := cat
+			added.Tasks = []Task{}
+			insertAt := 0
+			for j := i - 1; j >= 0; j-- {
+				if _, prev := result.categoryByID(ours.Categories[j].ID); prev >= 0 {
+					insertAt = prev + 1
+					break
+				}
+			}
+			result.InsertCategory(insertAt, added)
+		case target != nil && original != nil:
+			if cat.Name != original.Name {
+				target.Name = cat.Name
+			}
+			if cat.EstimateMinutes != original.EstimateMinutes {
+				target.EstimateMinutes = cat.EstimateMinutes
+			}
+		}
+	}
+}
+
+func mergeTask(result *Project, baseTasks map[string]taskPlacement, mine taskPlacement) {
+	original, inBase := baseTasks[mine.task.ID]
+	changed := !inBase || !sameTaskFields(original.task, mine.task)
+	moved := !inBase || original.categoryID != mine.categoryID || original.parentID != mine.parentID
+	if !changed && !moved {
+		return
+	}
+
+	existing, _, found := result.FindTask(mine.task.ID)
+	if !found && inBase && !changed {
+		// Deleted on their side and only moved on ours.
+		return
+	}
+
+	var task Task
+	if found {
+		task = *existing
+		if moved {
+			task, _ = result.RemoveTaskByID(task.ID)
+		}
+		if changed {
+			subtasks := task.Subtasks
+			task = mine.task.Clone()
+			task.Subtasks = subtasks
+		}
+		if !moved {
+			*existing = task
+			return
+		}
+	} else {
+		task = mine.task.Clone()
+		task.Subtasks = nil
+	}
+
+	var siblings *[]Task
+	if mine.parentID != "" {
+		if parent, _, ok := result.FindTask(mine.parentID); ok {
+			siblings = &parent.Subtasks
+		}
+	}
+	if siblings == nil {
+		cat, _ := result.categoryByID(mine.categoryID)
+		if cat == nil {
+			return
+		}
+		siblings = &cat.Tasks
+	}
+	insertAt := 0
+	if mine.prevID != "" {
+		if idx := slices.IndexFunc(*siblings, func(t Task) bool { return t.ID == mine.prevID }); idx >= 0 {
+			insertAt = idx + 1
+		}
+	}
+	InsertTaskAt(siblings, insertAt, task)
+}
+
+// mergeOrder applies our reordering of a sibling list to the merged list,
+// when we changed the order. Tasks we know about are rearranged among the
+// slots they occupy; tasks only the other side has stay where they are.
+func mergeOrder(target *[]Task, baseOrder, ourOrder []Task) {
+	ids := func(tasks []Task) []string {
+		out := make([]string, 0, len(tasks))
+		for _, t := range tasks {
+			out = append(out, t.ID)
+		}
+		return out
+	}
+	ourIDs := ids(ourOrder)
+	baseIDs := slices.DeleteFunc(ids(baseOrder), func(id string) bool { return !slices.Contains(ourIDs, id) })
+	commonOurs := slices.DeleteFunc(slices.Clone(ourIDs), func(id string) bool { return !slices.Contains(baseIDs, id) })
+	if slices.Equal(baseIDs, commonOurs) {
+		return
+	}
+
+	rank := make(map[string]int, len(ourIDs))
+	for i, id := range ourIDs {
+		rank[id] = i
+	}
+	var slots []int
+	var known []Task
+	for i, t := range *target {
+		if _, ok := rank[t.ID]; ok {
+			slots = append(slots, i)
+			known = append(known, t)
+		}
+	}
+	slices.SortStableFunc(known, func(a, b Task) int { return rank[a.ID] - rank[b.ID] })
+	for i, slot := range slots {
+		(*target)[slot] = known[i]
+	}
+}
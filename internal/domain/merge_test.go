@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mergeBase() Project {
+	return Project{
+		ID:       "p",
+		Name:     "Project",
+		Revision: 3,
+		Categories: []Category{
+			{ID: "c1", Name: "Feature", Tasks: []Task{
+				{ID: "a", Title: "A"},
+				{ID: "b", Title: "B", Subtasks: []Task{{ID: "b1", Title: "B1"}}},
+				{ID: "c", Title: "C"},
+			}},
+			{ID: "c2", Name: "Fix", Tasks: []Task{{ID: "d", Title: "D"}}},
+		},
+	}
+}
+
+func taskIDs(tasks []Task) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func TestMergeProjects_KeepsBothSides(t *testing.T) {
+	base := mergeBase()
+
+	ours := base.Clone()
+	ours.Categories[0].Tasks[0].Title = "A edited"
+	ours.Categories[1].Tasks = append(ours.Categories[1].Tasks, Task{ID: "mine", Title: "Mine"})
+	ours.Archived = true
+
+	theirs := base.Clone()
+	theirs.Revision = 4
+	theirs.Categories[0].Tasks = append(theirs.Categories[0].Tasks, Task{ID: "cli", Title: "From CLI"})
+	theirs.Categories[1].Tasks[0].Status = StatusCompleted
+
+	merged := MergeProjects(base, ours, theirs)
+
+	assert.Equal(t, 4, merged.Revision)
+	assert.Equal(t, []string{"a", "b", "c", "cli"}, taskIDs(merged.Categories[0].Tasks))
+	assert.Equal(t, "A edited", merged.Categories[0].Tasks[0].Title)
+	assert.Equal(t, []string{"d", "mine"}, taskIDs(merged.Categories[1].Tasks))
+	assert.Equal(t, StatusCompleted, merged.Categories[1].Tasks[0].Status)
+	assert.True(t, merged.Archived)
+}
+
+func TestMergeProjects_DeletesAndMoves(t *testing.T) {
+	base := mergeBase()
+
+	ours := base.Clone()
+	_, ok := ours.RemoveTaskByID("c")
+	require.True(t, ok)
+	moved, _ := ours.RemoveTaskByID("a")
+	ours.Categories[1].Tasks = append(ours.Categories[1].Tasks, moved)
+
+	theirs := base.Clone()
+	theirs.Categories[0].Tasks[1].Subtasks = append(theirs.Categories[0].Tasks[1].Subtasks, Task{ID: "b2", Title: "B2"})
+
+	merged := MergeProjects(base, ours, theirs)
+
+	assert.Equal(t, []string{"b"}, taskIDs(merged.Categories[0].Tasks))
+	assert.Equal(t, []string{"b1", "b2"}, taskIDs(merged.Categories[0].Tasks[0].Subtasks))
+	assert.Equal(t, []string{"d", "a"}, taskIDs(merged.Categories[1].Tasks))
+}
+
+func TestMergeProjects_Reorder(t *testing.T) {
+	base := mergeBase()
+
+	ours := base.Clone()
+	tasks := ours.Categories[0].Tasks
+	tasks[0], tasks[2] = tasks[2], tasks[0]
+
+	theirs := base.Clone()
+	theirs.Categories[0].Tasks = append(theirs.Categories[0].Tasks, Task{ID: "cli"})
+
+	merged := MergeProjects(base, ours, theirs)
+	assert.Equal(t, []string{"c", "b", "a", "cli"}, taskIDs(merged.Categories[0].Tasks))
+}
+
+func TestMergeProjects_Categories(t *testing.T) {
+	base := mergeBase()
+
+	ours := base.Clone()
+	ours.Categories[0].Name = "Features"
+	newCat := Category{ID: "c3", Name: "Docs", Tasks: []Task{{ID: "e", Title: "E"}}}
+	ours.Categories = append(ours.Categories, newCat)
+
+	theirs := base.Clone()
+	theirs.Categories = theirs.Categories[:1]
+
+	merged := MergeProjects(base, ours, theirs)
+	require.Len(t, merged.Categories, 2)
+	assert.Equal(t, "Features", merged.Categories[0].Name)
+	assert.Equal(t, "Docs", merged.Categories[1].Name)
+	assert.Equal(t, []string{"e"}, taskIDs(merged.Categories[1].Tasks))
+}
@@ -0,0 +1,227 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RecurDay   = "day"
+	RecurWeek  = "week"
+	RecurMonth = "month"
+)
+
+var recurrenceRe = regexp.MustCompile(`^(\d+)\s*([dwm])$`)
+
+// Recurrence describes how often a task repeats. On a fixed schedule the
+// next deadline follows the previous one; with FromCompletion it is counted
+// from the day the task was completed.
+type Recurrence struct {
+	Interval       int    `json:"interval"`
+	Unit           string `json:"unit"`
+	FromCompletion bool   `json:"from_completion,omitempty"`
+}
+
+func ValidateRecurrence(r Recurrence) error {
+	if r.Interval < 1 {
+		return errors.New("recurrence interval must be at least 1")
+	}
+	switch r.Unit {
+	case RecurDay, RecurWeek, RecurMonth:
+		return nil
+	default:
+		return errors.New("invalid recurrence unit")
+	}
+}
+
+// ParseRecurrence reads "daily", "weekly", "monthly" or an interval such as
+// "3d", "2w" or "1m". "none" or an empty string means no recurrence.
+func ParseRecurrence(input string) (*Recurrence, error) {
+	input = strings.TrimSpace(strings.ToLower(input))
+	switch input {
+	case "", "none":
+		return nil, nil
+	case "daily":
+		return &Recurrence{Interval: 1, Unit: RecurDay}, nil
+	case "weekly":
+		return &Recurrence{Interval: 1, Unit: RecurWeek}, nil
+	case "monthly":
+		return &Recurrence{Interval: 1, Unit: RecurMonth}, nil
+	}
+	m := recurrenceRe.FindStringSubmatch(input)
+	if m == nil {
+		return nil, fmt.Errorf("invalid recurrence: %s (use daily, weekly, monthly, 3d, 2w, 1m)", input)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return nil, err
+	}
+	r := &Recurrence{Interval: n}
+	switch m[2] {
+	case "d":
+		r.Unit = RecurDay
+	case "w":
+		r.Unit = RecurWeek
+	case "m":
+		r.Unit = RecurMonth
+	}
+	if err := ValidateRecurrence(*r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// String describes the rule for display, e.g. "every 2 weeks".
+func (r Recurrence) String() string {
+	text := "every " + r.Unit
+	if r.Interval > 1 {
+		text = fmt.Sprintf("every %d %ss", r.Interval, r.Unit)
+	}
+	if r.FromCompletion {
+		text += " after completion"
+	}
+	return text
+}
+
+// Short is the compact form accepted by ParseRecurrence, e.g. "2w".
+func (r Recurrence) Short() string {
+	return fmt.Sprintf("%d%c", r.Interval, r.Unit[0])
+}
+
+// Next returns the date one interval after from. Monthly rules stay on the
+// same day of the month, clamped to the month's last day.
+func (r Recurrence) Next(from time.Time) time.Time {
+	switch r.Unit {
+	case RecurWeek:
+		return from.AddDate(0, 0, 7*r.Interval)
+	case RecurMonth:
+		first := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location()).AddDate(0, r.Interval, 0)
+		lastDay := first.AddDate(0, 1, -1).Day()
+		return first.AddDate(0, 0, min(from.Day(), lastDay)-1)
+	default:
+		return from.AddDate(0, 0, r.Interval)
+	}
+}
+
+func (t *Task) SetRecurrence(r *Recurrence) error {
+	if r != nil {
+		if err := ValidateRecurrence(*r); err != nil {
+			return err
+		}
+	}
+	t.Recurrence = r
+	t.UpdatedAt = NowTimestamp()
+	return nil
+}
+
+// NextOccurrence builds the todo copy that follows t. The copy, including
+// its subtasks, gets fresh IDs and takes over the recurrence rule.
+func (t *Task) NextOccurrence(now time.Time) (Task, error) {
+	next := t.Clone()
+	if err := next.ReassignIDs(); err != nil {
+		return Task{}, err
+	}
+	resetOccurrence(&next)
+	if next.Section == SectionPast {
+		next.Section = SectionCurrent
+	}
+
+	today := StartOfDay(now)
+	rule := *t.Recurrence
+	base := today
+	if deadline, ok := t.DeadlineIn(now.Location()); ok && !rule.FromCompletion {
+		base = deadline
+	}
+	due := rule.Next(base)
+	for due.Before(today) {
+		due = rule.Next(due)
+	}
+	next.Deadline = due.Format(DateLayout)
+	return next, nil
+}
+
+func resetOccurrence(t *Task) {
+	now := NowTimestamp()
+	t.Status = StatusTodo
+	t.CompletionDate = ""
+	t.TimeEntries = nil
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	for i := range t.Subtasks {
+		t.Subtasks[i].Recurrence = nil
+		resetOccurrence(&t.Subtasks[i])
+	}
+}
+
+// SetTaskStatus sets the status of a task. Completing a recurring task
+// inserts its next occurrence right after it and returns the copy; the rule
+// moves to the copy, so the completed task stays as history.
+func (p *Project) SetTaskStatus(id, status string) (*Task, error) {
+	siblings, idx, _, ok := p.LocateTask(id)
+	if !ok {
+		return nil, errors.New("task not found")
+	}
+	previous := (*siblings)[idx].Status
+	if err := (*siblings)[idx].SetStatus(status); err != nil {
+		return nil, err
+	}
+	return spawnOccurrence(siblings, idx, previous), nil
+}
+
+// CycleTaskStatus moves a task to its next status like Task.CycleStatus,
+// creating the next occurrence of a recurring task on completion. Cycling
+// on to cancelled takes back an occurrence that was just created and not
+// touched since, as completion was only a step on the way.
+func (p *Project) CycleTaskStatus(id string) (bool, *Task) {
+	siblings, idx, _, ok := p.LocateTask(id)
+	if !ok {
+		return false, nil
+	}
+	previous := (*siblings)[idx].Status
+	if previous == StatusCompleted {
+		retractOccurrence(siblings, idx)
+	}
+	if !(*siblings)[idx].CycleStatus() {
+		return false, nil
+	}
+	return true, spawnOccurrence(siblings, idx, previous)
+}
+
+// spawnOccurrence inserts the follow-up of a recurring task that was just
+// completed after it. The copy is created when the task was completed, so
+// retractOccurrence can tell it apart.
+func spawnOccurrence(siblings *[]Task, idx int, previous string) *Task {
+	task := &(*siblings)[idx]
+	if task.Status != StatusCompleted || previous == StatusCompleted || task.Recurrence == nil {
+		return nil
+	}
+	next, err := task.NextOccurrence(time.Now())
+	if err != nil {
+		return nil
+	}
+	next.CreatedAt = task.CompletionDate
+	next.UpdatedAt = task.CompletionDate
+	task.Recurrence = nil
+	InsertTaskAt(siblings, idx+1, next)
+	return &(*siblings)[idx+1]
+}
+
+// retractOccurrence removes the occurrence spawned by completing the task
+// at idx, giving the rule back, as long as the copy was not changed since.
+func retractOccurrence(siblings *[]Task, idx int) {
+	task := &(*siblings)[idx]
+	if task.Recurrence != nil || task.CompletionDate == "" || idx+1 >= len(*siblings) {
+		return
+	}
+	next := (*siblings)[idx+1]
+	if next.Recurrence == nil || next.Status != StatusTodo || next.Title != task.Title ||
+		next.CreatedAt != task.CompletionDate || next.UpdatedAt != next.CreatedAt {
+		return
+	}
+	task.Recurrence = next.Recurrence
+	*siblings = append((*siblings)[:idx+1], (*siblings)[idx+2:]...)
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected *Recurrence
+	}{
+		{"", nil},
+		{"none", nil},
+		{"daily", &Recurrence{Interval: 1, Unit: RecurDay}},
+		{"Weekly", &Recurrence{Interval: 1, Unit: RecurWeek}},
+		{"monthly", &Recurrence{Interval: 1, Unit: RecurMonth}},
+		{"3d", &Recurrence{Interval: 3, Unit: RecurDay}},
+		{"2w", &Recurrence{Interval: 2, Unit: RecurWeek}},
+		{"6m", &Recurrence{Interval: 6, Unit: RecurMonth}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			r, err := ParseRecurrence(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, r)
+		})
+	}
+
+	for _, input := range []string{"0d", "3y", "often"} {
+		_, err := ParseRecurrence(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestRecurrence_String(t *testing.T) {
+	assert.Equal(t, "every week", Recurrence{Interval: 1, Unit: RecurWeek}.String())
+	assert.Equal(t, "every 3 days after completion", Recurrence{Interval: 3, Unit: RecurDay, FromCompletion: true}.String())
+	assert.Equal(t, "2w", Recurrence{Interval: 2, Unit: RecurWeek}.Short())
+}
+
+func TestRecurrence_Next(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse(DateLayout, s)
+		require.NoError(t, err)
+		return d
+	}
+	monthly := Recurrence{Interval: 1, Unit: RecurMonth}
+	assert.Equal(t, "2026-02-28", monthly.Next(date("2026-01-31")).Format(DateLayout))
+	assert.Equal(t, "2026-03-15", monthly.Next(date("2026-02-15")).Format(DateLayout))
+	assert.Equal(t, "2026-01-15", Recurrence{Interval: 2, Unit: RecurWeek}.Next(date("2026-01-01")).Format(DateLayout))
+}
+
+func TestTask_NextOccurrence(t *testing.T) {
+	now := time.Date(2026, 5, 20, 10, 0, 0, 0, time.UTC)
+
+	t.Run("fixed schedule follows the previous deadline", func(t *testing.T) {
+		task := Task{ID: "a", Status: StatusCompleted, Deadline: "2026-05-18",
+			Recurrence: &Recurrence{Interval: 1, Unit: RecurWeek}}
+		next, err := task.NextOccurrence(now)
+		require.NoError(t, err)
+		assert.Equal(t, "2026-05-25", next.Deadline)
+		assert.Equal(t, StatusTodo, next.Status)
+		assert.NotEqual(t, "a", next.ID)
+	})
+
+	t.Run("fixed schedule skips periods already past", func(t *testing.T) {
+		task := Task{Deadline: "2026-04-01", Recurrence: &Recurrence{Interval: 1, Unit: RecurWeek}}
+		next, err := task.NextOccurrence(now)
+		require.NoError(t, err)
+		assert.Equal(t, "2026-05-20", next.Deadline)
+	})
+
+	t.Run("after completion counts from today", func(t *testing.T) {
+		task := Task{Deadline: "2026-04-01", Recurrence: &Recurrence{Interval: 3, Unit: RecurDay, FromCompletion: true}}
+		next, err := task.NextOccurrence(now)
+		require.NoError(t, err)
+		assert.Equal(t, "2026-05-23", next.Deadline)
+	})
+
+	t.Run("resets subtasks", func(t *testing.T) {
+		task := Task{Recurrence: &Recurrence{Interval: 1, Unit: RecurDay},
+			Subtasks: []Task{{ID: "s", Status: StatusCompleted, CompletionDate: "x"}}}
+		next, err := task.NextOccurrence(now)
+		require.NoError(t, err)
+		require.Len(t, next.Subtasks, 1)
+		assert.Equal(t, StatusTodo, next.Subtasks[0].Status)
+		assert.Empty(t, next.Subtasks[0].CompletionDate)
+		assert.NotEqual(t, "s", next.Subtasks[0].ID)
+	})
+}
+
+func TestSetTaskStatus_SpawnsRecurrence(t *testing.T) {
+	p := Project{Categories: []Category{{Tasks: []Task{
+		{ID: "a", Title: "Rotate secrets", Status: StatusTodo, Recurrence: &Recurrence{Interval: 1, Unit: RecurMonth}},
+		{ID: "b", Status: StatusTodo},
+	}}}}
+
+	next, err := p.SetTaskStatus("a", StatusCompleted)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+
+	tasks := p.Categories[0].Tasks
+	require.Len(t, tasks, 3)
+	assert.Equal(t, "a", tasks[0].ID)
+	assert.Equal(t, StatusCompleted, tasks[0].Status)
+	assert.Nil(t, tasks[0].Recurrence, "the completed occurrence is kept as history")
+	assert.Equal(t, "Rotate secrets", tasks[1].Title)
+	assert.Equal(t, StatusTodo, tasks[1].Status)
+	require.NotNil(t, tasks[1].Recurrence)
+	assert.NotEmpty(t, tasks[1].Deadline)
+	assert.Equal(t, "b", tasks[2].ID)
+
+	next, err = p.SetTaskStatus("a", StatusCompleted)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+	assert.Len(t, p.Categories[0].Tasks, 3)
+
+	task := Task{Status: StatusInProgress, Recurrence: &Recurrence{Interval: 1, Unit: RecurDay}}
+	require.NoError(t, task.SetStatus(StatusCompleted))
+	assert.NotNil(t, task.Recurrence, "a task on its own never spawns")
+}
+
+func TestCycleTaskStatus_CancelTakesBackOccurrence(t *testing.T) {
+	rule := &Recurrence{Interval: 1, Unit: RecurWeek}
+	p := Project{Categories: []Category{{Tasks: []Task{
+		{ID: "a", Title: "Water plants", Status: StatusInProgress, Recurrence: rule},
+	}}}}
+
+	changed, next := p.CycleTaskStatus("a")
+	assert.True(t, changed)
+	require.NotNil(t, next)
+	require.Len(t, p.Categories[0].Tasks, 2)
+
+	changed, next = p.CycleTaskStatus("a")
+	assert.True(t, changed)
+	assert.Nil(t, next)
+	tasks := p.Categories[0].Tasks
+	require.Len(t, tasks, 1, "cancelling takes the new occurrence back")
+	assert.Equal(t, StatusCancelled, tasks[0].Status)
+	assert.Equal(t, rule, tasks[0].Recurrence)
+
+	// An occurrence that was worked on stays.
+	changed, _ = p.CycleTaskStatus("a")
+	assert.True(t, changed)
+	_, _ = p.CycleTaskStatus("a")
+	_, next = p.CycleTaskStatus("a")
+	require.NotNil(t, next)
+	next.Notes = "started"
+	next.UpdatedAt = "2000-01-01T00:00:00Z"
+	_, _ = p.CycleTaskStatus("a")
+	assert.Len(t, p.Categories[0].Tasks, 2)
+}
@@ -0,0 +1,257 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+)
+
+func (t *Task) HasSubtasks() bool {
+	return len(t.Subtasks) > 0
+}
+
+func (t *Task) AddSubtask(sub Task) {
+	t.Subtasks = append(t.Subtasks, sub)
+	t.UpdatedAt = NowTimestamp()
+}
+
+func (t *Task) InsertSubtask(index int, sub Task) {
+	InsertTaskAt(&t.Subtasks, index, sub)
+	t.UpdatedAt = NowTimestamp()
+}
+
+func (t *Task) RemoveSubtask(index int) error {
+	if index < 0 || index >= len(t.Subtasks) {
+		return errors.New("subtask index out of range")
+	}
+	t.Subtasks = append(t.Subtasks[:index], t.Subtasks[index+1:]...)
+	t.UpdatedAt = NowTimestamp()
+	return nil
+}
+
+// SubtaskProgress counts done and total subtasks at every depth below t.
+func (t *Task) SubtaskProgress() (done, total int) {
+	WalkTasks(t.Subtasks, func(sub *Task, _ int) {
+		total++
+		if sub.IsDone() {
+			done++
+		}
+	})
+	return done, total
+}
+
+// TotalEstimateMinutes is the task's own estimate plus those of all its
+// subtasks.
+func (t *Task) TotalEstimateMinutes() int {
+	total := t.EstimateMinutes
+	WalkTasks(t.Subtasks, func(sub *Task, _ int) {
+		total += sub.EstimateMinutes
+	})
+	return total
+}
+
+// WalkTasks visits tasks depth-first, parents before their subtasks. Depth
+// is 0 for the tasks passed in.
+func WalkTasks(tasks []Task, fn func(task *Task, depth int)) {
+	walkTasks(tasks, 0, fn)
+}
+
+func walkTasks(tasks []Task, depth int, fn func(task *Task, depth int)) {
+	for i := range tasks {
+		fn(&tasks[i], depth)
+		walkTasks(tasks[i].Subtasks, depth+1, fn)
+	}
+}
+
+// CountTasks returns the number of tasks in the category including subtasks.
+func (c *Category) CountTasks() int {
+	count := 0
+	WalkTasks(c.Tasks, func(*Task, int) { count++ })
+	return count
+}
+
+// TotalTaskEstimateMinutes sums the estimates of every task and subtask in
+// the category.
+func (c *Category) TotalTaskEstimateMinutes() int {
+	total := 0
+	WalkTasks(c.Tasks, func(task *Task, _ int) {
+		total += task.EstimateMinutes
+	})
+	return total
+}
+
+// TaskAt resolves a top-level task index followed by a path of subtask
+// indexes. It returns nil when any index is out of range.
+func (c *Category) TaskAt(taskIndex int, path []int) *Task {
+	siblings, index := c.SiblingsOf(taskIndex, path)
+	if siblings == nil {
+		return nil
+	}
+	return &(*siblings)[index]
+}
+
+// SiblingsOf returns the slice that holds the addressed task together with
+// the task's index in it.
+func (c *Category) SiblingsOf(taskIndex int, path []int) (*[]Task, int) {
+	if taskIndex < 0 || taskIndex >= len(c.Tasks) {
+		return nil, -1
+	}
+	siblings := &c.Tasks
+	index := taskIndex
+	for _, next := range path {
+		parent := &(*siblings)[index]
+		if next < 0 || next >= len(parent.Subtasks) {
+			return nil, -1
+		}
+		siblings = &parent.Subtasks
+		index = next
+	}
+	return siblings, index
+}
+
+// FindTask locates a task by ID anywhere in the project.
+func (p *Project) FindTask(id string) (*Task, int, bool) {
+	for cIdx := range p.Categories {
+		if task := findTaskIn(p.Categories[cIdx].Tasks, id); task != nil {
+			return task, cIdx, true
+		}
+	}
+	return nil, -1, false
+}
+
+func findTaskIn(tasks []Task, id string) *Task {
+	for i := range tasks {
+		if tasks[i].ID == id {
+			return &tasks[i]
+		}
+		if found := findTaskIn(tasks[i].Subtasks, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// RemoveTaskByID removes a task, with its subtasks, from wherever it sits in
+// the project and returns it.
+func (p *Project) RemoveTaskByID(id string) (Task, bool) {
+	for cIdx := range p.Categories {
+		if task, ok := removeTaskIn(&p.Categories[cIdx].Tasks, id); ok {
+			p.Categories[cIdx].UpdatedAt = NowTimestamp()
+			return task, true
+		}
+	}
+	return Task{}, false
+}
+
+func removeTaskIn(tasks *[]Task, id string) (Task, bool) {
+	for i := range *tasks {
+		if (*tasks)[i].ID == id {
+			removed := (*tasks)[i]
+			*tasks = append((*tasks)[:i], (*tasks)[i+1:]...)
+			return removed, true
+		}
+		if removed, ok := removeTaskIn(&(*tasks)[i].Subtasks, id); ok {
+			return removed, true
+		}
+	}
+	return Task{}, false
+}
+
+// Clone returns a deep copy of the task so that the copy's subtasks do not
+// share storage with the original.
+func (t *Task) Clone() Task {
+	clone := *t
+	clone.BlockedBy = slices.Clone(t.BlockedBy)
+	clone.Tags = slices.Clone(t.Tags)
+	clone.TimeEntries = slices.Clone(t.TimeEntries)
+	if t.Recurrence != nil {
+		rule := *t.Recurrence
+		clone.Recurrence = &rule
+	}
+	if t.Subtasks != nil {
+		clone.Subtasks = make([]Task, len(t.Subtasks))
+		for i := range t.Subtasks {
+			clone.Subtasks[i] = t.Subtasks[i].Clone()
+		}
+	}
+	return clone
+}
+
+// Clone returns a deep copy of the project.
+func (p *Project) Clone() Project {
+	clone := *p
+	if p.Categories != nil {
+		clone.Categories = make([]Category, len(p.Categories))
+		for cIdx, cat := range p.Categories {
+			clone.Categories[cIdx] = cat
+			if cat.Tasks != nil {
+				clone.Categories[cIdx].Tasks = make([]Task, len(cat.Tasks))
+				for tIdx := range cat.Tasks {
+					clone.Categories[cIdx].Tasks[tIdx] = cat.Tasks[tIdx].Clone()
+				}
+			}
+		}
+	}
+	if p.Archive != nil {
+		clone.Archive = make([]ArchivedTask, len(p.Archive))
+		for i, entry := range p.Archive {
+			clone.Archive[i] = entry
+			clone.Archive[i].Task = entry.Task.Clone()
+		}
+	}
+	return clone
+}
+
+// ReassignIDs gives the task and all of its subtasks fresh IDs.
+func (t *Task) ReassignIDs() error {
+	id, err := NewID()
+	if err != nil {
+		return err
+	}
+	t.ID = id
+	for i := range t.Subtasks {
+		if err := t.Subtasks[i].ReassignIDs(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasDescendant reports whether a task with the given ID sits anywhere
+// below t.
+func (t *Task) HasDescendant(id string) bool {
+	return findTaskIn(t.Subtasks, id) != nil
+}
+
+// LocateTask finds a task by ID and returns the slice holding it, its index
+// in that slice and the index of its category.
+func (p *Project) LocateTask(id string) (*[]Task, int, int, bool) {
+	for cIdx := range p.Categories {
+		if siblings, idx := locateTaskIn(&p.Categories[cIdx].Tasks, id); siblings != nil {
+			return siblings, idx, cIdx, true
+		}
+	}
+	return nil, -1, -1, false
+}
+
+func locateTaskIn(tasks *[]Task, id string) (*[]Task, int) {
+	for i := range *tasks {
+		if (*tasks)[i].ID == id {
+			return tasks, i
+		}
+		if siblings, idx := locateTaskIn(&(*tasks)[i].Subtasks, id); siblings != nil {
+			return siblings, idx
+		}
+	}
+	return nil, -1
+}
+
+// InsertTaskAt inserts task into tasks at index, appending when the index is
+// out of range.
+func InsertTaskAt(tasks *[]Task, index int, task Task) {
+	if index < 0 || index > len(*tasks) {
+		index = len(*tasks)
+	}
+	*tasks = append(*tasks, Task{})
+	copy((*tasks)[index+1:], (*tasks)[index:])
+	(*tasks)[index] = task
+}
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nestedCategory() Category {
+	return Category{Tasks: []Task{
+		{ID: "a", Status: StatusTodo, EstimateMinutes: 60, Subtasks: []Task{
+			{ID: "a1", Status: StatusCompleted, EstimateMinutes: 15},
+			{ID: "a2", Status: StatusTodo, Subtasks: []Task{
+				{ID: "a2x", Status: StatusCancelled, EstimateMinutes: 30},
+			}},
+		}},
+		{ID: "b", Status: StatusTodo},
+	}}
+}
+
+func TestTask_SubtaskProgress(t *testing.T) {
+	cat := nestedCategory()
+	done, total := cat.Tasks[0].SubtaskProgress()
+	assert.Equal(t, 2, done)
+	assert.Equal(t, 3, total)
+
+	done, total = cat.Tasks[1].SubtaskProgress()
+	assert.Equal(t, 0, done)
+	assert.Equal(t, 0, total)
+}
+
+func TestTask_TotalEstimateMinutes(t *testing.T) {
+	cat := nestedCategory()
+	assert.Equal(t, 105, cat.Tasks[0].TotalEstimateMinutes())
+	assert.Equal(t, 105, cat.TotalTaskEstimateMinutes())
+	assert.Equal(t, 5, cat.CountTasks())
+}
+
+func TestTask_InsertSubtask(t *testing.T) {
+	task := Task{Subtasks: []Task{{ID: "1"}, {ID: "3"}}}
+	task.InsertSubtask(1, Task{ID: "2"})
+	require.Len(t, task.Subtasks, 3)
+	assert.Equal(t, "2", task.Subtasks[1].ID)
+
+	task.InsertSubtask(-1, Task{ID: "4"})
+	assert.Equal(t, "4", task.Subtasks[3].ID)
+
+	require.NoError(t, task.RemoveSubtask(0))
+	assert.Equal(t, "2", task.Subtasks[0].ID)
+	assert.Error(t, task.RemoveSubtask(5))
+}
+
+func TestCategory_TaskAt(t *testing.T) {
+	cat := nestedCategory()
+
+	t.Run("resolves top-level task", func(t *testing.T) {
+		task := cat.TaskAt(1, nil)
+		require.NotNil(t, task)
+		assert.Equal(t, "b", task.ID)
+	})
+
+	t.Run("resolves nested task", func(t *testing.T) {
+		task := cat.TaskAt(0, []int{1, 0})
+		require.NotNil(t, task)
+		assert.Equal(t, "a2x", task.ID)
+	})
+
+	t.Run("returns nil for bad path", func(t *testing.T) {
+		assert.Nil(t, cat.TaskAt(0, []int{5}))
+		assert.Nil(t, cat.TaskAt(3, nil))
+	})
+
+	t.Run("siblings point into the tree", func(t *testing.T) {
+		siblings, idx := cat.SiblingsOf(0, []int{1})
+		require.NotNil(t, siblings)
+		assert.Equal(t, 1, idx)
+		(*siblings)[idx].Title = "changed"
+		assert.Equal(t, "changed", cat.Tasks[0].Subtasks[1].Title)
+	})
+}
+
+func TestProject_RemoveTaskByID(t *testing.T) {
+	project := Project{Categories: []Category{nestedCategory()}}
+
+	task, catIdx, ok := project.FindTask("a2x")
+	require.True(t, ok)
+	assert.Equal(t, 0, catIdx)
+	assert.Equal(t, StatusCancelled, task.Status)
+
+	removed, ok := project.RemoveTaskByID("a2")
+	require.True(t, ok)
+	assert.Equal(t, "a2", removed.ID)
+	assert.Len(t, removed.Subtasks, 1)
+	assert.Len(t, project.Categories[0].Tasks[0].Subtasks, 1)
+
+	_, _, ok = project.FindTask("a2x")
+	assert.False(t, ok)
+
+	_, ok = project.RemoveTaskByID("missing")
+	assert.False(t, ok)
+}
+
+func TestTask_Clone(t *testing.T) {
+	original := nestedCategory().Tasks[0]
+	clone := original.Clone()
+	clone.Subtasks[1].Subtasks[0].Title = "changed"
+	assert.Empty(t, original.Subtasks[1].Subtasks[0].Title)
+
+	require.NoError(t, clone.ReassignIDs())
+	assert.NotEqual(t, original.ID, clone.ID)
+	assert.NotEqual(t, original.Subtasks[1].Subtasks[0].ID, clone.Subtasks[1].Subtasks[0].ID)
+	assert.True(t, original.HasDescendant("a2x"))
+	assert.False(t, original.HasDescendant("a"))
+}
+
+func TestProject_Clone(t *testing.T) {
+	original := Project{Name: "P", Categories: []Category{nestedCategory()}}
+	clone := original.Clone()
+	clone.Categories[0].Name = "changed"
+	clone.Categories[0].Tasks[0].Subtasks[0].Title = "changed"
+	clone.Categories[0].Tasks = append(clone.Categories[0].Tasks, Task{ID: "extra"})
+
+	assert.NotEqual(t, "changed", original.Categories[0].Name)
+	assert.NotEqual(t, "changed", original.Categories[0].Tasks[0].Subtasks[0].Title)
+	assert.Len(t, original.Categories[0].Tasks, len(nestedCategory().Tasks))
+}
+
+func TestProject_LocateTask(t *testing.T) {
+	project := Project{Categories: []Category{{}, nestedCategory()}}
+
+	siblings, idx, catIdx, ok := project.LocateTask("a2")
+	require.True(t, ok)
+	assert.Equal(t, 1, idx)
+	assert.Equal(t, 1, catIdx)
+
+	InsertTaskAt(siblings, idx, Task{ID: "new"})
+	assert.Equal(t, "new", project.Categories[1].Tasks[0].Subtasks[1].ID)
+	assert.Equal(t, "a2", project.Categories[1].Tasks[0].Subtasks[2].ID)
+
+	_, _, _, ok = project.LocateTask("missing")
+	assert.False(t, ok)
+}
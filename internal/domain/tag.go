@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tagSuffixRe matches a trailing "#tag" token, mirroring how priorities and
+// deadlines are written as title suffixes. Tags start with a letter so that
+// issue references such as "#42" stay part of the title.
+var tagSuffixRe = regexp.MustCompile(`\s+#(\pL[^\s#]*)\s*$`)
+
+// NormalizeTag lowercases a tag and strips a leading '#'.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return errors.New("tag cannot be empty")
+	}
+	if strings.ContainsAny(tag, "# \t\n") {
+		return errors.New("tag cannot contain spaces or '#'")
+	}
+	if r, _ := utf8.DecodeRuneInString(tag); !unicode.IsLetter(r) {
+		return errors.New("tag must start with a letter")
+	}
+	return nil
+}
+
+// SetTags replaces the task's tags, normalizing and de-duplicating them.
+func (t *Task) SetTags(tags []string) error {
+	var normalized []string
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+		if !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	if slices.Equal(normalized, t.Tags) {
+		return nil
+	}
+	t.Tags = normalized
+	t.UpdatedAt = NowTimestamp()
+	return nil
+}
+
+func (t *Task) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, NormalizeTag(tag))
+}
+
+// HasTagInTree reports whether the task or any of its subtasks carries tag.
+func (t *Task) HasTagInTree(tag string) bool {
+	found := false
+	WalkTasks([]Task{*t}, func(task *Task, _ int) {
+		if task.HasTag(tag) {
+			found = true
+		}
+	})
+	return found
+}
+
+// ParseTitleTags splits trailing "#tag" tokens off an inline title, so that
+// "Fix login #frontend #urgent" yields "Fix login" and [frontend urgent].
+func ParseTitleTags(input string) (string, []string) {
+	title := strings.TrimSpace(input)
+	var tags []string
+	for {
+		m := tagSuffixRe.FindStringSubmatch(title)
+		if m == nil {
+			break
+		}
+		tags = append([]string{NormalizeTag(m[1])}, tags...)
+		title = strings.TrimSpace(tagSuffixRe.ReplaceAllString(title, ""))
+	}
+	return title, tags
+}
+
+// FormatTitleWithTags is the inverse of ParseTitleTags.
+func FormatTitleWithTags(title string, tags []string) string {
+	for _, tag := range tags {
+		title += " #" + tag
+	}
+	return title
+}
+
+// TagCounts counts how many tasks, subtasks included, carry each tag.
+func (p *Project) TagCounts() map[string]int {
+	counts := make(map[string]int)
+	for cIdx := range p.Categories {
+		WalkTasks(p.Categories[cIdx].Tasks, func(task *Task, _ int) {
+			for _, tag := range task.Tags {
+				counts[tag]++
+			}
+		})
+	}
+	return counts
+}
+
+// Tags lists every tag used in the project in alphabetical order.
+func (p *Project) Tags() []string {
+	counts := p.TagCounts()
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
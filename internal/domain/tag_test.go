@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTitleTags(t *testing.T) {
+	tests := []struct {
+		input string
+		title string
+		tags  []string
+	}{
+		{"Fix login", "Fix login", nil},
+		{"Fix login #frontend", "Fix login", []string{"frontend"}},
+		{"Fix login #Frontend #release-1.2 ", "Fix login", []string{"frontend", "release-1.2"}},
+		{"Issue #42 in parser", "Issue #42 in parser", nil},
+		{"#solo", "#solo", nil},
+		{"Fix bug #42", "Fix bug #42", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			title, tags := ParseTitleTags(tt.input)
+			assert.Equal(t, tt.title, title)
+			assert.Equal(t, tt.tags, tags)
+		})
+	}
+}
+
+func TestFormatTitleWithTags(t *testing.T) {
+	formatted := FormatTitleWithTags("Ship", []string{"a", "b"})
+	assert.Equal(t, "Ship #a #b", formatted)
+
+	title, tags := ParseTitleTags(formatted)
+	assert.Equal(t, "Ship", title)
+	assert.Equal(t, []string{"a", "b"}, tags)
+}
+
+func TestTask_SetTags(t *testing.T) {
+	task := Task{}
+	require.NoError(t, task.SetTags([]string{"#Frontend", "frontend", "api"}))
+	assert.Equal(t, []string{"frontend", "api"}, task.Tags)
+	assert.True(t, task.HasTag("#FRONTEND"))
+	assert.False(t, task.HasTag("backend"))
+
+	assert.Error(t, task.SetTags([]string{"two words"}))
+	assert.Error(t, task.SetTags([]string{"#"}))
+	assert.Error(t, task.SetTags([]string{"1.2"}))
+
+	require.NoError(t, task.SetTags(nil))
+	assert.Nil(t, task.Tags)
+}
+
+func TestProject_TagCounts(t *testing.T) {
+	p := Project{Categories: []Category{
+		{Tasks: []Task{
+			{Tags: []string{"ui"}, Subtasks: []Task{{Tags: []string{"ui", "api"}}}},
+		}},
+		{Tasks: []Task{{Tags: []string{"api"}}, {}}},
+	}}
+	assert.Equal(t, map[string]int{"ui": 2, "api": 2}, p.TagCounts())
+	assert.Equal(t, []string{"api", "ui"}, p.Tags())
+	assert.True(t, p.Categories[0].Tasks[0].HasTagInTree("api"))
+	assert.False(t, p.Categories[1].Tasks[1].HasTagInTree("api"))
+}
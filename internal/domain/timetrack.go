@@ -0,0 +1,222 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTimerRunning    = errors.New("timer already running")
+	ErrTimerNotRunning = errors.New("no timer running")
+)
+
+// TimeEntry is one tracked work interval. An empty End means the timer is
+// still running.
+type TimeEntry struct {
+	Start string `json:"start"`
+	End   string `json:"end,omitempty"`
+}
+
+func (e TimeEntry) IsRunning() bool {
+	return e.End == ""
+}
+
+// Bounds returns the entry's interval, ending at now while it is running.
+func (e TimeEntry) Bounds(now time.Time) (time.Time, time.Time, bool) {
+	start, err := time.Parse(time.RFC3339, e.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end := now
+	if !e.IsRunning() {
+		end, err = time.Parse(time.RFC3339, e.End)
+		if err != nil {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	if end.Before(start) {
+		return start, start, true
+	}
+	return start, end, true
+}
+
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	start, end, ok := e.Bounds(now)
+	if !ok {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+func (t *Task) IsTimerRunning() bool {
+	n := len(t.TimeEntries)
+	return n > 0 && t.TimeEntries[n-1].IsRunning()
+}
+
+// RunningSince returns when the task's running timer was started.
+func (t *Task) RunningSince() (time.Time, bool) {
+	if !t.IsTimerRunning() {
+		return time.Time{}, false
+	}
+	start, err := time.Parse(time.RFC3339, t.TimeEntries[len(t.TimeEntries)-1].Start)
+	return start, err == nil
+}
+
+func (t *Task) StartTimer(now time.Time) error {
+	if t.IsTimerRunning() {
+		return ErrTimerRunning
+	}
+	t.TimeEntries = append(t.TimeEntries, TimeEntry{Start: now.UTC().Format(time.RFC3339)})
+	t.UpdatedAt = NowTimestamp()
+	return nil
+}
+
+func (t *Task) StopTimer(now time.Time) error {
+	if !t.IsTimerRunning() {
+		return ErrTimerNotRunning
+	}
+	t.TimeEntries[len(t.TimeEntries)-1].End = now.UTC().Format(time.RFC3339)
+	t.UpdatedAt = NowTimestamp()
+	return nil
+}
+
+// TrackedDuration is the time logged on the task itself, counting a running
+// timer up to now.
+func (t *Task) TrackedDuration(now time.Time) time.Duration {
+	var total time.Duration
+	for _, entry := range t.TimeEntries {
+		total += entry.Duration(now)
+	}
+	return total
+}
+
+// TotalTrackedDuration adds the time logged on all subtasks.
+func (t *Task) TotalTrackedDuration(now time.Time) time.Duration {
+	total := t.TrackedDuration(now)
+	WalkTasks(t.Subtasks, func(sub *Task, _ int) {
+		total += sub.TrackedDuration(now)
+	})
+	return total
+}
+
+// TrackedBetween is the part of the task's own logged time that falls
+// within [since, now].
+func (t *Task) TrackedBetween(since, now time.Time) time.Duration {
+	var total time.Duration
+	for _, entry := range t.TimeEntries {
+		start, end, ok := entry.Bounds(now)
+		if !ok {
+			continue
+		}
+		if start.Before(since) {
+			start = since
+		}
+		if end.After(now) {
+			end = now
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
+
+// TrackedDuration sums the time logged on every task and subtask in the
+// category.
+func (c *Category) TrackedDuration(now time.Time) time.Duration {
+	var total time.Duration
+	WalkTasks(c.Tasks, func(task *Task, _ int) {
+		total += task.TrackedDuration(now)
+	})
+	return total
+}
+
+// RunningTimer returns the task whose timer is running, if any.
+func (p *Project) RunningTimer() *Task {
+	var running *Task
+	for cIdx := range p.Categories {
+		WalkTasks(p.Categories[cIdx].Tasks, func(task *Task, _ int) {
+			if running == nil && task.IsTimerRunning() {
+				running = task
+			}
+		})
+	}
+	return running
+}
+
+// StopTimers stops every running timer in the project and returns how many
+// were stopped.
+func (p *Project) StopTimers(now time.Time) int {
+	stopped := 0
+	for cIdx := range p.Categories {
+		WalkTasks(p.Categories[cIdx].Tasks, func(task *Task, _ int) {
+			if task.StopTimer(now) == nil {
+				stopped++
+			}
+		})
+	}
+	return stopped
+}
+
+// FormatDuration renders a tracked duration as e.g. "45m" or "2h05m".
+func FormatDuration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+// ClearTimeEntries drops the time logged on the task and its subtasks, used
+// when a task is duplicated rather than moved.
+func (t *Task) ClearTimeEntries() {
+	t.TimeEntries = nil
+	for i := range t.Subtasks {
+		t.Subtasks[i].ClearTimeEntries()
+	}
+}
+
+var sinceHoursRe = regexp.MustCompile(`^(\d+)h$`)
+
+// ParseSince turns the start of a reporting window into a time. Accepted
+// forms: YYYY-MM-DD, today, yesterday, periods back from today such as
+// "7d", "2w" or "1m", and hours back from now such as "2h".
+func ParseSince(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(strings.ToLower(input))
+	today := StartOfDay(now)
+	switch input {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if m := sinceHoursRe.FindStringSubmatch(input); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(-time.Duration(n) * time.Hour), nil
+	}
+	if m := relativeDateRe.FindStringSubmatch(input); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, err
+		}
+		switch m[2] {
+		case "d":
+			return today.AddDate(0, 0, -n), nil
+		case "w":
+			return today.AddDate(0, 0, -7*n), nil
+		case "m":
+			return today.AddDate(0, -n, 0), nil
+		}
+	}
+	if t, err := time.ParseInLocation(DateLayout, input, now.Location()); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %s (use YYYY-MM-DD, today, yesterday, 2h, 7d, 2w, 1m)", input)
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTask_Timer(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	task := Task{Title: "Write report"}
+
+	require.NoError(t, task.StartTimer(start))
+	assert.True(t, task.IsTimerRunning())
+	assert.ErrorIs(t, task.StartTimer(start), ErrTimerRunning)
+	assert.Equal(t, 30*time.Minute, task.TrackedDuration(start.Add(30*time.Minute)))
+
+	require.NoError(t, task.StopTimer(start.Add(45*time.Minute)))
+	assert.False(t, task.IsTimerRunning())
+	assert.ErrorIs(t, task.StopTimer(start), ErrTimerNotRunning)
+	assert.Equal(t, 45*time.Minute, task.TrackedDuration(start.Add(5*time.Hour)))
+}
+
+func TestTask_TrackedBetween(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	task := Task{TimeEntries: []TimeEntry{
+		{Start: day.Add(-2 * time.Hour).Format(time.RFC3339), End: day.Add(time.Hour).Format(time.RFC3339)},
+		{Start: day.Add(3 * time.Hour).Format(time.RFC3339)},
+	}}
+	now := day.Add(4 * time.Hour)
+
+	assert.Equal(t, 2*time.Hour, task.TrackedBetween(day, now))
+	assert.Equal(t, 4*time.Hour, task.TrackedDuration(now))
+}
+
+func TestProject_Timers(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	project := Project{Categories: []Category{{
+		Name: "Work",
+		Tasks: []Task{{
+			ID:       "parent",
+			Subtasks: []Task{{ID: "child"}},
+		}},
+	}}}
+
+	assert.Nil(t, project.RunningTimer())
+	child, _, _ := project.FindTask("child")
+	require.NoError(t, child.StartTimer(now))
+	assert.Equal(t, "child", project.RunningTimer().ID)
+	assert.Equal(t, time.Hour, project.Categories[0].Tasks[0].TotalTrackedDuration(now.Add(time.Hour)))
+	assert.Equal(t, time.Hour, project.Categories[0].TrackedDuration(now.Add(time.Hour)))
+
+	assert.Equal(t, 1, project.StopTimers(now.Add(time.Hour)))
+	assert.Nil(t, project.RunningTimer())
+}
+
+func TestSetStatus_StopsTimer(t *testing.T) {
+	task := Task{Status: StatusInProgress}
+	require.NoError(t, task.StartTimer(time.Now().Add(-time.Minute)))
+	require.NoError(t, task.SetStatus(StatusCompleted))
+	assert.False(t, task.IsTimerRunning())
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "0m", FormatDuration(20*time.Second))
+	assert.Equal(t, "45m", FormatDuration(45*time.Minute))
+	assert.Equal(t, "2h05m", FormatDuration(125*time.Minute))
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 18, 15, 0, 0, 0, time.UTC)
+	tests := map[string]string{
+		"today":      "2026-03-18",
+		"yesterday":  "2026-03-17",
+		"7d":         "2026-03-11",
+		"2w":         "2026-03-04",
+		"1m":         "2026-02-18",
+		"2026-01-05": "2026-01-05",
+	}
+	for input, expected := range tests {
+		since, err := ParseSince(input, now)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, since.Format(DateLayout), input)
+	}
+
+	since, err := ParseSince("2h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-2*time.Hour), since)
+
+	_, err = ParseSince("last tuesday", now)
+	assert.Error(t, err)
+}
@@ -17,17 +17,39 @@ const (
 	PriorityHigh   = "high"
 	PriorityMedium = "medium"
 	PriorityLow    = "low"
+
+	SectionCurrent = "current"
+	SectionFuture  = "future"
+	SectionPast    = "past"
 )
 
 var DefaultCategories = []string{"Feature", "Fix", "Ergonomy", "Documentation", "Research"}
 
-// Project is stored as a single JSON file.
+// Project is stored as a single JSON file. Revision counts the saves so a
+// writer holding an outdated copy can be detected. Archived projects are
+// left out of listings and the project picker unless asked for.
 type Project struct {
-	ID         string     `json:"id"`
-	Name       string     `json:"name"`
-	CreatedAt  string     `json:"created_at"`
-	UpdatedAt  string     `json:"updated_at"`
-	Categories []Category `json:"categories"`
+	// SchemaVersion is the layout of the file the project was stored in;
+	// the store sets it when saving.
+	SchemaVersion int        `json:"schema_version"`
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Revision      int        `json:"revision,omitempty"`
+	CreatedAt     string     `json:"created_at"`
+	UpdatedAt     string     `json:"updated_at"`
+	Archived      bool       `json:"archived,omitempty"`
+	Categories    []Category `json:"categories"`
+
+	// Archive holds finished tasks taken off the board, which listings and
+	// the task view leave out.
+	Archive []ArchivedTask `json:"archive,omitempty"`
+
+	// Encrypted projects are saved encrypted. The flag is kept by the file
+	// around the project, not in the project itself.
+	Encrypted bool `json:"-"`
+	// Sealed marks an encrypted project listed with only its ID and name;
+	// LoadProject returns it in full.
+	Sealed bool `json:"-"`
 }
 
 type Category struct {
@@ -40,14 +62,23 @@ type Category struct {
 }
 
 type Task struct {
-	ID              string `json:"id"`
-	Title           string `json:"title"`
-	Status          string `json:"status"`
-	CreatedAt       string `json:"created_at"`
-	UpdatedAt       string `json:"updated_at"`
-	Priority        string `json:"priority,omitempty"`
-	CompletionDate  string `json:"completion_date,omitempty"`
-	EstimateMinutes int    `json:"estimate_minutes,omitempty"`
+	ID              string      `json:"id"`
+	Title           string      `json:"title"`
+	Status          string      `json:"status"`
+	CreatedAt       string      `json:"created_at"`
+	UpdatedAt       string      `json:"updated_at"`
+	Priority        string      `json:"priority,omitempty"`
+	CompletionDate  string      `json:"completion_date,omitempty"`
+	EstimateMinutes int         `json:"estimate_minutes,omitempty"`
+	Description     string      `json:"description,omitempty"`
+	Notes           string      `json:"notes,omitempty"`
+	Deadline        string      `json:"deadline,omitempty"`
+	Section         string      `json:"section,omitempty"`
+	Subtasks        []Task      `json:"subtasks,omitempty"`
+	BlockedBy       []string    `json:"blocked_by,omitempty"`
+	Tags            []string    `json:"tags,omitempty"`
+	Recurrence      *Recurrence `json:"recurrence,omitempty"`
+	TimeEntries     []TimeEntry `json:"time_entries,omitempty"`
 }
 
 var EstimatePresets = []int{0, 15, 30, 60, 120, 240, 480, 960, 1440, 2400}
@@ -117,6 +148,7 @@ func NewTask(title string) (Task, error) {
 		ID:        id,
 		Title:     title,
 		Status:    StatusTodo,
+		Section:   SectionCurrent,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}, nil
@@ -140,20 +172,72 @@ func ValidatePriority(priority string) error {
 	}
 }
 
+func ValidateSection(section string) error {
+	switch section {
+	case "", SectionCurrent, SectionFuture, SectionPast:
+		return nil
+	default:
+		return errors.New("invalid section")
+	}
+}
+
 func (t *Task) SetStatus(status string) error {
 	if err := ValidateStatus(status); err != nil {
 		return err
 	}
 	t.Status = status
 	t.UpdatedAt = NowTimestamp()
+	if status == StatusCompleted || status == StatusCancelled {
+		_ = t.StopTimer(time.Now())
+	}
 	if status == StatusCompleted {
 		t.CompletionDate = NowTimestamp()
 	} else {
 		t.CompletionDate = ""
 	}
+	// Reopening a task pulls it back out of the past.
+	if !t.IsDone() && t.Section == SectionPast {
+		t.Section = SectionCurrent
+	}
 	return nil
 }
 
+// SectionName returns the task's section, treating an unset value as current.
+func (t *Task) SectionName() string {
+	if t.Section == "" {
+		return SectionCurrent
+	}
+	return t.Section
+}
+
+func (t *Task) SetSection(section string) error {
+	if err := ValidateSection(section); err != nil {
+		return err
+	}
+	if section == SectionPast && !t.IsDone() {
+		return errors.New("only completed or cancelled tasks can move to past")
+	}
+	t.Section = section
+	t.UpdatedAt = NowTimestamp()
+	return nil
+}
+
+// NextSection returns the section that follows the task's current one,
+// skipping past when the task is still open.
+func (t *Task) NextSection() string {
+	switch t.SectionName() {
+	case SectionCurrent:
+		return SectionFuture
+	case SectionFuture:
+		if t.IsDone() {
+			return SectionPast
+		}
+		return SectionCurrent
+	default:
+		return SectionCurrent
+	}
+}
+
 func (t *Task) SetPriority(priority string) error {
 	if err := ValidatePriority(priority); err != nil {
 		return err
@@ -202,6 +286,20 @@ func (t *Task) SetEstimate(minutes int) {
 	t.UpdatedAt = NowTimestamp()
 }
 
+func (t *Task) SetDescription(description string) {
+	t.Description = strings.TrimSpace(description)
+	t.UpdatedAt = NowTimestamp()
+}
+
+func (t *Task) SetNotes(notes string) {
+	t.Notes = strings.TrimSpace(notes)
+	t.UpdatedAt = NowTimestamp()
+}
+
+func (t *Task) IsDone() bool {
+	return t.Status == StatusCompleted || t.Status == StatusCancelled
+}
+
 func (t *Task) CycleStatus() bool {
 	var nextStatus string
 	switch t.Status {
@@ -223,6 +321,7 @@ func (t *Task) CycleStatus() bool {
 	return true
 }
 
+// AggregateStatus summarises the category's tasks, subtasks included.
 func (c *Category) AggregateStatus() string {
 	if len(c.Tasks) == 0 {
 		return ""
@@ -230,7 +329,7 @@ func (c *Category) AggregateStatus() string {
 	allDone := true
 	hasInProgress := false
 	hasCompleted := false
-	for _, t := range c.Tasks {
+	WalkTasks(c.Tasks, func(t *Task, _ int) {
 		if t.Status == StatusInProgress {
 			hasInProgress = true
 		}
@@ -239,7 +338,7 @@ func (c *Category) AggregateStatus() string {
 		} else {
 			allDone = false
 		}
-	}
+	})
 	if hasInProgress {
 		return StatusInProgress
 	}
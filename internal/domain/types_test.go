@@ -283,6 +283,22 @@ func TestCategory_AggregateStatus(t *testing.T) {
 		}}
 		assert.Equal(t, StatusInProgress, cat.AggregateStatus())
 	})
+
+	t.Run("counts open subtasks of a completed task", func(t *testing.T) {
+		cat := Category{Tasks: []Task{
+			{Status: StatusCompleted, Subtasks: []Task{{Status: StatusTodo}}},
+		}}
+		assert.Equal(t, StatusInProgress, cat.AggregateStatus())
+	})
+
+	t.Run("in_progress subtask returns in_progress", func(t *testing.T) {
+		cat := Category{Tasks: []Task{
+			{Status: StatusTodo, Subtasks: []Task{
+				{Status: StatusTodo, Subtasks: []Task{{Status: StatusInProgress}}},
+			}},
+		}}
+		assert.Equal(t, StatusInProgress, cat.AggregateStatus())
+	})
 }
 
 func TestTask_SetStatus(t *testing.T) {
@@ -313,6 +329,74 @@ func TestTask_SetStatus(t *testing.T) {
 		err := task.SetStatus("invalid")
 		assert.Error(t, err)
 	})
+
+	t.Run("reopening a past task moves it back to current", func(t *testing.T) {
+		task := Task{Status: StatusCompleted, Section: SectionPast}
+		err := task.SetStatus(StatusTodo)
+		require.NoError(t, err)
+		assert.Equal(t, SectionCurrent, task.Section)
+	})
+
+	t.Run("cancelling a past task keeps it in past", func(t *testing.T) {
+		task := Task{Status: StatusCompleted, Section: SectionPast}
+		err := task.SetStatus(StatusCancelled)
+		require.NoError(t, err)
+		assert.Equal(t, SectionPast, task.Section)
+	})
+
+	t.Run("does not touch future tasks", func(t *testing.T) {
+		task := Task{Status: StatusCompleted, Section: SectionFuture}
+		err := task.SetStatus(StatusInProgress)
+		require.NoError(t, err)
+		assert.Equal(t, SectionFuture, task.Section)
+	})
+}
+
+func TestTask_SetSection(t *testing.T) {
+	t.Run("moves open task to future", func(t *testing.T) {
+		task := Task{Status: StatusTodo}
+		err := task.SetSection(SectionFuture)
+		require.NoError(t, err)
+		assert.Equal(t, SectionFuture, task.Section)
+		assert.NotEmpty(t, task.UpdatedAt)
+	})
+
+	t.Run("rejects past for open task", func(t *testing.T) {
+		task := Task{Status: StatusInProgress, Section: SectionCurrent}
+		err := task.SetSection(SectionPast)
+		assert.Error(t, err)
+		assert.Equal(t, SectionCurrent, task.Section)
+	})
+
+	t.Run("allows past for done task", func(t *testing.T) {
+		task := Task{Status: StatusCancelled}
+		err := task.SetSection(SectionPast)
+		require.NoError(t, err)
+		assert.Equal(t, SectionPast, task.Section)
+	})
+
+	t.Run("returns error for invalid section", func(t *testing.T) {
+		task := Task{}
+		assert.Error(t, task.SetSection("someday"))
+	})
+}
+
+func TestTask_NextSection(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     Task
+		expected string
+	}{
+		{"unset counts as current", Task{Status: StatusTodo}, SectionFuture},
+		{"future open goes to current", Task{Status: StatusTodo, Section: SectionFuture}, SectionCurrent},
+		{"future done goes to past", Task{Status: StatusCompleted, Section: SectionFuture}, SectionPast},
+		{"past goes to current", Task{Status: StatusCompleted, Section: SectionPast}, SectionCurrent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.task.NextSection())
+		})
+	}
 }
 
 func TestTask_SetPriority(t *testing.T) {
@@ -330,3 +414,21 @@ func TestTask_SetPriority(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestTask_SetDescription(t *testing.T) {
+	t.Run("sets trimmed description and updates timestamp", func(t *testing.T) {
+		task := Task{}
+		task.SetDescription("  Steps to reproduce\n\n")
+		assert.Equal(t, "Steps to reproduce", task.Description)
+		assert.NotEmpty(t, task.UpdatedAt)
+	})
+}
+
+func TestTask_SetNotes(t *testing.T) {
+	t.Run("sets trimmed notes and updates timestamp", func(t *testing.T) {
+		task := Task{}
+		task.SetNotes("\nSee https://example.com\n")
+		assert.Equal(t, "See https://example.com", task.Notes)
+		assert.NotEmpty(t, task.UpdatedAt)
+	})
+}
@@ -13,10 +13,16 @@ import (
 var (
 	projectHeaderRe  = regexp.MustCompile(`^#\s+(.+)$`)
 	categoryHeaderRe = regexp.MustCompile(`^##\s+(.+)$`)
-	taskLineRe       = regexp.MustCompile(`^-\s+\[([ x\-~])\]\s+(.+)$`)
+	taskLineRe       = regexp.MustCompile(`^([ \t]*)-\s+\[([ x\-~])\]\s+(.+)$`)
 	prioritySuffixRe = regexp.MustCompile(`\s+\((high|medium|low)\)\s*$`)
+	dueSuffixRe      = regexp.MustCompile(`\s+\(due (\d{4}-\d{2}-\d{2})\)\s*$`)
+	noteLineRe       = regexp.MustCompile(`^>\s?(.*)$`)
 )
 
+// bodyIndent is the indentation of one nesting level: subtasks sit one
+// level below their parent and a task's body one level below its line.
+const bodyIndent = "  "
+
 func statusToMarker(status string) string {
 	switch status {
 	case domain.StatusCompleted:
@@ -47,17 +53,50 @@ func ExportCategoryMarkdown(cat domain.Category) string {
 	var sb strings.Builder
 	fmt.Fprintf(&sb, "## %s\n\n", cat.Name)
 	for _, task := range cat.Tasks {
-		marker := statusToMarker(task.Status)
-		line := fmt.Sprintf("- [%s] %s", marker, task.Title)
-		if task.Priority != "" {
-			line += fmt.Sprintf(" (%s)", task.Priority)
-		}
-		sb.WriteString(line)
-		sb.WriteByte('\n')
+		writeTask(&sb, task, "")
 	}
 	return sb.String()
 }
 
+func writeTask(sb *strings.Builder, task domain.Task, indent string) {
+	marker := statusToMarker(task.Status)
+	line := fmt.Sprintf("%s- [%s] %s", indent, marker, domain.FormatTitleWithTags(task.Title, task.Tags))
+	if task.Deadline != "" {
+		line += fmt.Sprintf(" (due %s)", task.Deadline)
+	}
+	if task.Priority != "" {
+		line += fmt.Sprintf(" (%s)", task.Priority)
+	}
+	sb.WriteString(line)
+	sb.WriteByte('\n')
+	writeTaskBody(sb, task, indent+bodyIndent)
+	for _, sub := range task.Subtasks {
+		writeTask(sb, sub, indent+bodyIndent)
+	}
+}
+
+// writeTaskBody writes the description as indented text under the task line,
+// followed by the notes as an indented blockquote.
+func writeTaskBody(sb *strings.Builder, task domain.Task, indent string) {
+	if task.Description != "" {
+		for _, line := range strings.Split(task.Description, "\n") {
+			if strings.TrimSpace(line) == "" {
+				sb.WriteString(strings.TrimRight(indent, " ") + "\n")
+				continue
+			}
+			sb.WriteString(indent + escapeQuote(line) + "\n")
+		}
+	}
+	if task.Notes != "" {
+		if task.Description != "" {
+			sb.WriteByte('\n')
+		}
+		for _, line := range strings.Split(task.Notes, "\n") {
+			sb.WriteString(strings.TrimRight(indent+"> "+line, " ") + "\n")
+		}
+	}
+}
+
 func ExportMarkdown(project domain.Project, w io.Writer) error {
 	if _, err := fmt.Fprintf(w, "# %s\n", project.Name); err != nil {
 		return err
@@ -76,10 +115,61 @@ func ImportMarkdown(r io.Reader, projectName string) (domain.Project, error) {
 	var parsedName string
 	var categories []categoryData
 	var currentCategory *categoryData
+	// openTasks holds the most recent task at each nesting depth.
+	var openTasks []*taskData
+	var pendingBlank int
 
 	for scanner.Scan() {
 		line := scanner.Text()
 
+		if m := taskLineRe.FindStringSubmatch(line); m != nil && currentCategory != nil {
+			depth := min(indentWidth(m[1])/len(bodyIndent), len(openTasks))
+			marker := m[2]
+			title := strings.TrimSpace(m[3])
+			var priority string
+			if pm := prioritySuffixRe.FindStringSubmatch(title); pm != nil {
+				priority = pm[1]
+				title = strings.TrimSpace(prioritySuffixRe.ReplaceAllString(title, ""))
+			}
+			var deadline string
+			if dm := dueSuffixRe.FindStringSubmatch(title); dm != nil {
+				deadline = dm[1]
+				title = strings.TrimSpace(dueSuffixRe.ReplaceAllString(title, ""))
+			}
+			title, tags := domain.ParseTitleTags(title)
+			td := taskData{
+				title:    title,
+				tags:     tags,
+				status:   markerToStatus(marker),
+				priority: priority,
+				deadline: deadline,
+			}
+			siblings := &currentCategory.tasks
+			if depth > 0 {
+				siblings = &openTasks[depth-1].subtasks
+			}
+			*siblings = append(*siblings, td)
+			openTasks = append(openTasks[:depth], &(*siblings)[len(*siblings)-1])
+			pendingBlank = 0
+			continue
+		}
+
+		if len(openTasks) > 0 {
+			if strings.TrimSpace(line) == "" {
+				pendingBlank++
+				continue
+			}
+			trimmed := strings.TrimLeft(line, " \t")
+			if level := indentWidth(line[:len(line)-len(trimmed)]) / len(bodyIndent); level > 0 {
+				owner := openTasks[min(level, len(openTasks))-1]
+				owner.addBodyLine(trimmed, pendingBlank)
+				pendingBlank = 0
+				continue
+			}
+			openTasks = nil
+			pendingBlank = 0
+		}
+
 		if m := projectHeaderRe.FindStringSubmatch(line); m != nil {
 			parsedName = strings.TrimSpace(m[1])
 			continue
@@ -92,22 +182,6 @@ func ImportMarkdown(r io.Reader, projectName string) (domain.Project, error) {
 			currentCategory = &categoryData{name: strings.TrimSpace(m[1])}
 			continue
 		}
-
-		if m := taskLineRe.FindStringSubmatch(line); m != nil && currentCategory != nil {
-			marker := m[1]
-			title := strings.TrimSpace(m[2])
-			var priority string
-			if pm := prioritySuffixRe.FindStringSubmatch(title); pm != nil {
-				priority = pm[1]
-				title = strings.TrimSpace(prioritySuffixRe.ReplaceAllString(title, ""))
-			}
-			currentCategory.tasks = append(currentCategory.tasks, taskData{
-				title:    title,
-				status:   markerToStatus(marker),
-				priority: priority,
-			})
-			continue
-		}
 	}
 	if err := scanner.Err(); err != nil {
 		return domain.Project{}, err
@@ -135,18 +209,10 @@ func ImportMarkdown(r io.Reader, projectName string) (domain.Project, error) {
 			return domain.Project{}, err
 		}
 		for _, td := range cd.tasks {
-			task, err := domain.NewTask(td.title)
+			task, err := td.build()
 			if err != nil {
 				return domain.Project{}, err
 			}
-			if err := task.SetStatus(td.status); err != nil {
-				return domain.Project{}, err
-			}
-			if td.priority != "" {
-				if err := task.SetPriority(td.priority); err != nil {
-					return domain.Project{}, err
-				}
-			}
 			cat.Tasks = append(cat.Tasks, task)
 		}
 		project.Categories = append(project.Categories, cat)
@@ -155,13 +221,99 @@ func ImportMarkdown(r io.Reader, projectName string) (domain.Project, error) {
 	return project, nil
 }
 
+// indentWidth measures leading whitespace, counting a tab as one level.
+func indentWidth(indent string) int {
+	width := 0
+	for _, r := range indent {
+		if r == '\t' {
+			width += len(bodyIndent)
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
 type categoryData struct {
 	name  string
 	tasks []taskData
 }
 
 type taskData struct {
-	title    string
-	status   string
-	priority string
+	title       string
+	status      string
+	priority    string
+	deadline    string
+	tags        []string
+	description []string
+	notes       []string
+	subtasks    []taskData
+}
+
+func (t *taskData) build() (domain.Task, error) {
+	task, err := domain.NewTask(t.title)
+	if err != nil {
+		return domain.Task{}, err
+	}
+	if err := task.SetStatus(t.status); err != nil {
+		return domain.Task{}, err
+	}
+	if t.priority != "" {
+		if err := task.SetPriority(t.priority); err != nil {
+			return domain.Task{}, err
+		}
+	}
+	if t.deadline != "" {
+		if err := task.SetDeadline(t.deadline); err != nil {
+			return domain.Task{}, err
+		}
+	}
+	if err := task.SetTags(t.tags); err != nil {
+		return domain.Task{}, err
+	}
+	task.Description = strings.TrimSpace(strings.Join(t.description, "\n"))
+	task.Notes = strings.TrimSpace(strings.Join(t.notes, "\n"))
+	for i := range t.subtasks {
+		sub, err := t.subtasks[i].build()
+		if err != nil {
+			return domain.Task{}, err
+		}
+		task.Subtasks = append(task.Subtasks, sub)
+	}
+	return task, nil
+}
+
+func (t *taskData) addBodyLine(line string, blanksBefore int) {
+	if m := noteLineRe.FindStringSubmatch(line); m != nil {
+		if len(t.notes) > 0 {
+			t.notes = appendBlankLines(t.notes, blanksBefore)
+		}
+		t.notes = append(t.notes, m[1])
+		return
+	}
+	if len(t.description) > 0 {
+		t.description = appendBlankLines(t.description, blanksBefore)
+	}
+	if strings.HasPrefix(line, `\`) && escapeQuote(line) != line {
+		line = line[1:]
+	}
+	t.description = append(t.description, line)
+}
+
+// escapeQuote puts a backslash in front of a description line starting
+// with ">", which would otherwise be read back as a note. Lines that already
+// have backslashes before the ">" get one more.
+func escapeQuote(line string) string {
+	text := strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(strings.TrimLeft(text, `\`), ">") {
+		return line
+	}
+	return line[:len(line)-len(text)] + `\` + text
+}
+
+func appendBlankLines(lines []string, count int) []string {
+	for i := 0; i < count; i++ {
+		lines = append(lines, "")
+	}
+	return lines
 }
@@ -296,8 +296,8 @@ func TestRoundTrip(t *testing.T) {
 				{
 					Name: "Feature",
 					Tasks: []domain.Task{
-						{Title: "Task A", Status: domain.StatusTodo, Priority: domain.PriorityHigh},
-						{Title: "Task B", Status: domain.StatusCompleted},
+						{Title: "Task A", Status: domain.StatusTodo, Priority: domain.PriorityHigh, Deadline: "2026-03-01"},
+						{Title: "Task B", Status: domain.StatusCompleted, Deadline: "2026-02-14"},
 						{Title: "Task C", Status: domain.StatusInProgress, Priority: domain.PriorityLow},
 					},
 				},
@@ -330,11 +330,191 @@ func TestRoundTrip(t *testing.T) {
 				assert.Equal(t, origTask.Title, impTask.Title)
 				assert.Equal(t, origTask.Status, impTask.Status)
 				assert.Equal(t, origTask.Priority, impTask.Priority)
+				assert.Equal(t, origTask.Deadline, impTask.Deadline)
 			}
 		}
 	})
 }
 
+func TestTaskBody(t *testing.T) {
+	t.Run("exports description and notes as indented body", func(t *testing.T) {
+		cat := domain.Category{
+			Name: "Fix",
+			Tasks: []domain.Task{
+				{
+					Title:       "Login bug",
+					Status:      domain.StatusTodo,
+					Description: "Steps:\n\n1. open app",
+					Notes:       "See #42",
+				},
+				{Title: "Other", Status: domain.StatusTodo},
+			},
+		}
+
+		output := ExportCategoryMarkdown(cat)
+		expected := "## Fix\n\n" +
+			"- [ ] Login bug\n" +
+			"  Steps:\n" +
+			"\n" +
+			"  1. open app\n" +
+			"\n" +
+			"  > See #42\n" +
+			"- [ ] Other\n"
+		assert.Equal(t, expected, output)
+	})
+
+	t.Run("imports indented body text", func(t *testing.T) {
+		md := `# Test
+
+## Fix
+
+- [ ] Login bug (high)
+  Steps:
+
+  1. open app
+
+  > See #42
+  >
+  > Second note
+
+- [x] Other
+`
+		project, err := ImportMarkdown(strings.NewReader(md), "")
+		require.NoError(t, err)
+
+		tasks := project.Categories[0].Tasks
+		require.Len(t, tasks, 2)
+		assert.Equal(t, "Login bug", tasks[0].Title)
+		assert.Equal(t, domain.PriorityHigh, tasks[0].Priority)
+		assert.Equal(t, "Steps:\n\n1. open app", tasks[0].Description)
+		assert.Equal(t, "See #42\n\nSecond note", tasks[0].Notes)
+		assert.Empty(t, tasks[1].Description)
+		assert.Empty(t, tasks[1].Notes)
+	})
+
+	t.Run("round trip preserves description and notes", func(t *testing.T) {
+		original := domain.Project{
+			Name: "Body",
+			Categories: []domain.Category{{
+				Name: "Feature",
+				Tasks: []domain.Task{
+					{Title: "A", Status: domain.StatusTodo, Description: "Line 1\nLine 2", Notes: "Note"},
+					{Title: "B", Status: domain.StatusCompleted, Notes: "Only notes"},
+					{Title: "C", Status: domain.StatusTodo, Description: "Only description"},
+					{Title: "D", Status: domain.StatusTodo, Description: "> quoted\n\\> escaped", Notes: "Note"},
+				},
+			}},
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, ExportMarkdown(original, &buf))
+		imported, err := ImportMarkdown(&buf, "")
+		require.NoError(t, err)
+
+		require.Len(t, imported.Categories[0].Tasks, 4)
+		for i, task := range original.Categories[0].Tasks {
+			assert.Equal(t, task.Description, imported.Categories[0].Tasks[i].Description)
+			assert.Equal(t, task.Notes, imported.Categories[0].Tasks[i].Notes)
+		}
+	})
+}
+
+func TestSubtasks(t *testing.T) {
+	t.Run("exports subtasks as nested list items", func(t *testing.T) {
+		cat := domain.Category{
+			Name: "Release",
+			Tasks: []domain.Task{{
+				Title:  "Ship",
+				Status: domain.StatusTodo,
+				Subtasks: []domain.Task{
+					{Title: "Write notes", Status: domain.StatusCompleted, Description: "Draft"},
+					{Title: "Tag", Status: domain.StatusTodo, Subtasks: []domain.Task{
+						{Title: "Push tag", Status: domain.StatusTodo, Priority: domain.PriorityHigh},
+					}},
+				},
+			}},
+		}
+
+		output := ExportCategoryMarkdown(cat)
+		expected := "## Release\n\n" +
+			"- [ ] Ship\n" +
+			"  - [x] Write notes\n" +
+			"    Draft\n" +
+			"  - [ ] Tag\n" +
+			"    - [ ] Push tag (high)\n"
+		assert.Equal(t, expected, output)
+	})
+
+	t.Run("imports nested list items as subtasks", func(t *testing.T) {
+		md := `## Release
+
+- [ ] Ship
+  Parent body
+  - [x] Write notes
+    Draft
+  - [ ] Tag
+    - [ ] Push tag
+- [ ] Announce
+`
+		project, err := ImportMarkdown(strings.NewReader(md), "")
+		require.NoError(t, err)
+
+		tasks := project.Categories[0].Tasks
+		require.Len(t, tasks, 2)
+		assert.Equal(t, "Parent body", tasks[0].Description)
+		require.Len(t, tasks[0].Subtasks, 2)
+		assert.Equal(t, "Write notes", tasks[0].Subtasks[0].Title)
+		assert.Equal(t, domain.StatusCompleted, tasks[0].Subtasks[0].Status)
+		assert.Equal(t, "Draft", tasks[0].Subtasks[0].Description)
+		require.Len(t, tasks[0].Subtasks[1].Subtasks, 1)
+		assert.Equal(t, "Push tag", tasks[0].Subtasks[1].Subtasks[0].Title)
+		assert.Equal(t, "Announce", tasks[1].Title)
+		assert.Empty(t, tasks[1].Subtasks)
+	})
+
+	t.Run("clamps over-indented items to one level below the previous task", func(t *testing.T) {
+		md := "## A\n\n- [ ] Parent\n      - [ ] Deep\n"
+		project, err := ImportMarkdown(strings.NewReader(md), "")
+		require.NoError(t, err)
+
+		tasks := project.Categories[0].Tasks
+		require.Len(t, tasks, 1)
+		require.Len(t, tasks[0].Subtasks, 1)
+		assert.Equal(t, "Deep", tasks[0].Subtasks[0].Title)
+	})
+}
+
+func TestTags(t *testing.T) {
+	t.Run("exports tags before deadline and priority", func(t *testing.T) {
+		cat := domain.Category{
+			Name: "Web",
+			Tasks: []domain.Task{{
+				Title:    "Fix header",
+				Status:   domain.StatusTodo,
+				Priority: domain.PriorityLow,
+				Deadline: "2026-03-01",
+				Tags:     []string{"frontend", "release-1.2"},
+			}},
+		}
+		output := ExportCategoryMarkdown(cat)
+		assert.Contains(t, output, "- [ ] Fix header #frontend #release-1.2 (due 2026-03-01) (low)\n")
+	})
+
+	t.Run("round trip preserves tags", func(t *testing.T) {
+		md := "## Web\n\n- [ ] Fix header #frontend (due 2026-03-01) (low)\n- [ ] Close #42\n"
+		project, err := ImportMarkdown(strings.NewReader(md), "")
+		require.NoError(t, err)
+
+		tasks := project.Categories[0].Tasks
+		require.Len(t, tasks, 2)
+		assert.Equal(t, "Fix header", tasks[0].Title)
+		assert.Equal(t, []string{"frontend"}, tasks[0].Tags)
+		assert.Equal(t, "2026-03-01", tasks[0].Deadline)
+		assert.Equal(t, "Close #42", tasks[1].Title)
+		assert.Empty(t, tasks[1].Tags)
+	})
+}
+
 func TestStatusToMarker(t *testing.T) {
 	tests := []struct {
 		status   string
@@ -0,0 +1,22 @@
+package fsutil
+
+import "os"
+
+// Lock takes an exclusive advisory lock on path, creating the file if
+// needed, and blocks until the lock is available. Other phasionary
+// processes honour the lock; it does not stop unrelated programs from
+// touching the file. Call the returned function to release it.
+func Lock(path string) (func(), error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
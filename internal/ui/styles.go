@@ -16,6 +16,12 @@ var (
 	DialogTitleStyle = lipgloss.NewStyle().Bold(true)
 	DialogHintStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
 	SuccessStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	OverdueStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("1"))
+	DueSoonStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	BlockedColor     = lipgloss.Color("5")
+	BlockedStyle     = lipgloss.NewStyle().Foreground(BlockedColor)
+	TagColor         = lipgloss.Color("6")
+	TagStyle         = lipgloss.NewStyle().Foreground(TagColor)
 )
 
 func StatusStyle(status string) lipgloss.Style {
@@ -87,6 +93,29 @@ func SelectedStatusStyle(status string) lipgloss.Style {
 	}
 }
 
+func DeadlineStyle(overdue, dueSoon bool) lipgloss.Style {
+	switch {
+	case overdue:
+		return OverdueStyle
+	case dueSoon:
+		return DueSoonStyle
+	default:
+		return MutedStyle
+	}
+}
+
+func GetSelectedDeadlineStyle(overdue, dueSoon, focused bool) lipgloss.Style {
+	base := GetSelectedStyle(focused)
+	switch {
+	case overdue:
+		return base.Foreground(lipgloss.Color("1"))
+	case dueSoon:
+		return base.Foreground(lipgloss.Color("3"))
+	default:
+		return base
+	}
+}
+
 // Unfocused selection style (underline, no background - works in light/dark modes)
 var UnfocusedSelectedStyle = lipgloss.NewStyle().Bold(true).Underline(true)
 